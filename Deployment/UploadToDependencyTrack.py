@@ -293,7 +293,11 @@ def extract_metadata_from_bom(bom_file: Path) -> Dict:
         local_ips = [prop["value"] for prop in component.get("properties", []) 
                     if prop.get("name") == "local_ip"]
         
+        scan_ids = [prop["value"] for prop in metadata.get("properties", [])
+                    if prop.get("name") == "scan_id"]
+        
         return {
+            "scan_id": scan_ids[0] if scan_ids else "",
             "hostname": component.get("name", "unknown"),
             "os": properties.get("os", "unknown"),
             "os_version": properties.get("os_version", "unknown"),
@@ -403,7 +407,14 @@ def get_most_recent_scan_files(scans_dir: Path) -> List[Path]:
     # Find the most recent timestamp
     most_recent_timestamp = max(file_timestamps.values())
     
-    # Get all files matching the most recent timestamp
+    # BOMs from one scan share its scan ID, so collect the newest BOM's siblings
+    newest = next(f for f, ts in file_timestamps.items() if ts == most_recent_timestamp)
+    scan_id = extract_metadata_from_bom(newest).get("scan_id")
+    if scan_id:
+        return [f for f in file_timestamps
+                if extract_metadata_from_bom(f).get("scan_id") == scan_id]
+    
+    # Older BOMs have no scan ID: get all files matching the most recent timestamp
     recent_files = [f for f, ts in file_timestamps.items() if ts == most_recent_timestamp]
     
     return recent_files
@@ -510,6 +521,8 @@ def main():
         print(f"   OS: {metadata.get('os', 'unknown')} {metadata.get('os_version', 'unknown')}")
         print(f"   User: {metadata.get('logged_in_user', 'unknown')}")
         print(f"   Scan Time: {metadata.get('timestamp', 'unknown')}")
+        if metadata.get('scan_id'):
+            print(f"   Scan ID: {metadata['scan_id']}")
         
         # Step 1: Create parent project for the hostname
         print("\n" + "-" * 80)
@@ -70,6 +70,7 @@ type Project struct {
 }
 
 type BOMMetadata struct {
+	ScanID         string
 	Hostname       string
 	OS             string
 	OSVersion      string
@@ -398,6 +399,14 @@ func ExtractMetadata(bomFile string) (*BOMMetadata, error) {
 		if timestamp, ok := meta["timestamp"].(string); ok {
 			metadata.Timestamp = timestamp
 		}
+
+		if props, ok := meta["properties"].([]interface{}); ok {
+			for _, p := range props {
+				if prop, ok := p.(map[string]interface{}); ok && prop["name"] == "scan_id" {
+					metadata.ScanID, _ = prop["value"].(string)
+				}
+			}
+		}
 	}
 
 	if components, ok := bom["components"].([]interface{}); ok {
@@ -501,7 +510,26 @@ func getMostRecentScanFiles(scansDir string) ([]string, error) {
 		}
 	}
 
-	// Get all files matching the most recent timestamp
+	// BOMs from one scan share its scan ID, so collect the newest BOM's siblings
+	for file, ts := range fileTimestamps {
+		if !ts.Equal(mostRecentTime) {
+			continue
+		}
+		newest, err := ExtractMetadata(file)
+		if err != nil || newest.ScanID == "" {
+			break
+		}
+
+		var sessionFiles []string
+		for other := range fileTimestamps {
+			if metadata, err := ExtractMetadata(other); err == nil && metadata.ScanID == newest.ScanID {
+				sessionFiles = append(sessionFiles, other)
+			}
+		}
+		return sessionFiles, nil
+	}
+
+	// Older BOMs have no scan ID: get all files matching the most recent timestamp
 	var recentFiles []string
 	for file, ts := range fileTimestamps {
 		if ts.Equal(mostRecentTime) {
@@ -664,6 +692,9 @@ func main() {
 		fmt.Printf("   OS: %s %s\n", firstMetadata.OS, firstMetadata.OSVersion)
 		fmt.Printf("   User: %s\n", firstMetadata.LoggedInUser)
 		fmt.Printf("   Scan Time: %s\n", firstMetadata.Timestamp)
+		if firstMetadata.ScanID != "" {
+			fmt.Printf("   Scan ID: %s\n", firstMetadata.ScanID)
+		}
 
 		// Create parent project
 		fmt.Println("\n" + strings.Repeat("-", 80))
@@ -1,43 +1,57 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
-	"github.com/spf13/cobra"
 	"github.com/eapolsniper/endpointbom/internal/archive"
 	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/redact"
 	"github.com/eapolsniper/endpointbom/internal/sbom"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
 	"github.com/eapolsniper/endpointbom/internal/scanners/applications"
 	"github.com/eapolsniper/endpointbom/internal/scanners/browsers"
 	"github.com/eapolsniper/endpointbom/internal/scanners/historical"
 	"github.com/eapolsniper/endpointbom/internal/scanners/ides"
+	"github.com/eapolsniper/endpointbom/internal/scanners/mcp"
+	"github.com/eapolsniper/endpointbom/internal/scanners/ospackages"
 	"github.com/eapolsniper/endpointbom/internal/scanners/packagemanagers"
 	"github.com/eapolsniper/endpointbom/internal/security"
+	"github.com/eapolsniper/endpointbom/internal/session"
 	"github.com/eapolsniper/endpointbom/internal/system"
 	"github.com/eapolsniper/endpointbom/internal/version"
+	"github.com/spf13/cobra"
 )
 
 var (
-	cfgFile            string
-	outputDir          string
-	debug              bool
-	verbose            bool
-	requireAdmin       bool
-	scanAllUsers       bool
-	excludePaths       []string
-	disabledScanners   []string
-	enabledScanners    []string
-	disablePublicIP    bool
-	fetchPublicIP      bool
-	enableAll          bool // Enable all optional features (browser extensions, public IP)
-	historicalDays     int
-	noHistorical       bool
-	noRawLogs          bool
-	noZip              bool
-	showVersion        bool
+	cfgFile             string
+	outputDir           string
+	outputFormat        string
+	singleBOM           bool
+	deterministicSerial bool
+	debug               bool
+	verbose             bool
+	requireAdmin        bool
+	scanAllUsers        bool
+	excludePaths        []string
+	disabledScanners    []string
+	enabledScanners     []string
+	disablePublicIP     bool
+	fetchPublicIP       bool
+	enableAll           bool // Enable all optional features (browser extensions, public IP)
+	historicalDays      int
+	noHistorical        bool
+	noHashes            bool
+	noRawLogs           bool
+	noZip               bool
+	showVersion         bool
+	maxParallel         int
+	scannerTimeout      int
 )
 
 var rootCmd = &cobra.Command{
@@ -50,10 +64,11 @@ The tool scans:
   - Package managers (npm, pip, yarn, brew, gem, cargo, composer, chocolatey, etc.)
   - Installed applications (all non-OS applications)
   - IDE extensions and plugins (VSCode, Cursor, JetBrains, Sublime, etc.)
-  - MCP servers configured in supported IDEs
+  - MCP servers configured for Claude Desktop, Cursor, VSCode, Windsurf, Zed, Continue and Cline
 
 Security features:
   - No secrets or environment variables are collected
+  - Tokens and credentials in collected values are redacted before output
   - Uses minimal, trusted dependencies
   - Dependency pinning for security
 
@@ -67,6 +82,9 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./endpointbom.yaml)")
 	rootCmd.PersistentFlags().StringVar(&outputDir, "output", "", "output directory for SBOM files (default is ./scans relative to executable)")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", sbom.DefaultFormat, "SBOM output format ("+strings.Join(sbom.Formats(), ", ")+")")
+	rootCmd.PersistentFlags().BoolVar(&singleBOM, "single-bom", false, "write one consolidated SBOM per endpoint instead of one per category")
+	rootCmd.PersistentFlags().BoolVar(&deterministicSerial, "deterministic-serial", false, "derive SBOM serial numbers from their content so unchanged scans keep the same serial")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug output")
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "enable verbose output")
 	rootCmd.PersistentFlags().BoolVar(&requireAdmin, "require-admin", false, "require admin/root privileges (fail if not admin)")
@@ -78,7 +96,10 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&enableAll, "all", false, "enable all optional features (browser extensions, public IP lookup)")
 	rootCmd.PersistentFlags().IntVar(&historicalDays, "historical-days", 30, "days to look back for historical package installations")
 	rootCmd.PersistentFlags().BoolVar(&noHistorical, "no-historical", false, "disable historical package tracking")
-	
+	rootCmd.PersistentFlags().BoolVar(&noHashes, "no-hashes", false, "don't compute SHA-256 hashes of application executables and extensions")
+	rootCmd.PersistentFlags().IntVar(&maxParallel, "parallel", 4, "maximum number of scanners to run concurrently")
+	rootCmd.PersistentFlags().IntVar(&scannerTimeout, "scanner-timeout", 300, "per-scanner timeout in seconds (0 disables the timeout)")
+
 	// Deprecated flag - hidden from help but still functional for backward compatibility
 	rootCmd.PersistentFlags().BoolVar(&disablePublicIP, "disable-public-ip", false, "deprecated: use --fetch-public-ip instead")
 	rootCmd.PersistentFlags().MarkHidden("disable-public-ip")
@@ -109,7 +130,7 @@ func runScan(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("invalid config file path: %w", err)
 		}
 	}
-	
+
 	// Load configuration
 	cfg, err := config.LoadFromFile(validatedCfgFile)
 	if err != nil {
@@ -127,14 +148,28 @@ func runScan(cmd *cobra.Command, args []string) error {
 		}
 		cfg.OutputDir = defaultOutput
 	}
-	
+
 	// Validate output directory
 	validatedOutput, err := security.ValidateOutputDirectory(cfg.OutputDir)
 	if err != nil {
 		return fmt.Errorf("invalid output directory: %w", err)
 	}
 	cfg.OutputDir = validatedOutput
-	
+
+	// Validate the output format before spending time scanning
+	if cmd.Flags().Changed("format") {
+		cfg.OutputFormat = outputFormat
+	}
+	if _, err := sbom.NewWriter(cfg.OutputFormat); err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("single-bom") {
+		cfg.SingleBOM = singleBOM
+	}
+	if cmd.Flags().Changed("deterministic-serial") {
+		cfg.DeterministicSerial = deterministicSerial
+	}
+
 	// Override other config settings with CLI flags
 	if cmd.Flags().Changed("debug") {
 		cfg.Debug = debug
@@ -168,7 +203,7 @@ func runScan(cmd *cobra.Command, args []string) error {
 	if cmd.Flags().Changed("enable") {
 		// Expand shorthand groups
 		expandedScanners := expandScannerGroups(enabledScanners)
-		
+
 		// Remove enabled scanners from the disabled list
 		for _, enableScanner := range expandedScanners {
 			cfg.DisabledScanners = removeFromSlice(cfg.DisabledScanners, enableScanner)
@@ -186,16 +221,25 @@ func runScan(cmd *cobra.Command, args []string) error {
 	if cmd.Flags().Changed("no-historical") {
 		cfg.IncludeHistorical = !noHistorical
 	}
+	if cmd.Flags().Changed("no-hashes") {
+		cfg.DisableHashing = noHashes
+	}
 	if cmd.Flags().Changed("no-raw-logs") {
 		cfg.IncludeRawLogs = !noRawLogs
 	}
 	if cmd.Flags().Changed("no-zip") {
 		cfg.CreateZipArchive = !noZip
 	}
+	if cmd.Flags().Changed("parallel") {
+		cfg.MaxParallelScanners = maxParallel
+	}
+	if cmd.Flags().Changed("scanner-timeout") {
+		cfg.ScannerTimeoutSeconds = scannerTimeout
+	}
 
 	// Check admin privileges and adjust behavior accordingly
 	isAdmin := system.IsAdmin()
-	
+
 	if cfg.RequireAdmin && !isAdmin {
 		return fmt.Errorf("this tool requires administrator/root privileges. Please run with sudo or as administrator")
 	}
@@ -239,11 +283,18 @@ func runScan(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Output directory: %s\n", cfg.OutputDir)
 	}
 
+	// One session names and ties together everything this scan writes
+	scan := session.New(sysInfo.Hostname, sysInfo.Timestamp)
+	if cfg.Verbose {
+		fmt.Printf("Scan ID: %s\n", scan.ID)
+	}
+
 	// Initialize scanners
-	allScanners := []scanners.Scanner{
+	allScanners := []scanners.ContextScanner{
 		// Package managers (global)
 		&packagemanagers.NPMScanner{},
 		&packagemanagers.PipScanner{},
+		&packagemanagers.CondaScanner{},
 		&packagemanagers.YarnScanner{},
 		&packagemanagers.PnpmScanner{},
 		&packagemanagers.BrewScanner{},
@@ -257,6 +308,17 @@ func runScan(cmd *cobra.Command, args []string) error {
 		&packagemanagers.NPMLocalScanner{},
 		&packagemanagers.PipLocalScanner{},
 		&packagemanagers.GemLocalScanner{},
+		&packagemanagers.GoLocalScanner{},
+		&packagemanagers.CargoLocalScanner{},
+		&packagemanagers.ComposerLocalScanner{},
+
+		// OS packages (Linux)
+		&ospackages.DpkgScanner{},
+		&ospackages.RpmScanner{},
+		&ospackages.ApkScanner{},
+		&ospackages.PacmanScanner{},
+		&ospackages.SnapScanner{},
+		&ospackages.FlatpakScanner{},
 
 		// Applications
 		&applications.ApplicationScanner{},
@@ -267,6 +329,9 @@ func runScan(cmd *cobra.Command, args []string) error {
 		&ides.JetBrainsScanner{},
 		&ides.SublimeScanner{},
 
+		// MCP servers (AI clients, editors and project configs)
+		&mcp.MCPScanner{},
+
 		// Browser Extensions
 		&browsers.ChromeScanner{},
 		&browsers.FirefoxScanner{},
@@ -276,6 +341,14 @@ func runScan(cmd *cobra.Command, args []string) error {
 		// Historical tracking (best-effort from logs)
 		&historical.NPMHistoricalScanner{},
 		&historical.BrewHistoricalScanner{},
+		&historical.PipHistoricalScanner{},
+		&historical.YarnHistoricalScanner{},
+		&historical.PnpmHistoricalScanner{},
+		&historical.GemHistoricalScanner{},
+		&historical.CargoHistoricalScanner{},
+		&historical.DpkgHistoricalScanner{},
+		&historical.AptHistoricalScanner{},
+		&historical.DnfHistoricalScanner{},
 	}
 
 	// Run all scanners - separate current from historical
@@ -288,30 +361,54 @@ func runScan(cmd *cobra.Command, args []string) error {
 
 	var historicalComponents []scanners.Component
 
+	// Filter out disabled scanners before handing the rest to the worker pool,
+	// recording them so the SBOM shows they were never run
+	var enabled []scanners.ContextScanner
+	records := make(map[string]scanners.ScannerRecord)
 	for _, scanner := range allScanners {
 		if cfg.IsScannerDisabled(scanner.Name()) {
 			if cfg.Verbose {
 				fmt.Printf("Skipping disabled scanner: %s\n", scanner.Name())
 			}
+			records[scanner.Name()] = scanners.DisabledRecord(scanner.Name())
 			continue
 		}
+		enabled = append(enabled, scanner)
+	}
 
-		fmt.Printf("Running scanner: %s\n", scanner.Name())
-		components, err := scanner.Scan(cfg)
-		if err != nil {
+	// Cancel in-flight scanners (and their child processes) on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Running %d scanners (up to %d in parallel)\n", len(enabled), cfg.MaxParallelScanners)
+	runResults := scanners.RunScanners(ctx, cfg, enabled)
+	if ctx.Err() != nil {
+		return fmt.Errorf("scan interrupted: %w", ctx.Err())
+	}
+
+	for _, run := range runResults {
+		scanner := run.Scanner
+		records[scanner.Name()] = scanners.NewScannerRecord(run)
+
+		if run.TimedOut {
+			fmt.Printf("Scanner %s %v\n", scanner.Name(), run.Err)
+			continue
+		}
+		if run.Err != nil {
 			if cfg.Debug {
-				fmt.Printf("Scanner %s error: %v\n", scanner.Name(), err)
+				fmt.Printf("Scanner %s error: %v\n", scanner.Name(), run.Err)
 			}
 			continue
 		}
 
+		components := run.Components
 		if cfg.Verbose {
-			fmt.Printf("  Found %d components\n", len(components))
+			fmt.Printf("  %s: found %d components in %s\n", scanner.Name(), len(components), run.Duration.Round(time.Millisecond))
 		}
 
 		// Separate historical scanners from current scanners
 		isHistorical := strings.HasSuffix(scanner.Name(), "-historical")
-		
+
 		if isHistorical {
 			// Store historical components for later deduplication
 			historicalComponents = append(historicalComponents, components...)
@@ -343,7 +440,7 @@ func runScan(cmd *cobra.Command, args []string) error {
 					result.PackageManagers = append(result.PackageManagers, comp)
 				case "browser-extension":
 					result.BrowserExtensions = append(result.BrowserExtensions, comp)
-				case "ide-extension", "mcp-server":
+				case "ide-extension", "mcp-server", "service":
 					result.IDEExtensions = append(result.IDEExtensions, comp)
 				default:
 					result.Applications = append(result.Applications, comp)
@@ -352,25 +449,67 @@ func runScan(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Keep provenance records in the same order the scanners are registered
+	statusCounts := make(map[string]int)
+	for _, scanner := range allScanners {
+		record := records[scanner.Name()]
+		result.Scanners = append(result.Scanners, record)
+		statusCounts[record.Status]++
+	}
+
 	// Deduplicate historical components
 	// Only add historical components that are NOT currently installed
 	currentPackages := buildPackageSet(result.PackageManagers)
-	
+	historicalPackages := make(map[string]int) // key -> index in result.PackageManagers
+
 	for _, histComp := range historicalComponents {
 		key := fmt.Sprintf("%s:%s:%s", histComp.Name, histComp.Version, histComp.PackageManager)
-		
-		// If this package@version is NOT currently installed, add it as historical
-		if !currentPackages[key] {
-			if histComp.Properties == nil {
-				histComp.Properties = make(map[string]string)
+
+		// Keep every event, marking the ones whose version is still installed
+		for _, event := range histComp.Events {
+			_, event.Installed = currentPackages[fmt.Sprintf("%s:%s:%s", event.Name, event.Version, event.PackageManager)]
+			result.Events = append(result.Events, event)
+		}
+		histComp.Events = nil
+
+		// If it IS currently installed, we already have it from the current scan;
+		// keep the install date the history recorded for it
+		if i, installed := currentPackages[key]; installed {
+			current := result.PackageManagers[i]
+			if _, exists := current.Properties["install_date"]; !exists && histComp.Properties["install_date"] != "" {
+				current.Properties["install_date"] = histComp.Properties["install_date"]
 			}
-			// Override install_type to "historical" (not currently installed)
-			histComp.Properties["install_type"] = "historical"
-			
-			result.PackageManagers = append(result.PackageManagers, histComp)
+			continue
+		}
+
+		// Several logs can report the same package (dpkg.log and apt's history);
+		// merge what each adds into one entry
+		if i, seen := historicalPackages[key]; seen {
+			merged := result.PackageManagers[i]
+			for property, value := range histComp.Properties {
+				if _, exists := merged.Properties[property]; !exists {
+					merged.Properties[property] = value
+				}
+			}
+			continue
+		}
+
+		// If this package@version is NOT currently installed, add it as historical
+		if histComp.Properties == nil {
+			histComp.Properties = make(map[string]string)
 		}
-		// If it IS currently installed, we already have it from the current scan
+		// Override install_type to "historical" (not currently installed)
+		histComp.Properties["install_type"] = "historical"
+
+		historicalPackages[key] = len(result.PackageManagers)
+		result.PackageManagers = append(result.PackageManagers, histComp)
 	}
+	scanners.SortEvents(result.Events)
+
+	// Remove any secrets the scanners picked up before anything is written
+	scan.Redactions = redact.ScanResult(result)
+
+	scan.Finish()
 
 	// Print summary
 	fmt.Println("\n=== Scan Summary ===")
@@ -378,32 +517,46 @@ func runScan(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Applications: %d\n", len(result.Applications))
 	fmt.Printf("IDE Extensions/Plugins: %d\n", len(result.IDEExtensions))
 	fmt.Printf("Browser Extensions: %d\n", len(result.BrowserExtensions))
+	if cfg.IncludeHistorical {
+		fmt.Printf("Package Events: %d\n", len(result.Events))
+	}
+	fmt.Printf("Scanners: %d ok, %d error, %d not installed, %d skipped, %d disabled\n",
+		statusCounts[scanners.StatusOK],
+		statusCounts[scanners.StatusError],
+		statusCounts[scanners.StatusNotInstalled],
+		statusCounts[scanners.StatusSkipped],
+		statusCounts[scanners.StatusDisabled])
+	fmt.Printf("Redacted Secrets: %d\n", scan.Redactions)
 	fmt.Printf("Output Directory: %s\n", cfg.OutputDir)
 
 	// Generate SBOMs
 	fmt.Println("\n=== Generating SBOMs ===")
-	if err := sbom.GenerateSBOMs(result, sysInfo, cfg.OutputDir); err != nil {
+	if err := sbom.GenerateSBOMs(result, sysInfo, scan, cfg.OutputDir, sbom.Options{
+		Format:              cfg.OutputFormat,
+		SingleBOM:           cfg.SingleBOM,
+		DeterministicSerial: cfg.DeterministicSerial,
+	}); err != nil {
 		return fmt.Errorf("failed to generate SBOMs: %w", err)
 	}
 
 	// Collect log files and create zip archive
 	if cfg.CreateZipArchive {
 		fmt.Println("\n=== Creating Archive ===")
-		
+
 		var allLogFiles []string
-		
-		// Collect logs from historical scanners
-		npmHistorical := &historical.NPMHistoricalScanner{}
-		if logs, err := npmHistorical.GetLogFiles(cfg); err == nil {
-			allLogFiles = append(allLogFiles, logs...)
-		}
-		
-		brewHistorical := &historical.BrewHistoricalScanner{}
-		if logs, err := brewHistorical.GetLogFiles(cfg); err == nil {
-			allLogFiles = append(allLogFiles, logs...)
+
+		// Collect logs from the historical scanners that ran
+		for _, scanner := range enabled {
+			logSource, ok := scanner.(historical.LogSource)
+			if !ok {
+				continue
+			}
+			if logs, err := logSource.GetLogFiles(cfg); err == nil {
+				allLogFiles = append(allLogFiles, logs...)
+			}
 		}
 
-		zipFilename, err := archive.CreateScanArchive(cfg.OutputDir, sysInfo, cfg, allLogFiles)
+		zipFilename, err := archive.CreateScanArchive(cfg.OutputDir, sysInfo, cfg, scan, result.Scanners, result.Events, allLogFiles)
 		if err != nil {
 			fmt.Printf("Warning: Failed to create zip archive: %v\n", err)
 		} else if zipFilename != "" {
@@ -415,12 +568,15 @@ func runScan(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// buildPackageSet creates a set of currently installed packages for deduplication
-func buildPackageSet(components []scanners.Component) map[string]bool {
-	set := make(map[string]bool)
-	for _, comp := range components {
+// buildPackageSet indexes currently installed packages for deduplication,
+// mapping each key to the first component's index
+func buildPackageSet(components []scanners.Component) map[string]int {
+	set := make(map[string]int)
+	for i, comp := range components {
 		key := fmt.Sprintf("%s:%s:%s", comp.Name, comp.Version, comp.PackageManager)
-		set[key] = true
+		if _, exists := set[key]; !exists {
+			set[key] = i
+		}
 	}
 	return set
 }
@@ -428,7 +584,7 @@ func buildPackageSet(components []scanners.Component) map[string]bool {
 // expandScannerGroups expands shorthand scanner groups into individual scanners
 func expandScannerGroups(scanners []string) []string {
 	var expanded []string
-	
+
 	for _, scanner := range scanners {
 		switch scanner {
 		case "browser-extensions":
@@ -442,12 +598,23 @@ func expandScannerGroups(scanners []string) []string {
 			expanded = append(expanded, "npm-local")
 			expanded = append(expanded, "pip-local")
 			expanded = append(expanded, "gem-local")
+			expanded = append(expanded, "go-local")
+			expanded = append(expanded, "cargo-local")
+			expanded = append(expanded, "composer-local")
+		case "os-packages":
+			// Expand to all Linux system package scanners
+			expanded = append(expanded, "dpkg")
+			expanded = append(expanded, "rpm")
+			expanded = append(expanded, "apk")
+			expanded = append(expanded, "pacman")
+			expanded = append(expanded, "snap")
+			expanded = append(expanded, "flatpak")
 		default:
 			// Not a group, add as-is
 			expanded = append(expanded, scanner)
 		}
 	}
-	
+
 	return expanded
 }
 
@@ -468,4 +635,3 @@ func main() {
 		os.Exit(1)
 	}
 }
-
@@ -11,13 +11,18 @@ import (
 	"time"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/session"
 	"github.com/eapolsniper/endpointbom/internal/system"
 )
 
 // ScanMetadata contains metadata about the scan for the archive
 type ScanMetadata struct {
+	ScanID                string    `json:"scan_id"`
 	Hostname              string    `json:"hostname"`
 	Timestamp             time.Time `json:"timestamp"`
+	ScanFinished          time.Time `json:"scan_finished"`
+	Redactions            int       `json:"redactions"`
 	OSName                string    `json:"os_name"`
 	OSVersion             string    `json:"os_version"`
 	LocalIPs              []string  `json:"local_ips"`
@@ -25,16 +30,17 @@ type ScanMetadata struct {
 	HistoricalLookbackDays int       `json:"historical_lookback_days"`
 	IncludeHistorical     bool      `json:"include_historical"`
 	IncludeRawLogs        bool      `json:"include_raw_logs"`
+	Scanners              []scanners.ScannerRecord `json:"scanners"`
 }
 
-// CreateScanArchive creates a zip file containing SBOMs and optional logs
-func CreateScanArchive(outputDir string, sysInfo *system.Info, cfg *config.Config, logFiles []string) (string, error) {
+// CreateScanArchive creates a zip file containing the SBOMs written for the scan
+// session, the package events found by historical scanners and optional logs
+func CreateScanArchive(outputDir string, sysInfo *system.Info, cfg *config.Config, scan *session.Session, scannerRecords []scanners.ScannerRecord, events []scanners.Event, logFiles []string) (string, error) {
 	if !cfg.CreateZipArchive {
 		return "", nil
 	}
 
-	timestamp := time.Now().Format("20060102-150405")
-	zipFilename := fmt.Sprintf("%s.%s.scan.zip", sysInfo.Hostname, timestamp)
+	zipFilename := scan.Filename("scan", "zip")
 	zipPath := filepath.Join(outputDir, zipFilename)
 
 	// Create zip file
@@ -49,8 +55,11 @@ func CreateScanArchive(outputDir string, sysInfo *system.Info, cfg *config.Confi
 
 	// Add metadata.json
 	metadata := ScanMetadata{
+		ScanID:                scan.ID,
 		Hostname:              sysInfo.Hostname,
-		Timestamp:             sysInfo.Timestamp,
+		Timestamp:             scan.Started,
+		ScanFinished:          scan.Finished,
+		Redactions:            scan.Redactions,
 		OSName:                sysInfo.OSName,
 		OSVersion:             sysInfo.OSVersion,
 		LocalIPs:              sysInfo.LocalIPs,
@@ -58,19 +67,27 @@ func CreateScanArchive(outputDir string, sysInfo *system.Info, cfg *config.Confi
 		HistoricalLookbackDays: cfg.HistoricalLookbackDays,
 		IncludeHistorical:     cfg.IncludeHistorical,
 		IncludeRawLogs:        cfg.IncludeRawLogs,
+		Scanners:              scannerRecords,
 	}
 
 	if err := addJSONToZip(zipWriter, "metadata.json", metadata); err != nil {
 		return "", err
 	}
 
-	// Add all CycloneDX SBOM files
-	sbomFiles, err := filepath.Glob(filepath.Join(outputDir, fmt.Sprintf("%s.%s.*.cdx.json", sysInfo.Hostname, timestamp)))
-	if err == nil {
-		for _, sbomFile := range sbomFiles {
-			if err := addFileToZip(zipWriter, sbomFile, "sboms/"+filepath.Base(sbomFile)); err != nil {
-				return "", err
-			}
+	// Add events.json: the install, upgrade and removal history of packages
+	if cfg.IncludeHistorical {
+		if events == nil {
+			events = []scanners.Event{}
+		}
+		if err := addJSONToZip(zipWriter, "events.json", events); err != nil {
+			return "", err
+		}
+	}
+
+	// Add the SBOM files the session wrote
+	for _, sbomFile := range scan.Files {
+		if err := addFileToZip(zipWriter, filepath.Join(outputDir, sbomFile), "sboms/"+sbomFile); err != nil {
+			return "", err
 		}
 	}
 
@@ -140,7 +157,7 @@ func addJSONToZip(zipWriter *zip.Writer, archivePath string, data interface{}) e
 func detectPackageManager(logPath string) string {
 	logPath = filepath.ToSlash(strings.ToLower(logPath))
 
-	if strings.Contains(logPath, "/.npm/") {
+	if strings.Contains(logPath, "/.npm/") || strings.Contains(logPath, "/npm-cache/_logs/") {
 		return "npm"
 	} else if strings.Contains(logPath, "/homebrew/") || strings.Contains(logPath, "/usr/local/var/log/") {
 		return "brew"
@@ -148,6 +165,12 @@ func detectPackageManager(logPath string) string {
 		return "chocolatey"
 	} else if strings.Contains(logPath, "/.pip/") {
 		return "pip"
+	} else if strings.Contains(logPath, "/var/log/apt/") {
+		return "apt"
+	} else if strings.Contains(logPath, "/var/log/dpkg.log") {
+		return "dpkg"
+	} else if strings.Contains(logPath, "/var/log/dnf") {
+		return "dnf"
 	}
 
 	return "other"
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -27,6 +28,17 @@ type Config struct {
 	// OutputDir specifies where to save SBOM files
 	OutputDir string `yaml:"output_dir"`
 
+	// OutputFormat selects the SBOM format: cdx-json, cdx-xml, spdx-json, spdx-tagvalue or spdx3-json
+	OutputFormat string `yaml:"output_format"`
+
+	// SingleBOM writes one SBOM per endpoint with a nested assembly per category
+	// instead of one SBOM file per category
+	SingleBOM bool `yaml:"single_bom"`
+
+	// DeterministicSerial derives SBOM serial numbers from their content (UUIDv5)
+	// so rescans of an unchanged endpoint keep the same serial
+	DeterministicSerial bool `yaml:"deterministic_serial"`
+
 	// Debug enables debug logging
 	Debug bool `yaml:"debug"`
 
@@ -47,6 +59,47 @@ type Config struct {
 
 	// CreateZipArchive creates a zip file with SBOMs and logs
 	CreateZipArchive bool `yaml:"create_zip_archive"`
+
+	// MaxParallelScanners limits how many scanners run at the same time
+	MaxParallelScanners int `yaml:"max_parallel_scanners"`
+
+	// ScannerTimeoutSeconds is the default per-scanner timeout (0 disables the timeout)
+	ScannerTimeoutSeconds int `yaml:"scanner_timeout_seconds"`
+
+	// ScannerTimeouts overrides the timeout (in seconds) for individual scanners
+	ScannerTimeouts map[string]int `yaml:"scanner_timeouts"`
+
+	// ProjectRoots are the directories (globs allowed) searched for local projects.
+	// A leading "~" is expanded for every scanned user. When empty, common project
+	// directory names under each home directory are used.
+	ProjectRoots []string `yaml:"project_roots"`
+
+	// ProjectMaxDepth limits how many directories deep project discovery descends below a root
+	ProjectMaxDepth int `yaml:"project_max_depth"`
+
+	// ProjectMaxEntries stops project discovery after this many files and directories (0 disables)
+	ProjectMaxEntries int `yaml:"project_max_entries"`
+
+	// ProjectIgnorePatterns are gitignore-style patterns for directories discovery skips
+	ProjectIgnorePatterns []string `yaml:"project_ignore_patterns"`
+
+	// RespectGitignore makes project discovery skip directories ignored by .gitignore files
+	RespectGitignore bool `yaml:"respect_gitignore"`
+
+	// DiscoveryTimeBudgetSeconds bounds the total time spent discovering projects (0 disables)
+	DiscoveryTimeBudgetSeconds int `yaml:"discovery_time_budget_seconds"`
+
+	// DisableHashing turns off SHA-256 hashing of application executables and extension files
+	DisableHashing bool `yaml:"disable_hashing"`
+
+	// HashMaxFileSizeMB skips files larger than this when hashing (0 disables the limit)
+	HashMaxFileSizeMB int `yaml:"hash_max_file_size_mb"`
+
+	// HashBudgetMB caps the total amount of data hashed per scan (0 disables the limit)
+	HashBudgetMB int `yaml:"hash_budget_mb"`
+
+	// HashTimeBudgetSeconds bounds the total time spent hashing per scan (0 disables the limit)
+	HashTimeBudgetSeconds int `yaml:"hash_time_budget_seconds"`
 }
 
 // DefaultConfig returns a Config with default values including sensitive path exclusions
@@ -82,6 +135,7 @@ func DefaultConfig() *Config {
 		RequireAdmin:           false, // Don't require admin - auto-adjust based on privileges
 		ScanAllUsers:           true,  // Default to true, will auto-adjust if not admin
 		OutputDir:              "",    // Will be set to scans/ by main
+		OutputFormat:           "cdx-json",
 		Debug:                  false,
 		Verbose:                false,
 		DisablePublicIP:        true,  // Default to true - don't fetch public IP from external services
@@ -89,6 +143,23 @@ func DefaultConfig() *Config {
 		IncludeHistorical:      true,
 		IncludeRawLogs:         true,
 		CreateZipArchive:       true,
+		MaxParallelScanners:    4,
+		ScannerTimeoutSeconds:  300,
+		ProjectMaxDepth:        8,
+		ProjectMaxEntries:      500000,
+		ProjectIgnorePatterns: []string{
+			"node_modules",
+			"vendor",
+			".git",
+			"__pycache__",
+			".tox",
+			".cache",
+		},
+		RespectGitignore:           true,
+		DiscoveryTimeBudgetSeconds: 120,
+		HashMaxFileSizeMB:          256,
+		HashBudgetMB:               2048,
+		HashTimeBudgetSeconds:      60,
 	}
 }
 
@@ -121,6 +192,37 @@ func (c *Config) IsScannerDisabled(scanner string) bool {
 	return false
 }
 
+// DiscoveryTimeBudget returns the project discovery time budget. A zero duration
+// means discovery is not time limited.
+func (c *Config) DiscoveryTimeBudget() time.Duration {
+	if c.DiscoveryTimeBudgetSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.DiscoveryTimeBudgetSeconds) * time.Second
+}
+
+// HashTimeBudget returns the hashing time budget. A zero duration means
+// hashing is not time limited.
+func (c *Config) HashTimeBudget() time.Duration {
+	if c.HashTimeBudgetSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(c.HashTimeBudgetSeconds) * time.Second
+}
+
+// ScannerTimeout returns the timeout for a scanner, honoring per-scanner overrides.
+// A zero duration means the scanner has no timeout.
+func (c *Config) ScannerTimeout(scanner string) time.Duration {
+	seconds := c.ScannerTimeoutSeconds
+	if override, ok := c.ScannerTimeouts[scanner]; ok {
+		seconds = override
+	}
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
 // IsPathExcluded checks if a path should be excluded (improved with normalization)
 func (c *Config) IsPathExcluded(path string) bool {
 	// Normalize the input path
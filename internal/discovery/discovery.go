@@ -0,0 +1,412 @@
+// Package discovery finds local projects on disk in a single shared walk so that
+// every local project scanner works from the same list instead of re-walking the disk.
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/system"
+)
+
+// Markers are the file and directory names that identify a project. A directory
+// is reported as a project when it directly contains at least one of them.
+var Markers = []string{
+	// Node.js
+	"package.json",
+	"node_modules",
+	"package-lock.json",
+	"npm-shrinkwrap.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	".pnp.cjs",
+	// Python
+	"pyvenv.cfg",
+	"requirements.txt",
+	"pyproject.toml",
+	// Ruby
+	"Gemfile",
+	"Gemfile.lock",
+	// Go
+	"go.mod",
+	"go.sum",
+	// Rust
+	"Cargo.toml",
+	"Cargo.lock",
+	// PHP
+	"composer.json",
+	"composer.lock",
+	// Editor settings that may hold project-level MCP servers
+	".vscode",
+	".cursor",
+}
+
+// DefaultRoots are searched when no project roots are configured
+var DefaultRoots = []string{
+	"~/projects",
+	"~/code",
+	"~/dev",
+	"~/development",
+	"~/workspace",
+	"~/repos",
+	"~/git",
+	"~/src",
+	"~/work",
+}
+
+// homeSkipDirs are home directory entries never treated as project roots by the
+// default home directory heuristic
+var homeSkipDirs = map[string]bool{
+	"Library":   true,
+	"Documents": true,
+	"Downloads": true,
+	"Desktop":   true,
+	"Pictures":  true,
+	"Movies":    true,
+	"Music":     true,
+}
+
+// Project is a directory that contains one or more marker files
+type Project struct {
+	Path    string
+	Markers map[string]bool
+}
+
+// Has reports whether the project directory contains the marker
+func (p Project) Has(marker string) bool {
+	return p.Markers[marker]
+}
+
+// Result is the outcome of a discovery walk
+type Result struct {
+	Roots    []string
+	Projects []Project
+	Entries  int           // Files and directories visited
+	Duration time.Duration // Time spent walking
+	// TimedOut is set when the time budget ran out before the walk finished
+	TimedOut bool
+	// Truncated is set when the entry limit was reached before the walk finished
+	Truncated bool
+}
+
+// With returns the projects that contain the marker, in walk order
+func (r *Result) With(marker string) []Project {
+	var projects []Project
+	for _, project := range r.Projects {
+		if project.Has(marker) {
+			projects = append(projects, project)
+		}
+	}
+	return projects
+}
+
+// walkCall is a discovery walk shared by every caller using the same configuration
+type walkCall struct {
+	done   chan struct{}
+	result *Result
+}
+
+var (
+	mu    sync.Mutex
+	walks = make(map[*config.Config]*walkCall)
+)
+
+// Discover returns the projects under the configured roots. The disk is walked
+// once per configuration; concurrent and later callers share the first walk.
+// The walk itself is bounded by the configured time budget rather than by ctx, so
+// one scanner's timeout does not cut discovery short for the others.
+func Discover(ctx context.Context, cfg *config.Config) (*Result, error) {
+	mu.Lock()
+	call, exists := walks[cfg]
+	if !exists {
+		call = &walkCall{done: make(chan struct{})}
+		walks[cfg] = call
+		mu.Unlock()
+
+		go func() {
+			call.result = walk(context.WithoutCancel(ctx), cfg)
+			close(call.done)
+		}()
+	} else {
+		mu.Unlock()
+	}
+
+	select {
+	case <-call.done:
+		return call.result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// walker holds the state of a single discovery walk
+type walker struct {
+	cfg       *config.Config
+	deadline  time.Time
+	maxDepth  int
+	entries   int
+	visited   map[string]bool
+	projects  []Project
+	timedOut  bool
+	truncated bool
+}
+
+func walk(ctx context.Context, cfg *config.Config) *Result {
+	start := time.Now()
+
+	if budget := cfg.DiscoveryTimeBudget(); budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+
+	w := &walker{
+		cfg:      cfg,
+		maxDepth: cfg.ProjectMaxDepth,
+		visited:  make(map[string]bool),
+	}
+
+	roots := resolveRoots(cfg)
+	for _, root := range roots {
+		if cfg.Verbose {
+			fmt.Printf("Discovering projects in: %s\n", root)
+		}
+
+		var rules []ignoreRule
+		for _, pattern := range cfg.ProjectIgnorePatterns {
+			if rule, ok := parseIgnorePattern(root, pattern); ok {
+				rules = append(rules, rule)
+			}
+		}
+
+		if !w.walkDir(ctx, root, 0, rules) {
+			break
+		}
+	}
+
+	result := &Result{
+		Roots:     roots,
+		Projects:  w.projects,
+		Entries:   w.entries,
+		Duration:  time.Since(start),
+		TimedOut:  w.timedOut,
+		Truncated: w.truncated,
+	}
+
+	if cfg.Verbose {
+		fmt.Printf("Project discovery found %d projects in %d entries (%s)\n", len(result.Projects), result.Entries, result.Duration.Round(time.Millisecond))
+	}
+	if result.TimedOut {
+		fmt.Fprintf(os.Stderr, "Warning: project discovery stopped after the %s time budget; results are incomplete\n", cfg.DiscoveryTimeBudget())
+	}
+	if result.Truncated {
+		fmt.Fprintf(os.Stderr, "Warning: project discovery stopped after %d entries; results are incomplete\n", cfg.ProjectMaxEntries)
+	}
+
+	return result
+}
+
+// walkDir records dir if it is a project and descends into its subdirectories.
+// It returns false once the walk has to stop (time budget or entry limit).
+func (w *walker) walkDir(ctx context.Context, dir string, depth int, rules []ignoreRule) bool {
+	if ctx.Err() != nil {
+		w.timedOut = true
+		return false
+	}
+
+	// Each directory is walked once even if roots overlap
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return true
+	}
+	if w.visited[realDir] {
+		return true
+	}
+	w.visited[realDir] = true
+
+	if w.cfg.IsPathExcluded(dir) {
+		return true
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return true // Skip directories we can't access
+	}
+
+	w.entries += len(entries)
+	if w.cfg.ProjectMaxEntries > 0 && w.entries > w.cfg.ProjectMaxEntries {
+		w.truncated = true
+		return false
+	}
+
+	markers := make(map[string]bool)
+	for _, entry := range entries {
+		for _, marker := range Markers {
+			if entry.Name() == marker {
+				markers[marker] = true
+			}
+		}
+	}
+	if len(markers) > 0 {
+		w.projects = append(w.projects, Project{Path: dir, Markers: markers})
+		if w.cfg.Debug {
+			fmt.Printf("Discovered project at: %s\n", dir)
+		}
+	}
+
+	// Virtual environments hold installed packages, not projects
+	if markers["pyvenv.cfg"] {
+		return true
+	}
+
+	if w.maxDepth > 0 && depth >= w.maxDepth {
+		return true
+	}
+
+	if w.cfg.RespectGitignore {
+		if gitRules := readGitignore(dir); len(gitRules) > 0 {
+			rules = append(rules[:len(rules):len(rules)], gitRules...)
+		}
+	}
+
+	for _, entry := range entries {
+		// Symlinks are never followed; DirEntry reports them as non-directories
+		if !entry.IsDir() {
+			continue
+		}
+
+		child := filepath.Join(dir, entry.Name())
+		if isIgnored(rules, child) {
+			// Ignored directories are not descended into, but a virtual environment
+			// (usually gitignored) is still reported
+			if _, err := os.Stat(filepath.Join(child, "pyvenv.cfg")); err == nil && !w.cfg.IsPathExcluded(child) {
+				w.projects = append(w.projects, Project{Path: child, Markers: map[string]bool{"pyvenv.cfg": true}})
+			}
+			continue
+		}
+
+		if !w.walkDir(ctx, child, depth+1, rules) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// resolveRoots expands the configured project roots into existing directories.
+// "~" is expanded for every scanned user and globs are expanded. Without
+// configured roots, the default names are used along with any home directory
+// entry that itself looks like a project.
+func resolveRoots(cfg *config.Config) []string {
+	homes := system.HomeDirs(cfg)
+
+	patterns := cfg.ProjectRoots
+	useDefaults := len(patterns) == 0
+	if useDefaults {
+		patterns = DefaultRoots
+	}
+
+	var candidates []string
+	for _, pattern := range patterns {
+		if pattern == "~" || strings.HasPrefix(pattern, "~/") || strings.HasPrefix(pattern, `~\`) {
+			for _, home := range homes {
+				candidates = append(candidates, filepath.Join(home, pattern[1:]))
+			}
+		} else {
+			candidates = append(candidates, pattern)
+		}
+	}
+
+	if useDefaults {
+		for _, home := range homes {
+			candidates = append(candidates, homeProjectDirs(home)...)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var roots []string
+	for _, candidate := range candidates {
+		matches, err := filepath.Glob(candidate)
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err != nil || !info.IsDir() {
+				continue
+			}
+			if !seen[match] {
+				seen[match] = true
+				roots = append(roots, match)
+			}
+		}
+	}
+
+	// Drop roots nested inside other roots; the outer walk covers them
+	sort.Strings(roots)
+	var result []string
+	for _, root := range roots {
+		nested := false
+		for _, outer := range result {
+			if strings.HasPrefix(root, outer+string(filepath.Separator)) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			result = append(result, root)
+		}
+	}
+
+	return result
+}
+
+// homeProjectDirs returns the entries of a home directory that look like projects
+func homeProjectDirs(home string) []string {
+	entries, err := os.ReadDir(home)
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		name := entry.Name()
+		// Skip hidden directories, system directories
+		if !entry.IsDir() || strings.HasPrefix(name, ".") || homeSkipDirs[name] {
+			continue
+		}
+
+		fullPath := filepath.Join(home, name)
+		if looksLikeProjectDir(fullPath) {
+			dirs = append(dirs, fullPath)
+		}
+	}
+	return dirs
+}
+
+// looksLikeProjectDir checks for common project indicators
+func looksLikeProjectDir(path string) bool {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		if entry.Name() == ".git" {
+			return true
+		}
+		for _, marker := range Markers {
+			if entry.Name() == marker {
+				return true
+			}
+		}
+	}
+
+	return false
+}
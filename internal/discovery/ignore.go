@@ -0,0 +1,115 @@
+package discovery
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreRule is one gitignore-style pattern
+type ignoreRule struct {
+	base     string   // Directory the pattern is relative to
+	segments []string // Pattern split on "/"
+	anchored bool     // Pattern contains a "/" and only matches relative to base
+	negate   bool     // "!" pattern that re-includes a previously ignored path
+}
+
+// parseIgnorePattern parses a single gitignore line. It returns false for blank
+// lines and comments.
+func parseIgnorePattern(base, line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`)
+
+	// Only directories are ever matched, so a trailing "/" changes nothing
+	line = strings.TrimSuffix(line, "/")
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	rule.segments = strings.Split(line, "/")
+	return rule, true
+}
+
+// readGitignore reads the rules of the .gitignore file in dir, if there is one
+func readGitignore(dir string) []ignoreRule {
+	file, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseIgnorePattern(dir, scanner.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// matches reports whether the rule matches the directory at dirPath
+func (r ignoreRule) matches(dirPath string) bool {
+	rel, err := filepath.Rel(r.base, dirPath)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+
+	if !r.anchored {
+		// Unanchored patterns match the directory name at any depth
+		matched, _ := path.Match(r.segments[0], parts[len(parts)-1])
+		return matched
+	}
+	return matchSegments(r.segments, parts)
+}
+
+// matchSegments matches path segments against pattern segments, where "**"
+// matches zero or more segments
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], parts[0]); !matched {
+			return false
+		}
+		pattern = pattern[1:]
+		parts = parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// isIgnored applies rules in order; the last matching rule wins, as in git
+func isIgnored(rules []ignoreRule, dirPath string) bool {
+	ignored := false
+	for _, rule := range rules {
+		if rule.matches(dirPath) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
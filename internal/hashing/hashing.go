@@ -0,0 +1,201 @@
+// Package hashing computes SHA-256 digests of files and directories for
+// component evidence. Every scanner sharing a configuration draws from one
+// budget, so hashing stays bounded by the configured size and time limits no
+// matter how many applications and extensions an endpoint has.
+package hashing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+)
+
+// SHA256 is the CycloneDX name of the algorithm used for file and directory digests
+const SHA256 = "SHA-256"
+
+var (
+	// ErrDisabled is returned when hashing is turned off in the configuration
+	ErrDisabled = errors.New("hashing disabled")
+
+	// ErrBudgetExhausted is returned once the size or time budget has run out
+	ErrBudgetExhausted = errors.New("hash budget exhausted")
+
+	// ErrTooLarge is returned for files above the per-file size limit
+	ErrTooLarge = errors.New("file exceeds hash size limit")
+)
+
+// Budget bounds how much data is hashed and for how long
+type Budget struct {
+	mu          sync.Mutex
+	disabled    bool
+	maxFileSize int64     // 0 means no per-file limit
+	remaining   int64     // Bytes left; negative means unlimited
+	deadline    time.Time // Zero means no time limit
+	exhausted   bool
+}
+
+var (
+	mu      sync.Mutex
+	budgets = make(map[*config.Config]*Budget)
+)
+
+// For returns the budget shared by every scanner using cfg. The time budget
+// starts when the first scanner asks for it.
+func For(cfg *config.Config) *Budget {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if budget, exists := budgets[cfg]; exists {
+		return budget
+	}
+
+	budget := &Budget{
+		disabled:    cfg.DisableHashing,
+		maxFileSize: int64(cfg.HashMaxFileSizeMB) << 20,
+		remaining:   -1,
+	}
+	if cfg.HashBudgetMB > 0 {
+		budget.remaining = int64(cfg.HashBudgetMB) << 20
+	}
+	if limit := cfg.HashTimeBudget(); limit > 0 {
+		budget.deadline = time.Now().Add(limit)
+	}
+
+	budgets[cfg] = budget
+	return budget
+}
+
+// File returns the hex SHA-256 digest of a file
+func (b *Budget) File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a regular file", path)
+	}
+
+	if err := b.reserve(info.Size()); err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, &deadlineReader{r: f, budget: b}); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Directory returns a SHA-256 digest of a directory tree: the digest of a
+// sha256sum-style manifest ("<hex digest>  <path>\n") of every regular file,
+// sorted by slash-separated path relative to root. Symlinks are not followed.
+// A directory that cannot be hashed completely yields an error rather than a
+// partial digest.
+func (b *Budget) Directory(root string) (string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	type entry struct {
+		rel    string
+		digest string
+	}
+	entries := make([]entry, 0, len(files))
+	for _, path := range files {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return "", err
+		}
+		digest, err := b.File(path)
+		if err != nil {
+			return "", err
+		}
+		entries = append(entries, entry{rel: filepath.ToSlash(rel), digest: digest})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].rel < entries[j].rel
+	})
+
+	h := sha256.New()
+	for _, e := range entries {
+		fmt.Fprintf(h, "%s  %s\n", e.digest, e.rel)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// reserve takes size bytes from the budget before a file is read
+func (b *Budget) reserve(size int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.disabled {
+		return ErrDisabled
+	}
+	if b.maxFileSize > 0 && size > b.maxFileSize {
+		return ErrTooLarge
+	}
+	if b.exhausted || b.expired() {
+		b.exhausted = true
+		return ErrBudgetExhausted
+	}
+	if b.remaining >= 0 {
+		if size > b.remaining {
+			b.exhausted = true
+			return ErrBudgetExhausted
+		}
+		b.remaining -= size
+	}
+	return nil
+}
+
+// expired reports whether the time budget has run out; b.mu must be held
+func (b *Budget) expired() bool {
+	return !b.deadline.IsZero() && time.Now().After(b.deadline)
+}
+
+// deadlineReader stops a read part way through a file once the time budget expires
+type deadlineReader struct {
+	r      io.Reader
+	budget *Budget
+}
+
+func (d *deadlineReader) Read(p []byte) (int, error) {
+	d.budget.mu.Lock()
+	expired := d.budget.expired()
+	if expired {
+		d.budget.exhausted = true
+	}
+	d.budget.mu.Unlock()
+
+	if expired {
+		return 0, ErrBudgetExhausted
+	}
+	return d.r.Read(p)
+}
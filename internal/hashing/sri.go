@@ -0,0 +1,52 @@
+package hashing
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// sriAlgorithm is a Subresource Integrity algorithm with its digest length in bytes
+type sriAlgorithm struct {
+	name string // CycloneDX name
+	size int
+}
+
+// sriAlgorithms maps Subresource Integrity algorithm names to CycloneDX algorithms
+var sriAlgorithms = map[string]sriAlgorithm{
+	"sha1":   {name: "SHA-1", size: 20},
+	"sha256": {name: "SHA-256", size: 32},
+	"sha384": {name: "SHA-384", size: 48},
+	"sha512": {name: "SHA-512", size: 64},
+}
+
+// FromSRI converts a Subresource Integrity string such as npm's
+// "sha512-<base64>" (possibly several, space separated) into hex digests keyed
+// by CycloneDX algorithm name. These are the digests of the package tarball.
+func FromSRI(integrity string) map[string]string {
+	hashes := make(map[string]string)
+	for _, token := range strings.Fields(integrity) {
+		alg, encoded, found := strings.Cut(token, "-")
+		if !found {
+			continue
+		}
+		algorithm, known := sriAlgorithms[strings.ToLower(alg)]
+		if !known {
+			continue
+		}
+		// Options after '?' are reserved by the SRI spec and carry no digest data
+		encoded, _, _ = strings.Cut(encoded, "?")
+		digest, err := decodeBase64(encoded)
+		if err != nil || len(digest) != algorithm.size {
+			continue
+		}
+		hashes[algorithm.name] = hex.EncodeToString(digest)
+	}
+	return hashes
+}
+
+// decodeBase64 accepts padded and unpadded standard base64, since lockfiles
+// written by different tools disagree on padding
+func decodeBase64(s string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+}
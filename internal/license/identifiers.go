@@ -0,0 +1,252 @@
+package license
+
+// spdxLicenses are the SPDX license list identifiers recognized by Normalize:
+// the licenses that account for nearly all packages found on developer endpoints
+var spdxLicenses = []string{
+	"0BSD",
+	"AFL-2.1",
+	"AFL-3.0",
+	"AGPL-1.0-only",
+	"AGPL-3.0-only",
+	"AGPL-3.0-or-later",
+	"Apache-1.0",
+	"Apache-1.1",
+	"Apache-2.0",
+	"APSL-2.0",
+	"Artistic-1.0",
+	"Artistic-1.0-Perl",
+	"Artistic-2.0",
+	"BlueOak-1.0.0",
+	"BSD-1-Clause",
+	"BSD-2-Clause",
+	"BSD-2-Clause-Patent",
+	"BSD-3-Clause",
+	"BSD-3-Clause-Clear",
+	"BSD-4-Clause",
+	"BSL-1.0",
+	"BUSL-1.1",
+	"bzip2-1.0.6",
+	"CC-BY-3.0",
+	"CC-BY-4.0",
+	"CC-BY-SA-3.0",
+	"CC-BY-SA-4.0",
+	"CC-BY-NC-4.0",
+	"CC0-1.0",
+	"CDDL-1.0",
+	"CDDL-1.1",
+	"CECILL-2.1",
+	"CPL-1.0",
+	"curl",
+	"ECL-2.0",
+	"EFL-2.0",
+	"EPL-1.0",
+	"EPL-2.0",
+	"EUPL-1.1",
+	"EUPL-1.2",
+	"FSFAP",
+	"FSFUL",
+	"FSFULLR",
+	"FTL",
+	"GFDL-1.2-only",
+	"GFDL-1.2-or-later",
+	"GFDL-1.3-only",
+	"GFDL-1.3-or-later",
+	"GPL-1.0-only",
+	"GPL-1.0-or-later",
+	"GPL-2.0-only",
+	"GPL-2.0-or-later",
+	"GPL-3.0-only",
+	"GPL-3.0-or-later",
+	"HPND",
+	"ICU",
+	"IJG",
+	"ImageMagick",
+	"Info-ZIP",
+	"IPA",
+	"ISC",
+	"JSON",
+	"LGPL-2.0-only",
+	"LGPL-2.0-or-later",
+	"LGPL-2.1-only",
+	"LGPL-2.1-or-later",
+	"LGPL-3.0-only",
+	"LGPL-3.0-or-later",
+	"Libpng",
+	"libpng-2.0",
+	"libtiff",
+	"LPPL-1.3c",
+	"MirOS",
+	"MIT",
+	"MIT-0",
+	"MIT-CMU",
+	"MPL-1.0",
+	"MPL-1.1",
+	"MPL-2.0",
+	"MPL-2.0-no-copyleft-exception",
+	"MS-PL",
+	"MS-RL",
+	"NCSA",
+	"OFL-1.1",
+	"OLDAP-2.8",
+	"OpenSSL",
+	"OSL-3.0",
+	"PHP-3.0",
+	"PHP-3.01",
+	"PostgreSQL",
+	"PSF-2.0",
+	"Python-2.0",
+	"Ruby",
+	"SGI-B-2.0",
+	"Sleepycat",
+	"SSPL-1.0",
+	"TCL",
+	"Unicode-3.0",
+	"Unicode-DFS-2016",
+	"Unlicense",
+	"UPL-1.0",
+	"Vim",
+	"W3C",
+	"WTFPL",
+	"X11",
+	"Zlib",
+	"zlib-acknowledgement",
+	"ZPL-2.1",
+}
+
+// licenseAliases maps names used in package metadata, distro databases and
+// trove classifiers to SPDX identifiers. Deprecated SPDX identifiers (such as
+// "GPL-2.0") map to their current replacements.
+var licenseAliases = map[string]string{
+	// Permissive
+	"MIT License":                "MIT",
+	"Expat":                      "MIT",
+	"MIT/X11":                    "MIT",
+	"X11 License":                "X11",
+	"ISC License (ISCL)":         "ISC",
+	"ISCL":                       "ISC",
+	"BSD":                        "BSD-3-Clause",
+	"BSD License":                "BSD-3-Clause",
+	"New BSD":                    "BSD-3-Clause",
+	"Modified BSD":               "BSD-3-Clause",
+	"3-Clause BSD":               "BSD-3-Clause",
+	"BSD 3-Clause":               "BSD-3-Clause",
+	"BSD-3":                      "BSD-3-Clause",
+	"Simplified BSD":             "BSD-2-Clause",
+	"FreeBSD":                    "BSD-2-Clause",
+	"2-Clause BSD":               "BSD-2-Clause",
+	"BSD-2":                      "BSD-2-Clause",
+	"BSD with advertising":       "BSD-4-Clause",
+	"Apache":                     "Apache-2.0",
+	"Apache Software License":    "Apache-2.0",
+	"ASL 2.0":                    "Apache-2.0",
+	"ASL-2.0":                    "Apache-2.0",
+	"ASF 2.0":                    "Apache-2.0",
+	"ASL 1.1":                    "Apache-1.1",
+	"Boost":                      "BSL-1.0",
+	"Boost Software License":     "BSL-1.0",
+	"zlib/libpng":                "Zlib",
+	"zlib License":               "Zlib",
+	"Public Domain CC0":          "CC0-1.0",
+	"CC0":                        "CC0-1.0",
+	"Unlicense (Unlicense)":      "Unlicense",
+	"The Unlicense":              "Unlicense",
+	"Python Software Foundation": "PSF-2.0",
+	"PSF":                        "PSF-2.0",
+	"PSFL":                       "PSF-2.0",
+	"Python":                     "Python-2.0",
+	"Historical Permission Notice and Disclaimer (HPND)": "HPND",
+	"Universal Permissive License (UPL)":                 "UPL-1.0",
+	"Zope Public License":                                "ZPL-2.1",
+	"Artistic":                                           "Artistic-1.0-Perl",
+	"Artistic 2.0":                                       "Artistic-2.0",
+	"Perl":                                               "Artistic-1.0-Perl OR GPL-1.0-or-later",
+	"OFL":                                                "OFL-1.1",
+	"SIL Open Font License 1.1":                          "OFL-1.1",
+	"Vim License":                                        "Vim",
+	"PostgreSQL License":                                 "PostgreSQL",
+	"MPLv2":                                              "MPL-2.0",
+	"Mozilla Public License 2.0 (MPL 2.0)":               "MPL-2.0",
+	"Mozilla Public License 2.0":                         "MPL-2.0",
+	"Mozilla Public License 1.1 (MPL 1.1)":               "MPL-1.1",
+	"Eclipse Public License 2.0":                         "EPL-2.0",
+	"Eclipse Public License 1.0":                         "EPL-1.0",
+	"Eclipse Public License 2.0 (EPL-2.0)":               "EPL-2.0",
+	"Common Development and Distribution License 1.0 (CDDL-1.0)": "CDDL-1.0",
+	"European Union Public Licence 1.2 (EUPL 1.2)":               "EUPL-1.2",
+	"OpenSSL License":             "OpenSSL",
+	"Academic Free License (AFL)": "AFL-3.0",
+
+	// Copyleft; unversioned names keep their conventional meaning in distro metadata
+	"GPL":                               "GPL-1.0-or-later",
+	"GPL+":                              "GPL-1.0-or-later",
+	"GPL-1.0":                           "GPL-1.0-only",
+	"GPL-1.0+":                          "GPL-1.0-or-later",
+	"GPL-2.0":                           "GPL-2.0-only",
+	"GPL-2.0+":                          "GPL-2.0-or-later",
+	"GPL-3.0":                           "GPL-3.0-only",
+	"GPL-3.0+":                          "GPL-3.0-or-later",
+	"General Public License v2 (GPLv2)": "GPL-2.0-only",
+	"General Public License v3 (GPLv3)": "GPL-3.0-only",
+	"General Public License v2 or later (GPLv2+)": "GPL-2.0-or-later",
+	"General Public License v3 or later (GPLv3+)": "GPL-3.0-or-later",
+	"General Public License (GPL)":                "GPL-1.0-or-later",
+	"LGPL":                                        "LGPL-2.0-or-later",
+	"LGPL+":                                       "LGPL-2.0-or-later",
+	"LGPL-2.0":                                    "LGPL-2.0-only",
+	"LGPL-2.0+":                                   "LGPL-2.0-or-later",
+	"LGPL-2.1":                                    "LGPL-2.1-only",
+	"LGPL-2.1+":                                   "LGPL-2.1-or-later",
+	"LGPL-3.0":                                    "LGPL-3.0-only",
+	"LGPL-3.0+":                                   "LGPL-3.0-or-later",
+	"Lesser General Public License v2 (LGPLv2)":           "LGPL-2.0-only",
+	"Lesser General Public License v2 or later (LGPLv2+)": "LGPL-2.0-or-later",
+	"Lesser General Public License v3 (LGPLv3)":           "LGPL-3.0-only",
+	"Lesser General Public License v3 or later (LGPLv3+)": "LGPL-3.0-or-later",
+	"Library or Lesser General Public License (LGPL)":     "LGPL-2.0-or-later",
+	"AGPL":                             "AGPL-3.0-or-later",
+	"AGPL-3.0":                         "AGPL-3.0-only",
+	"AGPL-3.0+":                        "AGPL-3.0-or-later",
+	"Affero General Public License v3": "AGPL-3.0-only",
+	"Affero General Public License v3 or later (AGPLv3+)": "AGPL-3.0-or-later",
+	"GFDL-1.2": "GFDL-1.2-only",
+	"GFDL-1.3": "GFDL-1.3-only",
+	"FDL":      "GFDL-1.3-or-later",
+}
+
+// spdxExceptions are the SPDX license exception identifiers accepted after WITH
+var spdxExceptions = []string{
+	"Autoconf-exception-2.0",
+	"Autoconf-exception-3.0",
+	"Bison-exception-2.2",
+	"Classpath-exception-2.0",
+	"Font-exception-2.0",
+	"GCC-exception-2.0",
+	"GCC-exception-3.1",
+	"LLVM-exception",
+	"Linux-syscall-note",
+	"OCaml-LGPL-linking-exception",
+	"OpenJDK-assembly-exception-1.0",
+	"Qt-LGPL-exception-1.1",
+	"Universal-FOSS-exception-1.0",
+	"WxWindows-exception-3.1",
+}
+
+var (
+	// licenseIDs maps canonical keys of identifiers and aliases to SPDX identifiers
+	licenseIDs = make(map[string]string)
+
+	// exceptionIDs maps canonical keys of exception identifiers to their SPDX form
+	exceptionIDs = make(map[string]string)
+)
+
+func init() {
+	for _, id := range spdxLicenses {
+		licenseIDs[canonical(id)] = id
+	}
+	for alias, id := range licenseAliases {
+		licenseIDs[canonical(alias)] = id
+	}
+	for _, id := range spdxExceptions {
+		exceptionIDs[canonical(id)] = id
+	}
+}
@@ -0,0 +1,342 @@
+// Package license normalizes the license strings found in package metadata
+// (package.json, Python core metadata, gemspecs, Cargo.toml, distro package
+// databases, ...) into SPDX license identifiers and expressions.
+package license
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// versionPrefix matches a "v" between a license name and its version ("gplv3")
+var versionPrefix = regexp.MustCompile(`([a-z])v(\d)`)
+
+// Normalize converts a declared license into an SPDX license expression. Common
+// spellings ("Apache 2.0", "GPLv2+", "MIT License", "GPL version 3 or later",
+// Debian's "Expat", ...) map to their SPDX identifiers, lowercase and/or
+// operators are accepted, "/" (an old Cargo convention) is read as OR and "," as
+// AND. ok is false when any part of the license has no SPDX identifier; callers
+// should then report the declared name as is.
+func Normalize(declared string) (expression string, ok bool) {
+	declared = strings.TrimSpace(declared)
+	if declared == "" || strings.ContainsAny(declared, "\r\n") {
+		return "", false
+	}
+
+	// Names such as "Apache License, Version 2.0" or "ISC License (ISCL)" contain
+	// characters that would otherwise be read as operators or grouping
+	if id, known := Lookup(declared); known {
+		return id, true
+	}
+
+	var out []string
+	var term []string
+	afterWith := false
+
+	flush := func() bool {
+		if len(term) == 0 {
+			return true
+		}
+		raw := strings.Join(term, " ")
+		term = nil
+
+		var id string
+		var known bool
+		if afterWith {
+			id, known = exceptionIDs[canonical(raw)]
+			afterWith = false
+		} else {
+			id, known = Lookup(raw)
+		}
+		if known && strings.Contains(id, " ") {
+			// Aliases such as "Perl" stand for a whole expression
+			id = "(" + id + ")"
+		}
+		if known {
+			out = append(out, id)
+		}
+		return known
+	}
+
+	for _, token := range tokenize(declared) {
+		switch op := operator(token); {
+		case op != "":
+			if !flush() {
+				return "", false
+			}
+			out = append(out, op)
+			afterWith = op == "WITH"
+		case token == "(" || token == ")":
+			if !flush() {
+				return "", false
+			}
+			out = append(out, token)
+		default:
+			term = append(term, token)
+		}
+	}
+	if !flush() || !wellFormed(out) {
+		return "", false
+	}
+
+	expression = strings.Join(out, " ")
+	expression = strings.ReplaceAll(expression, "( ", "(")
+	expression = strings.ReplaceAll(expression, " )", ")")
+	return expression, true
+}
+
+// Join combines several declared licenses that all apply to one package (trove
+// classifiers, pacman's LICENSE list, Debian copyright stanzas) into a single
+// SPDX AND expression. When any of them cannot be normalized the names are
+// joined with ", " so the declared licenses are still reported.
+func Join(declared []string) string {
+	return join(declared, "AND")
+}
+
+// JoinAny combines alternative licenses (a gemspec or composer.json license
+// list, where the user may pick any one) into a single SPDX OR expression. It
+// falls back to the declared names like Join.
+func JoinAny(declared []string) string {
+	return join(declared, "OR")
+}
+
+func join(declared []string, op string) string {
+	var parts []string
+	seen := make(map[string]bool)
+	normalized := true
+	for _, name := range declared {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		expression, ok := Normalize(name)
+		if !ok {
+			normalized = false
+			break
+		}
+		if !seen[expression] {
+			seen[expression] = true
+			parts = append(parts, expression)
+		}
+	}
+
+	if !normalized {
+		var names []string
+		for _, name := range declared {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	if len(parts) > 1 {
+		for i, part := range parts {
+			if strings.Contains(part, " ") {
+				parts[i] = "(" + part + ")"
+			}
+		}
+	}
+	return strings.Join(parts, " "+op+" ")
+}
+
+// IsSimple reports whether a normalized expression is a single SPDX license
+// identifier rather than a compound expression or a LicenseRef
+func IsSimple(expression string) bool {
+	return expression != "" && !strings.ContainsAny(expression, " ()+") &&
+		!strings.HasPrefix(expression, "LicenseRef-")
+}
+
+// Lookup returns the SPDX identifier for a single license name or identifier.
+// LicenseRef- identifiers are passed through unchanged.
+func Lookup(name string) (string, bool) {
+	name = strings.Trim(strings.TrimSpace(name), `"'`)
+	if name == "" {
+		return "", false
+	}
+	if strings.HasPrefix(name, "LicenseRef-") {
+		return name, true
+	}
+
+	key := canonical(name)
+	if id, ok := licenseIDs[key]; ok {
+		return id, true
+	}
+
+	// "GPL-2.0+", "GPLv3+": the or-later identifier where SPDX has one,
+	// otherwise the SPDX '+' operator
+	if base, found := strings.CutSuffix(key, "+"); found {
+		base = strings.TrimSpace(base)
+		if id, ok := licenseIDs[base+" or later"]; ok {
+			return id, true
+		}
+		if id, ok := licenseIDs[base]; ok {
+			return strings.TrimSuffix(id, "-only") + "+", true
+		}
+	}
+
+	return "", false
+}
+
+// canonical reduces a license name to a comparison key, so that spellings such
+// as "Apache-2.0", "Apache License, Version 2.0" and "apache 2" agree: lowercase,
+// punctuation folded to spaces, filler words dropped, "v2"/"2.0" reduced to "2"
+// and letters split from version numbers ("gplv3" becomes "gpl 3")
+func canonical(name string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range versionPrefix.ReplaceAllString(strings.ToLower(name), "$1 $2") {
+		switch {
+		case r == '-' || r == '_' || r == ',' || r == ':' || r == '(' || r == ')' || unicode.IsSpace(r):
+			r = ' '
+		case unicode.IsDigit(r) && unicode.IsLetter(prev):
+			b.WriteRune(' ')
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+
+	var words []string
+	for _, word := range strings.Fields(b.String()) {
+		switch word {
+		case "the", "license", "licence", "licensed", "version", "gnu", "v":
+			continue
+		}
+		if len(word) > 1 && word[0] == 'v' && unicode.IsDigit(rune(word[1])) {
+			word = word[1:]
+		}
+		word, plus := strings.CutSuffix(word, "+")
+		for strings.HasSuffix(word, ".0") && len(word) > 2 {
+			word = strings.TrimSuffix(word, ".0")
+		}
+		if plus {
+			word += "+"
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}
+
+// tokenize splits a license string into parentheses, operators and words.
+// "or later" / "or any later version" is folded into the preceding word as '+'
+// so it is not mistaken for the OR operator.
+func tokenize(s string) []string {
+	var words []string
+	var current strings.Builder
+
+	emit := func() {
+		if current.Len() > 0 {
+			words = append(words, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range s {
+		switch r {
+		case '(', ')':
+			emit()
+			words = append(words, string(r))
+		case '/', '|':
+			emit()
+			words = append(words, "OR")
+		case '&', ',', ';':
+			emit()
+			words = append(words, "AND")
+		default:
+			if unicode.IsSpace(r) {
+				emit()
+			} else {
+				current.WriteRune(r)
+			}
+		}
+	}
+	emit()
+
+	var tokens []string
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if strings.EqualFold(word, "or") && len(tokens) > 0 && operator(tokens[len(tokens)-1]) == "" {
+			if skip := orLaterLength(words[i+1:]); skip > 0 {
+				tokens[len(tokens)-1] += "+"
+				i += skip
+				continue
+			}
+		}
+
+		// Collapse repeated operators produced by "a, and b" or "a || b"
+		if op := operator(word); op != "" && len(tokens) > 0 && operator(tokens[len(tokens)-1]) != "" {
+			tokens[len(tokens)-1] = op
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// orLaterLength returns how many words after "or" spell "later", "newer" or
+// "any later version", or 0 when the "or" is an operator
+func orLaterLength(words []string) int {
+	n := 0
+	later := false
+	for _, word := range words {
+		switch strings.ToLower(word) {
+		case "any":
+		case "later", "newer":
+			later = true
+		case "version", "versions":
+			if !later {
+				return 0
+			}
+		default:
+			if later {
+				return n
+			}
+			return 0
+		}
+		n++
+	}
+	if later {
+		return n
+	}
+	return 0
+}
+
+// operator returns the SPDX operator a token stands for, or ""
+func operator(token string) string {
+	switch upper := strings.ToUpper(token); upper {
+	case "AND", "OR", "WITH":
+		return upper
+	}
+	return ""
+}
+
+// wellFormed reports whether parentheses match and operators sit between terms
+func wellFormed(tokens []string) bool {
+	depth := 0
+	expectTerm := true
+	for _, token := range tokens {
+		switch {
+		case token == "(":
+			if !expectTerm {
+				return false
+			}
+			depth++
+		case token == ")":
+			if expectTerm || depth == 0 {
+				return false
+			}
+			depth--
+		case operator(token) != "":
+			if expectTerm {
+				return false
+			}
+			expectTerm = true
+		default:
+			if !expectTerm {
+				return false
+			}
+			expectTerm = false
+		}
+	}
+	return depth == 0 && !expectTerm
+}
@@ -0,0 +1,56 @@
+package license
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// FromPackageJSON returns the license declared in a package.json (npm packages,
+// VS Code and Cursor extensions). The current "license" field is an SPDX
+// expression string; older packages use a {"type": ...} object or a "licenses"
+// array of them, which lists alternatives and so becomes an OR expression.
+func FromPackageJSON(license, licenses json.RawMessage) string {
+	if declared := licenseType(license); declared != "" {
+		return declared
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(licenses, &list); err != nil {
+		return licenseType(licenses)
+	}
+
+	var names []string
+	for _, entry := range list {
+		if name := licenseType(entry); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 1 {
+		for i, name := range names {
+			if strings.Contains(name, " ") {
+				names[i] = "(" + name + ")"
+			}
+		}
+	}
+	return strings.Join(names, " OR ")
+}
+
+// licenseType decodes a license given as a string or as a {"type": ...} object
+func licenseType(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return strings.TrimSpace(name)
+	}
+
+	var object struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &object); err == nil {
+		return strings.TrimSpace(object.Type)
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+// Package purl builds Package URLs (https://github.com/package-url/purl-spec)
+// for the components EndpointBOM reports.
+package purl
+
+import (
+	"sort"
+	"strings"
+)
+
+// PackageURL is a parsed-out purl: pkg:type/namespace/name@version?qualifiers#subpath
+type PackageURL struct {
+	Type       string
+	Namespace  string // May contain "/"-separated segments, e.g. a Go module path prefix
+	Name       string
+	Version    string
+	Qualifiers map[string]string // Empty values are omitted
+	Subpath    string
+}
+
+// String renders the purl in canonical form: the type lowercased, every
+// namespace and subpath segment, the name, version and qualifier values
+// percent-encoded, and qualifiers sorted by key.
+func (p PackageURL) String() string {
+	var b strings.Builder
+	b.WriteString("pkg:")
+	b.WriteString(strings.ToLower(p.Type))
+	b.WriteString("/")
+
+	for _, segment := range strings.Split(p.Namespace, "/") {
+		if segment != "" {
+			b.WriteString(escape(segment))
+			b.WriteString("/")
+		}
+	}
+	b.WriteString(escape(p.Name))
+
+	if p.Version != "" {
+		b.WriteString("@")
+		b.WriteString(escape(p.Version))
+	}
+
+	var keys []string
+	for key, value := range p.Qualifiers {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		if i == 0 {
+			b.WriteString("?")
+		} else {
+			b.WriteString("&")
+		}
+		b.WriteString(strings.ToLower(key))
+		b.WriteString("=")
+		b.WriteString(escape(p.Qualifiers[key]))
+	}
+
+	var subpath []string
+	for _, segment := range strings.Split(p.Subpath, "/") {
+		if segment != "" && segment != "." && segment != ".." {
+			subpath = append(subpath, escape(segment))
+		}
+	}
+	if len(subpath) > 0 {
+		b.WriteString("#")
+		b.WriteString(strings.Join(subpath, "/"))
+	}
+
+	return b.String()
+}
+
+// escape percent-encodes everything except RFC 3986 unreserved characters.
+// ':' is also left as-is: it is common in versions (deb epochs) and the spec
+// does not require encoding it.
+func escape(s string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '.', c == '_', c == '~', c == ':':
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0x0f])
+		}
+	}
+	return b.String()
+}
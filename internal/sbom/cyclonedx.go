@@ -1,75 +1,184 @@
 package sbom
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	cdx "github.com/CycloneDX/cyclonedx-go"
 	"github.com/google/uuid"
+	"github.com/eapolsniper/endpointbom/internal/license"
+	"github.com/eapolsniper/endpointbom/internal/purl"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/session"
 	"github.com/eapolsniper/endpointbom/internal/system"
 )
 
-// GenerateSBOMs creates CycloneDX SBOM files for different component categories
-func GenerateSBOMs(result *scanners.ScanResult, sysInfo *system.Info, outputDir string) error {
-	now := time.Now()
-	timestamp := now.Format("20060102-150405")
-	timezone := now.Format("MST") // Get timezone abbreviation (e.g., CST, EST, PST)
-	timestampWithTZ := fmt.Sprintf("%s-%s", timestamp, timezone)
+// Options controls how SBOM files are written
+type Options struct {
+	// Format is the output format (see Formats); empty selects DefaultFormat
+	Format string
+
+	// SingleBOM writes one document per endpoint, with a nested assembly
+	// component per category, instead of one document per category
+	SingleBOM bool
+
+	// DeterministicSerial derives each serial number from the document content
+	// (UUIDv5) instead of generating a random one, so unchanged scans keep their serial
+	DeterministicSerial bool
+}
+
+// scanCategory is one group of components that gets its own SBOM, or its own
+// assembly in single-BOM mode
+type scanCategory struct {
+	Name       string // scan_category value and file name part, e.g. "package-managers"
+	Label      string // used in messages, e.g. "package managers"
+	Components []scanners.Component
+}
+
+// resultCategories returns the scan result's categories in output order
+func resultCategories(result *scanners.ScanResult) []scanCategory {
+	return []scanCategory{
+		{Name: "package-managers", Label: "package managers", Components: result.PackageManagers},
+		{Name: "applications", Label: "applications", Components: result.Applications},
+		{Name: "ide-extensions", Label: "IDE extensions", Components: result.IDEExtensions},
+		{Name: "browser-extensions", Label: "browser extensions", Components: result.BrowserExtensions},
+	}
+}
+
+// GenerateSBOMs creates SBOM files for the scan result: one per component
+// category, or a single consolidated document when opts.SingleBOM is set. The
+// files are named after the scan session and recorded in it.
+func GenerateSBOMs(result *scanners.ScanResult, sysInfo *system.Info, scan *session.Session, outputDir string, opts Options) error {
+	writer, err := NewWriter(opts.Format)
+	if err != nil {
+		return err
+	}
+
 	hostname := sysInfo.Hostname
+	categories := resultCategories(result)
 
-	// Generate SBOM for package managers
-	if len(result.PackageManagers) > 0 {
-		filename := fmt.Sprintf("%s.%s.package-managers.cdx.json", hostname, timestampWithTZ)
-		if err := generateSBOM(result.PackageManagers, sysInfo, filepath.Join(outputDir, filename), "package-managers"); err != nil {
-			return fmt.Errorf("failed to generate package managers SBOM: %w", err)
+	if opts.SingleBOM {
+		total := 0
+		for _, category := range categories {
+			total += len(category.Components)
+		}
+		if total == 0 {
+			return nil
 		}
-		fmt.Printf("Generated: %s\n", filename)
-	}
 
-	// Generate SBOM for applications
-	if len(result.Applications) > 0 {
-		filename := fmt.Sprintf("%s.%s.applications.cdx.json", hostname, timestampWithTZ)
-		if err := generateSBOM(result.Applications, sysInfo, filepath.Join(outputDir, filename), "applications"); err != nil {
-			return fmt.Errorf("failed to generate applications SBOM: %w", err)
+		filename := scan.Filename("all", writer.Extension())
+		bom := buildSingleBOM(categories, result.Scanners, sysInfo, scan)
+		if opts.DeterministicSerial {
+			bom.SerialNumber = contentSerial(hostname, "all", bom)
+		}
+		if err := writeBOM(bom, filepath.Join(outputDir, filename), writer); err != nil {
+			return fmt.Errorf("failed to generate consolidated SBOM: %w", err)
 		}
+		scan.AddFile(filename)
 		fmt.Printf("Generated: %s\n", filename)
+		return nil
 	}
 
-	// Generate SBOM for IDE extensions
-	if len(result.IDEExtensions) > 0 {
-		filename := fmt.Sprintf("%s.%s.ide-extensions.cdx.json", hostname, timestampWithTZ)
-		if err := generateSBOM(result.IDEExtensions, sysInfo, filepath.Join(outputDir, filename), "ide-extensions"); err != nil {
-			return fmt.Errorf("failed to generate IDE extensions SBOM: %w", err)
+	for _, category := range categories {
+		if len(category.Components) == 0 {
+			continue
 		}
-		fmt.Printf("Generated: %s\n", filename)
-	}
 
-	// Generate SBOM for browser extensions
-	if len(result.BrowserExtensions) > 0 {
-		filename := fmt.Sprintf("%s.%s.browser-extensions.cdx.json", hostname, timestampWithTZ)
-		if err := generateSBOM(result.BrowserExtensions, sysInfo, filepath.Join(outputDir, filename), "browser-extensions"); err != nil {
-			return fmt.Errorf("failed to generate browser extensions SBOM: %w", err)
+		filename := scan.Filename(category.Name, writer.Extension())
+		bom := buildBOM(category.Components, result.Scanners, sysInfo, scan, category.Name)
+		if opts.DeterministicSerial {
+			bom.SerialNumber = contentSerial(hostname, category.Name, bom)
 		}
+		if err := writeBOM(bom, filepath.Join(outputDir, filename), writer); err != nil {
+			return fmt.Errorf("failed to generate %s SBOM: %w", category.Label, err)
+		}
+		scan.AddFile(filename)
 		fmt.Printf("Generated: %s\n", filename)
 	}
 
 	return nil
 }
 
-func generateSBOM(components []scanners.Component, sysInfo *system.Info, outputPath string, category string) error {
+// writeBOM writes a BOM to outputPath in the writer's format
+func writeBOM(bom *cdx.BOM, outputPath string, writer Writer) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create BOM file: %w", err)
+	}
+
+	if err := writer.Write(file, bom); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write BOM file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write BOM file: %w", err)
+	}
+
+	return nil
+}
+
+// buildBOM assembles the CycloneDX BOM for one category. It is the common model
+// every output format is written from.
+func buildBOM(components []scanners.Component, scannerRecords []scanners.ScannerRecord, sysInfo *system.Info, scan *session.Session, category string) *cdx.BOM {
 	// Create BOM
 	bom := cdx.NewBOM()
 	bom.SerialNumber = "urn:uuid:" + generateUUID()
 	bom.Version = 1
+	bom.Metadata = buildMetadata(sysInfo, scannerRecords, scan, category)
+	rootBomRef := bom.Metadata.Component.BOMRef
+
+	// Use a map to deduplicate components by bom-ref
+	components, services := splitServices(components)
+	componentMap := make(map[string]cdx.Component)
+	rootDependsOn, dependencies := convertComponents(components, componentMap)
+
+	// Services the endpoint talks to are listed beside its components
+	serviceMap := make(map[string]cdx.Service)
+	serviceRefs, serviceDeps := convertServices(services, serviceMap)
+	rootDependsOn = append(rootDependsOn, serviceRefs...)
+	dependencies = append(dependencies, serviceDeps...)
+	bom.Services = servicesFromMap(serviceMap)
 	
+	// Convert map to slice for BOM
+	var cdxComponents []cdx.Component
+	for _, comp := range componentMap {
+		cdxComponents = append(cdxComponents, comp)
+	}
+
+	if len(cdxComponents) > 0 {
+		bom.Components = &cdxComponents
+	}
+	
+	deduplicatedDeps := mergeDependencies(dependencies)
+	
+	// Add root dependency (device depends on all top-level components)
+	if len(rootDependsOn) > 0 {
+		deduplicatedDeps = append([]cdx.Dependency{{
+			Ref:          rootBomRef,
+			Dependencies: &rootDependsOn,
+		}}, deduplicatedDeps...)
+	}
+	
+	if len(deduplicatedDeps) > 0 {
+		bom.Dependencies = &deduplicatedDeps
+	}
+
+	sortBOM(bom)
+	return bom
+}
+
+// buildMetadata creates the BOM metadata: the device as root component, tagged
+// with the scan category, the scan session and the scanner provenance
+func buildMetadata(sysInfo *system.Info, scannerRecords []scanners.ScannerRecord, scan *session.Session, category string) *cdx.Metadata {
 	// Create root component with bom-ref
 	rootBomRef := fmt.Sprintf("device:%s", sysInfo.Hostname)
-	bom.Metadata = &cdx.Metadata{
-		Timestamp: time.Now().Format(time.RFC3339),
+	metadata := &cdx.Metadata{
+		Timestamp: scan.Started.Format(time.RFC3339),
 		Component: &cdx.Component{
 			BOMRef:  rootBomRef,
 			Type:    cdx.ComponentTypeDevice,
@@ -94,8 +203,8 @@ func generateSBOM(components []scanners.Component, sysInfo *system.Info, outputP
 
 	// Add logged-in users to metadata
 	if len(sysInfo.Users) > 0 {
-		for _, user := range sysInfo.Users {
-			*bom.Metadata.Component.Properties = append(*bom.Metadata.Component.Properties, cdx.Property{
+		for _, user := range sortedCopy(sysInfo.Users) {
+			*metadata.Component.Properties = append(*metadata.Component.Properties, cdx.Property{
 				Name:  "logged_in_user",
 				Value: user,
 			})
@@ -104,8 +213,8 @@ func generateSBOM(components []scanners.Component, sysInfo *system.Info, outputP
 
 	// Add network information to metadata
 	if len(sysInfo.LocalIPs) > 0 {
-		for _, ip := range sysInfo.LocalIPs {
-			*bom.Metadata.Component.Properties = append(*bom.Metadata.Component.Properties, cdx.Property{
+		for _, ip := range sortedCopy(sysInfo.LocalIPs) {
+			*metadata.Component.Properties = append(*metadata.Component.Properties, cdx.Property{
 				Name:  "local_ip",
 				Value: ip,
 			})
@@ -113,18 +222,39 @@ func generateSBOM(components []scanners.Component, sysInfo *system.Info, outputP
 	}
 
 	if sysInfo.PublicIP != "" && sysInfo.PublicIP != "unavailable" {
-		*bom.Metadata.Component.Properties = append(*bom.Metadata.Component.Properties, cdx.Property{
+		*metadata.Component.Properties = append(*metadata.Component.Properties, cdx.Property{
 			Name:  "public_ip",
 			Value: sysInfo.PublicIP,
 		})
 	}
 
-	// Convert components to CycloneDX components and build dependency graph
+	// Tie every BOM of the run together, then record which scanners ran so
+	// consumers can tell "none found" from "never scanned"
+	properties := []cdx.Property{{Name: "scan_id", Value: scan.ID}}
+	if !scan.Finished.IsZero() {
+		properties = append(properties, cdx.Property{Name: "scan_finished", Value: scan.Finished.Format(time.RFC3339)})
+	}
+	properties = append(properties, cdx.Property{Name: "redactions", Value: fmt.Sprintf("%d", scan.Redactions)})
+	properties = append(properties, buildProvenanceProperties(scannerRecords)...)
+	metadata.Properties = &properties
+
+	return metadata
+}
+
+// sortedCopy returns a sorted copy of values, leaving the original untouched
+func sortedCopy(values []string) []string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// convertComponents converts components into componentMap (deduplicated by
+// bom-ref) and returns the bom-refs of the top-level components along with the
+// dependency relationships of everything converted
+func convertComponents(components []scanners.Component, componentMap map[string]cdx.Component) ([]string, []cdx.Dependency) {
 	var dependencies []cdx.Dependency
-	rootDependsOnMap := make(map[string]bool) // Use map to deduplicate root dependencies
-	
-	// Use a map to deduplicate components by bom-ref
-	componentMap := make(map[string]cdx.Component)
+	var topLevel []string
+	seen := make(map[string]bool) // Deduplicate top-level bom-refs
 	
 	for _, comp := range components {
 		cdxComp, deps := convertToCycloneDXComponentWithDeps(comp, componentMap)
@@ -132,24 +262,20 @@ func generateSBOM(components []scanners.Component, sysInfo *system.Info, outputP
 		// Add to map (will deduplicate automatically)
 		componentMap[cdxComp.BOMRef] = cdxComp
 		
-		// Root device depends on all top-level components (deduplicated via map)
-		rootDependsOnMap[cdxComp.BOMRef] = true
+		if !seen[cdxComp.BOMRef] {
+			seen[cdxComp.BOMRef] = true
+			topLevel = append(topLevel, cdxComp.BOMRef)
+		}
 		
 		// Add this component's dependencies to the dependencies array
 		dependencies = append(dependencies, deps...)
 	}
-	
-	// Convert map to slice for BOM
-	var cdxComponents []cdx.Component
-	for _, comp := range componentMap {
-		cdxComponents = append(cdxComponents, comp)
-	}
 
-	if len(cdxComponents) > 0 {
-		bom.Components = &cdxComponents
-	}
-	
-	// Deduplicate dependencies by ref
+	return topLevel, dependencies
+}
+
+// mergeDependencies deduplicates dependency entries by ref, merging their dependsOn lists
+func mergeDependencies(dependencies []cdx.Dependency) []cdx.Dependency {
 	dependencyMap := make(map[string]cdx.Dependency)
 	for _, dep := range dependencies {
 		if existing, exists := dependencyMap[dep.Ref]; exists {
@@ -169,6 +295,8 @@ func generateSBOM(components []scanners.Component, sysInfo *system.Info, outputP
 				}
 				existing.Dependencies = &merged
 				dependencyMap[dep.Ref] = existing
+			} else if dep.Dependencies != nil {
+				dependencyMap[dep.Ref] = dep
 			}
 		} else {
 			dependencyMap[dep.Ref] = dep
@@ -180,34 +308,8 @@ func generateSBOM(components []scanners.Component, sysInfo *system.Info, outputP
 	for _, dep := range dependencyMap {
 		deduplicatedDeps = append(deduplicatedDeps, dep)
 	}
-	
-	// Add root dependency (device depends on all top-level components)
-	if len(rootDependsOnMap) > 0 {
-		rootDependsOn := make([]string, 0, len(rootDependsOnMap))
-		for ref := range rootDependsOnMap {
-			rootDependsOn = append(rootDependsOn, ref)
-		}
-		deduplicatedDeps = append([]cdx.Dependency{{
-			Ref:          rootBomRef,
-			Dependencies: &rootDependsOn,
-		}}, deduplicatedDeps...)
-	}
-	
-	if len(deduplicatedDeps) > 0 {
-		bom.Dependencies = &deduplicatedDeps
-	}
-
-	// Write to file
-	data, err := json.MarshalIndent(bom, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal BOM: %w", err)
-	}
-
-	if err := os.WriteFile(outputPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write BOM file: %w", err)
-	}
 
-	return nil
+	return deduplicatedDeps
 }
 
 // convertToCycloneDXComponentWithDeps converts a component and returns both the component and its dependency relationships
@@ -219,14 +321,23 @@ func convertToCycloneDXComponentWithDeps(comp scanners.Component, componentMap m
 	
 	// Check if we've already processed this component
 	if existingComp, exists := componentMap[bomRef]; exists {
-		// Component already exists, just return it (no need to process dependencies again)
-		return existingComp, []cdx.Dependency{}
+		// The same package found again (e.g. in another project): record this
+		// install location and merge in any dependencies this copy lists. A
+		// copy whose location is already recorded was walked before, so its
+		// dependencies are only linked; a copy elsewhere is walked so that its
+		// dependencies record their own locations too.
+		location := occurrenceLocation(comp)
+		walked := location == "" || hasOccurrence(existingComp, location)
+		componentMap[bomRef] = mergeCopy(existingComp, comp)
+		allDependencies = convertDependencies(comp, bomRef, componentMap, walked)
+		return componentMap[bomRef], allDependencies
 	}
 	
 	cdxComp := cdx.Component{
-		BOMRef:  bomRef,
-		Name:    comp.Name,
-		Version: comp.Version,
+		BOMRef:     bomRef,
+		Name:       comp.Name,
+		Version:    comp.Version,
+		PackageURL: componentPURL(comp),
 	}
 
 	// Map component type
@@ -241,6 +352,8 @@ func convertToCycloneDXComponentWithDeps(comp scanners.Component, componentMap m
 		cdxComp.Type = cdx.ComponentTypeLibrary // Browser extensions are library-like
 	case "mcp-server":
 		cdxComp.Type = cdx.ComponentTypeApplication // MCP servers as applications
+	case "container":
+		cdxComp.Type = cdx.ComponentTypeContainer
 	default:
 		cdxComp.Type = cdx.ComponentTypeLibrary
 	}
@@ -289,13 +402,46 @@ func convertToCycloneDXComponentWithDeps(comp scanners.Component, componentMap m
 		cdxComp.Properties = &props
 	}
 
+	cdxComp.Licenses = convertLicense(comp.License)
+	mergeSupplierInfo(&cdxComp, comp)
+
+	// Add digests and how the scanner identified the component
+	cdxComp.Hashes = convertHashes(comp.Hashes)
+	if comp.Identity != nil {
+		cdxComp.Evidence = &cdx.Evidence{Identity: convertIdentity(*comp.Identity)}
+	}
+
+	// Record where this copy is installed; later copies add their own occurrences
+	cdxComp = addOccurrence(cdxComp, occurrenceLocation(comp))
+
 	// Add this component to the map early to prevent circular dependency issues
 	componentMap[bomRef] = cdxComp
 	
+	allDependencies = convertDependencies(comp, bomRef, componentMap, false)
+
+	// Return the stored copy, which may have gained occurrences through a cycle
+	return componentMap[bomRef], allDependencies
+}
+
+// convertDependencies converts a component's dependencies recursively and returns
+// their relationships along with the component's own dependency entry. With
+// skipKnown set, dependencies already in componentMap are merged and linked but
+// not walked again.
+func convertDependencies(comp scanners.Component, bomRef string, componentMap map[string]cdx.Component, skipKnown bool) []cdx.Dependency {
+	var allDependencies []cdx.Dependency
+
 	// Process dependencies recursively
 	var dependsOn []string
 	if len(comp.Dependencies) > 0 {
 		for _, dep := range comp.Dependencies {
+			if skipKnown {
+				if depRef := generateBomRef(dep); componentMap[depRef].BOMRef != "" {
+					componentMap[depRef] = mergeCopy(componentMap[depRef], dep)
+					dependsOn = append(dependsOn, depRef)
+					continue
+				}
+			}
+
 			depComp, depDeps := convertToCycloneDXComponentWithDeps(dep, componentMap)
 			
 			// Add dependency bom-ref to this component's dependsOn list
@@ -322,37 +468,281 @@ func convertToCycloneDXComponentWithDeps(comp scanners.Component, componentMap m
 		})
 	}
 
-	return cdxComp, allDependencies
+	return allDependencies
+}
+
+// convertLicense converts a declared license into CycloneDX licenses: an SPDX
+// identifier or expression when it can be normalized. Otherwise each
+// comma-separated part becomes its own license, by SPDX identifier where one
+// is known and by declared name where not.
+func convertLicense(declared string) *cdx.Licenses {
+	// Keep only the first line of license text pasted into metadata fields
+	declared, _, _ = strings.Cut(declared, "\n")
+	declared = strings.TrimSpace(declared)
+	if declared == "" {
+		return nil
+	}
+
+	if expression, ok := license.Normalize(declared); ok {
+		if license.IsSimple(expression) {
+			return &cdx.Licenses{{License: &cdx.License{ID: expression}}}
+		}
+		return &cdx.Licenses{{Expression: expression}}
+	}
+
+	var licenses cdx.Licenses
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(declared, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || seen[part] {
+			continue
+		}
+		seen[part] = true
+
+		if id, ok := license.Normalize(part); ok && license.IsSimple(id) {
+			if !seen[id] {
+				seen[id] = true
+				licenses = append(licenses, cdx.LicenseChoice{License: &cdx.License{ID: id}})
+			}
+			continue
+		}
+		licenses = append(licenses, cdx.LicenseChoice{License: &cdx.License{Name: part}})
+	}
+	return &licenses
+}
+
+// mergeSupplierInfo fills in the supplier, author and publisher a component does
+// not have yet and adds the external references it is missing
+func mergeSupplierInfo(cdxComp *cdx.Component, comp scanners.Component) {
+	if cdxComp.Supplier == nil && comp.Supplier != "" {
+		cdxComp.Supplier = &cdx.OrganizationalEntity{Name: comp.Supplier}
+	}
+	if cdxComp.Author == "" {
+		cdxComp.Author = comp.Author
+	}
+	if cdxComp.Publisher == "" {
+		cdxComp.Publisher = comp.Publisher
+	}
+
+	if len(comp.ExternalRefs) == 0 {
+		return
+	}
+	var refs []cdx.ExternalReference
+	if cdxComp.ExternalReferences != nil {
+		refs = *cdxComp.ExternalReferences
+	}
+	for _, ref := range comp.ExternalRefs {
+		known := false
+		for _, existing := range refs {
+			if string(existing.Type) == ref.Type && existing.URL == ref.URL {
+				known = true
+				break
+			}
+		}
+		if !known {
+			refs = append(refs, cdx.ExternalReference{Type: cdx.ExternalReferenceType(ref.Type), URL: ref.URL})
+		}
+	}
+	cdxComp.ExternalReferences = &refs
+}
+
+// convertHashes converts digests keyed by algorithm into CycloneDX hashes, sorted by algorithm
+func convertHashes(hashes map[string]string) *[]cdx.Hash {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	result := make([]cdx.Hash, 0, len(hashes))
+	for algorithm, value := range hashes {
+		result = append(result, cdx.Hash{
+			Algorithm: cdx.HashAlgorithm(algorithm),
+			Value:     value,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Algorithm < result[j].Algorithm
+	})
+	return &result
+}
+
+// convertIdentity converts identity evidence; the field defaults to the component name
+func convertIdentity(identity scanners.Identity) *cdx.EvidenceIdentity {
+	field := cdx.EvidenceIdentityFieldTypeName
+	if identity.Field != "" {
+		field = cdx.EvidenceIdentityFieldType(identity.Field)
+	}
+
+	confidence := identity.Confidence
+	methods := []cdx.EvidenceIdentityMethod{{
+		Technique:  cdx.EvidenceIdentityTechnique(identity.Technique),
+		Confidence: &confidence,
+		Value:      identity.Value,
+	}}
+
+	return &cdx.EvidenceIdentity{
+		Field:      field,
+		Confidence: &confidence,
+		Methods:    &methods,
+	}
+}
+
+// occurrenceLocation returns where on disk a component was found: the most
+// specific path its scanner recorded, falling back to its location
+func occurrenceLocation(comp scanners.Component) string {
+	for _, key := range []string{"install_path", "metadata_path"} {
+		if path := comp.Properties[key]; path != "" {
+			return path
+		}
+	}
+	return comp.Location
+}
+
+// mergeCopy merges another copy of a converted component into it: what the
+// first copy lacked is filled in, the properties and description that only held
+// for one location are dropped and the copy's location is recorded
+func mergeCopy(cdxComp cdx.Component, comp scanners.Component) cdx.Component {
+	if cdxComp.Hashes == nil {
+		cdxComp.Hashes = convertHashes(comp.Hashes)
+	}
+	if cdxComp.Licenses == nil {
+		cdxComp.Licenses = convertLicense(comp.License)
+	}
+	mergeSupplierInfo(&cdxComp, comp)
+
+	hadProject := hasProperty(cdxComp, "project_path")
+	cdxComp = dropPerLocationProperties(cdxComp, comp)
+	if hadProject && !hasProperty(cdxComp, "project_path") {
+		// The description names the first copy's project
+		cdxComp.Description = buildDescription(withoutProperty(comp, "project_path"))
+	}
+
+	return addOccurrence(cdxComp, occurrenceLocation(comp))
+}
+
+// hasProperty reports whether a converted component has the named property
+func hasProperty(cdxComp cdx.Component, name string) bool {
+	if cdxComp.Properties == nil {
+		return false
+	}
+	for _, prop := range *cdxComp.Properties {
+		if prop.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutProperty returns a copy of comp without the named property
+func withoutProperty(comp scanners.Component, name string) scanners.Component {
+	props := make(map[string]string, len(comp.Properties))
+	for key, value := range comp.Properties {
+		if key != name {
+			props[key] = value
+		}
+	}
+	comp.Properties = props
+	return comp
+}
+
+// hasOccurrence reports whether a location is already among a component's occurrences
+func hasOccurrence(cdxComp cdx.Component, location string) bool {
+	if cdxComp.Evidence == nil || cdxComp.Evidence.Occurrences == nil {
+		return false
+	}
+	for _, occurrence := range *cdxComp.Evidence.Occurrences {
+		if occurrence.Location == location {
+			return true
+		}
+	}
+	return false
+}
+
+// perLocationProperties describe where one copy of a component was found. The
+// occurrences list every copy, so these are dropped once copies found elsewhere
+// are merged into one component.
+var perLocationProperties = map[string]bool{
+	"location":      true,
+	"project_path":  true,
+	"install_path":  true,
+	"metadata_path": true,
+}
+
+// dropPerLocationProperties removes the per-location properties of cdxComp that
+// do not also hold for the copy comp being merged into it
+func dropPerLocationProperties(cdxComp cdx.Component, comp scanners.Component) cdx.Component {
+	if cdxComp.Properties == nil {
+		return cdxComp
+	}
+
+	var props []cdx.Property
+	for _, prop := range *cdxComp.Properties {
+		if perLocationProperties[prop.Name] {
+			value := comp.Properties[prop.Name]
+			// Dependencies of a project carry its path as their location only
+			if prop.Name == "location" || (prop.Name == "project_path" && value == "") {
+				value = comp.Location
+			}
+			if value != prop.Value {
+				continue
+			}
+		}
+		props = append(props, prop)
+	}
+	cdxComp.Properties = &props
+	return cdxComp
+}
+
+// addOccurrence records an install location as CycloneDX evidence, so a package
+// installed in several places keeps one bom-ref (for vulnerability matching)
+// while every copy on disk can still be found
+func addOccurrence(comp cdx.Component, location string) cdx.Component {
+	if location == "" {
+		return comp
+	}
+
+	if comp.Evidence == nil {
+		comp.Evidence = &cdx.Evidence{}
+	}
+
+	var occurrences []cdx.EvidenceOccurrence
+	if comp.Evidence.Occurrences != nil {
+		for _, occurrence := range *comp.Evidence.Occurrences {
+			if occurrence.Location == location {
+				return comp
+			}
+		}
+		occurrences = append(occurrences, *comp.Evidence.Occurrences...)
+	}
+
+	occurrences = append(occurrences, cdx.EvidenceOccurrence{Location: location})
+	evidence := *comp.Evidence
+	evidence.Occurrences = &occurrences
+	comp.Evidence = &evidence
+	return comp
+}
+
+// componentPURL returns the component's Package URL: the one the scanner set
+// (e.g. OS packages with distro qualifiers), or one built from its package
+// manager. Components without a package manager have none.
+func componentPURL(comp scanners.Component) string {
+	if comp.PackageURL != "" {
+		return comp.PackageURL
+	}
+	if comp.PackageManager == "" {
+		return ""
+	}
+
+	if p, ok := purl.ForPackage(comp.PackageManager, comp.Name, comp.Version, comp.Properties); ok {
+		return p.String()
+	}
+	return purl.PackageURL{Type: "generic", Name: comp.Name, Version: comp.Version}.String()
 }
 
 // generateBomRef creates a unique bom-ref for a component
 func generateBomRef(comp scanners.Component) string {
 	// Use Package URL (purl) format when we have package manager info
-	if comp.PackageManager != "" {
-		// Map package manager to purl type
-		purlType := comp.PackageManager
-		switch comp.PackageManager {
-		case "npm":
-			purlType = "npm"
-		case "pip", "pip-local":
-			purlType = "pypi"
-		case "gem", "gem-local":
-			purlType = "gem"
-		case "brew":
-			purlType = "brew"
-		case "cargo":
-			purlType = "cargo"
-		case "go":
-			purlType = "golang"
-		case "composer":
-			purlType = "composer"
-		}
-		
-		// Build purl: pkg:type/name@version
-		if comp.Version != "" {
-			return fmt.Sprintf("pkg:%s/%s@%s", purlType, comp.Name, comp.Version)
-		}
-		return fmt.Sprintf("pkg:%s/%s", purlType, comp.Name)
+	if p := componentPURL(comp); p != "" {
+		return p
 	}
 	
 	// For applications and extensions, use a descriptive format
@@ -377,6 +767,15 @@ func generateBomRef(comp scanners.Component) string {
 		return fmt.Sprintf("ide-ext:%s", comp.Name)
 	}
 	
+	// Servers of the same name configured in different clients or projects are distinct
+	if comp.Type == "mcp-server" {
+		return withConfigFile(fmt.Sprintf("mcp:%s:%s", comp.Properties["ide"], comp.Name), comp)
+	}
+	
+	if comp.Type == "service" {
+		return serviceBomRef(comp)
+	}
+	
 	// Fallback: use name@version or just name
 	if comp.Version != "" {
 		return fmt.Sprintf("%s@%s", comp.Name, comp.Version)
@@ -384,6 +783,27 @@ func generateBomRef(comp scanners.Component) string {
 	return comp.Name
 }
 
+// buildProvenanceProperties converts scanner records into BOM metadata properties.
+// Each scanner gets scanner:<name>:status, :components and :duration_ms entries,
+// plus :error when the scanner did not complete successfully.
+func buildProvenanceProperties(records []scanners.ScannerRecord) []cdx.Property {
+	var props []cdx.Property
+
+	for _, record := range records {
+		prefix := fmt.Sprintf("scanner:%s:", record.Name)
+		props = append(props,
+			cdx.Property{Name: prefix + "status", Value: record.Status},
+			cdx.Property{Name: prefix + "components", Value: fmt.Sprintf("%d", record.Components)},
+			cdx.Property{Name: prefix + "duration_ms", Value: fmt.Sprintf("%d", record.DurationMS)},
+		)
+		if record.Error != "" {
+			props = append(props, cdx.Property{Name: prefix + "error", Value: record.Error})
+		}
+	}
+
+	return props
+}
+
 // buildDescription creates an enhanced description based on component type and properties
 func buildDescription(comp scanners.Component) string {
 	var description string
@@ -0,0 +1,183 @@
+package sbom
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// npmPackage returns a package installed in a project's node_modules
+func npmPackage(project, name, version string, dependencies ...scanners.Component) scanners.Component {
+	return scanners.Component{
+		Type:           "library",
+		Name:           name,
+		Version:        version,
+		PackageManager: "npm",
+		Location:       project,
+		Properties: map[string]string{
+			"install_path": project + "/node_modules/" + name,
+		},
+		Dependencies: dependencies,
+	}
+}
+
+// projectPackage returns a project's direct dependency, which carries the project path
+func projectPackage(project, name, version string, dependencies ...scanners.Component) scanners.Component {
+	comp := npmPackage(project, name, version, dependencies...)
+	comp.Properties["project_path"] = project
+	return comp
+}
+
+func occurrences(comp cdx.Component) []string {
+	var locations []string
+	if comp.Evidence != nil && comp.Evidence.Occurrences != nil {
+		for _, occurrence := range *comp.Evidence.Occurrences {
+			locations = append(locations, occurrence.Location)
+		}
+	}
+	sort.Strings(locations)
+	return locations
+}
+
+func propertyValue(comp cdx.Component, name string) string {
+	if comp.Properties == nil {
+		return ""
+	}
+	for _, prop := range *comp.Properties {
+		if prop.Name == name {
+			return prop.Value
+		}
+	}
+	return ""
+}
+
+func TestConvertComponentsMergesSharedDependencies(t *testing.T) {
+	var components []scanners.Component
+	for _, project := range []string{"/a", "/b"} {
+		lodash := npmPackage(project, "lodash", "4.17.21")
+		bodyParser := npmPackage(project, "body-parser", "1.20.2", lodash)
+		components = append(components, projectPackage(project, "express", "4.19.2", bodyParser, lodash))
+	}
+
+	componentMap := make(map[string]cdx.Component)
+	topLevel, dependencies := convertComponents(components, componentMap)
+
+	if len(topLevel) != 1 || topLevel[0] != "pkg:npm/express@4.19.2" {
+		t.Fatalf("top-level refs = %v, want only express", topLevel)
+	}
+
+	tests := []struct {
+		ref  string
+		want []string
+	}{
+		{"pkg:npm/express@4.19.2", []string{"/a/node_modules/express", "/b/node_modules/express"}},
+		{"pkg:npm/body-parser@1.20.2", []string{"/a/node_modules/body-parser", "/b/node_modules/body-parser"}},
+		{"pkg:npm/lodash@4.17.21", []string{"/a/node_modules/lodash", "/b/node_modules/lodash"}},
+	}
+	for _, tt := range tests {
+		comp, ok := componentMap[tt.ref]
+		if !ok {
+			t.Errorf("%s missing from the BOM", tt.ref)
+			continue
+		}
+		if got := occurrences(comp); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s occurrences = %v, want %v", tt.ref, got, tt.want)
+		}
+		for _, name := range []string{"location", "project_path", "install_path"} {
+			if value := propertyValue(comp, name); value != "" {
+				t.Errorf("%s kept the %s of one copy: %q", tt.ref, name, value)
+			}
+		}
+	}
+
+	if description := componentMap["pkg:npm/express@4.19.2"].Description; strings.Contains(description, "/a") {
+		t.Errorf("express description names the first project: %q", description)
+	}
+
+	edges := make(map[string]bool)
+	for _, dep := range mergeDependencies(dependencies) {
+		if dep.Dependencies != nil {
+			for _, child := range *dep.Dependencies {
+				edges[dep.Ref+" -> "+child] = true
+			}
+		}
+	}
+	for _, edge := range []string{
+		"pkg:npm/express@4.19.2 -> pkg:npm/body-parser@1.20.2",
+		"pkg:npm/express@4.19.2 -> pkg:npm/lodash@4.17.21",
+		"pkg:npm/body-parser@1.20.2 -> pkg:npm/lodash@4.17.21",
+	} {
+		if !edges[edge] {
+			t.Errorf("missing dependency %s", edge)
+		}
+	}
+}
+
+func TestConvertComponentsKeepsSingleLocation(t *testing.T) {
+	// The same copy listed twice, as lockfile trees do for deduplicated
+	// packages, keeps its location
+	lodash := npmPackage("/a", "lodash", "4.17.21")
+	components := []scanners.Component{
+		projectPackage("/a", "express", "4.19.2", lodash),
+		projectPackage("/a", "express", "4.19.2", lodash),
+	}
+
+	componentMap := make(map[string]cdx.Component)
+	convertComponents(components, componentMap)
+
+	express := componentMap["pkg:npm/express@4.19.2"]
+	if got := propertyValue(express, "project_path"); got != "/a" {
+		t.Errorf("project_path = %q, want /a", got)
+	}
+	if got := occurrences(componentMap["pkg:npm/lodash@4.17.21"]); len(got) != 1 {
+		t.Errorf("lodash occurrences = %v, want one", got)
+	}
+}
+
+func TestConvertComponentsKeepsServersPerConfigFile(t *testing.T) {
+	server := func(config, pkg string) scanners.Component {
+		return scanners.Component{
+			Type:       "mcp-server",
+			Name:       "github",
+			Location:   config,
+			Properties: map[string]string{"ide": "vscode", "command": "npx"},
+			Dependencies: []scanners.Component{{
+				Type:           "library",
+				Name:           pkg,
+				Version:        "1.0.0",
+				PackageManager: "npm",
+			}},
+		}
+	}
+
+	componentMap := make(map[string]cdx.Component)
+	topLevel, dependencies := convertComponents([]scanners.Component{
+		server("/a/.vscode/mcp.json", "server-a"),
+		server("/b/.vscode/mcp.json", "server-b"),
+	}, componentMap)
+
+	if len(topLevel) != 2 {
+		t.Fatalf("top-level refs = %v, want one server per configuration file", topLevel)
+	}
+
+	want := map[string]string{
+		"mcp:vscode:github@/a/.vscode/mcp.json": "pkg:npm/server-a@1.0.0",
+		"mcp:vscode:github@/b/.vscode/mcp.json": "pkg:npm/server-b@1.0.0",
+	}
+	for _, dep := range mergeDependencies(dependencies) {
+		pkg, ok := want[dep.Ref]
+		if !ok {
+			continue
+		}
+		if dep.Dependencies == nil || len(*dep.Dependencies) != 1 || (*dep.Dependencies)[0] != pkg {
+			t.Errorf("%s depends on %v, want only %s", dep.Ref, dep.Dependencies, pkg)
+		}
+		delete(want, dep.Ref)
+	}
+	for ref := range want {
+		t.Errorf("no dependency entry for %s", ref)
+	}
+}
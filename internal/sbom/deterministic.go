@@ -0,0 +1,94 @@
+package sbom
+
+import (
+	"encoding/json"
+	"sort"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/google/uuid"
+)
+
+// serialNamespace is the UUIDv5 namespace content-derived serial numbers are generated in
+var serialNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/eapolsniper/endpointbom/serial"))
+
+// sortBOM orders components (recursively, by bom-ref) and services (by bom-ref),
+// their properties (by name, then value), their occurrences (by location) and
+// dependencies (by ref, with sorted dependsOn lists) so that identical machine
+// state produces identical documents. The device's own dependency entry stays first.
+func sortBOM(bom *cdx.BOM) {
+	sortComponents(bom.Components)
+	sortServices(bom.Services)
+
+	if bom.Dependencies == nil {
+		return
+	}
+
+	var rootRef string
+	if bom.Metadata != nil && bom.Metadata.Component != nil {
+		rootRef = bom.Metadata.Component.BOMRef
+	}
+
+	deps := *bom.Dependencies
+	for _, dep := range deps {
+		if dep.Dependencies != nil {
+			sort.Strings(*dep.Dependencies)
+		}
+	}
+	sort.SliceStable(deps, func(i, j int) bool {
+		if (deps[i].Ref == rootRef) != (deps[j].Ref == rootRef) {
+			return deps[i].Ref == rootRef
+		}
+		return deps[i].Ref < deps[j].Ref
+	})
+}
+
+func sortComponents(components *[]cdx.Component) {
+	if components == nil {
+		return
+	}
+
+	list := *components
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].BOMRef < list[j].BOMRef
+	})
+
+	for i := range list {
+		if list[i].Properties != nil {
+			props := *list[i].Properties
+			sort.SliceStable(props, func(a, b int) bool {
+				if props[a].Name != props[b].Name {
+					return props[a].Name < props[b].Name
+				}
+				return props[a].Value < props[b].Value
+			})
+		}
+		if list[i].Evidence != nil && list[i].Evidence.Occurrences != nil {
+			occurrences := *list[i].Evidence.Occurrences
+			sort.SliceStable(occurrences, func(a, b int) bool {
+				return occurrences[a].Location < occurrences[b].Location
+			})
+		}
+		sortComponents(list[i].Components)
+	}
+}
+
+// contentSerial derives a UUIDv5 serial number from the hostname, the scan
+// category and the sorted components, services and dependencies serialized as
+// JSON, so a rescan of an unchanged endpoint keeps its serial number while any
+// change to a component (a version, hash, property or location) gives a new
+// one. The metadata, which holds the timestamp, scan ID and scanner durations,
+// is left out. bom must already be sorted.
+func contentSerial(hostname, category string, bom *cdx.BOM) string {
+	content, err := json.Marshal(struct {
+		Components   *[]cdx.Component  `json:"components,omitempty"`
+		Services     *[]cdx.Service    `json:"services,omitempty"`
+		Dependencies *[]cdx.Dependency `json:"dependencies,omitempty"`
+	}{bom.Components, bom.Services, bom.Dependencies})
+	if err != nil {
+		// Every field marshals; keep a unique serial rather than fail the write
+		return bom.SerialNumber
+	}
+
+	name := hostname + "\n" + category + "\n" + string(content)
+	return "urn:uuid:" + uuid.NewSHA1(serialNamespace, []byte(name)).String()
+}
@@ -0,0 +1,158 @@
+package sbom
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"sort"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// splitServices separates "service" components, such as remote MCP servers,
+// from the components installed on the endpoint
+func splitServices(components []scanners.Component) ([]scanners.Component, []scanners.Component) {
+	var installed, services []scanners.Component
+	for _, comp := range components {
+		if comp.Type == "service" {
+			services = append(services, comp)
+		} else {
+			installed = append(installed, comp)
+		}
+	}
+	return installed, services
+}
+
+// convertServices converts service components into serviceMap (deduplicated by
+// bom-ref, so a configuration file read twice adds its services once) and
+// returns their bom-refs along with a dependency entry for each
+func convertServices(services []scanners.Component, serviceMap map[string]cdx.Service) ([]string, []cdx.Dependency) {
+	var refs []string
+	var dependencies []cdx.Dependency
+
+	for _, comp := range services {
+		service := convertToCycloneDXService(comp)
+		if _, exists := serviceMap[service.BOMRef]; exists {
+			continue
+		}
+		serviceMap[service.BOMRef] = service
+		refs = append(refs, service.BOMRef)
+		dependencies = append(dependencies, cdx.Dependency{Ref: service.BOMRef})
+	}
+
+	return refs, dependencies
+}
+
+// servicesFromMap returns the services of serviceMap, or nil when there are none
+func servicesFromMap(serviceMap map[string]cdx.Service) *[]cdx.Service {
+	if len(serviceMap) == 0 {
+		return nil
+	}
+	services := make([]cdx.Service, 0, len(serviceMap))
+	for _, service := range serviceMap {
+		services = append(services, service)
+	}
+	return &services
+}
+
+// convertToCycloneDXService converts a service component. The "authenticated"
+// property becomes the service's authenticated flag; the other properties and
+// the configuration file it was found in are kept as properties.
+func convertToCycloneDXService(comp scanners.Component) cdx.Service {
+	service := cdx.Service{
+		BOMRef:      generateBomRef(comp),
+		Name:        comp.Name,
+		Version:     comp.Version,
+		Group:       comp.Group,
+		Description: comp.Description,
+	}
+
+	if len(comp.Endpoints) > 0 {
+		endpoints := append([]string(nil), comp.Endpoints...)
+		service.Endpoints = &endpoints
+		service.CrossesTrustBoundary = crossesTrustBoundary(comp.Endpoints)
+	}
+
+	props := []cdx.Property{{Name: "component_type", Value: comp.Type}}
+	for key, value := range comp.Properties {
+		if key == "authenticated" {
+			authenticated := value == "true"
+			service.Authenticated = &authenticated
+			continue
+		}
+		props = append(props, cdx.Property{Name: key, Value: value})
+	}
+	if comp.Location != "" {
+		props = append(props, cdx.Property{Name: "location", Value: comp.Location})
+	}
+	service.Properties = &props
+
+	return service
+}
+
+// crossesTrustBoundary reports whether any endpoint is off the endpoint itself,
+// i.e. not on a loopback host
+func crossesTrustBoundary(endpoints []string) *bool {
+	crosses := false
+	for _, endpoint := range endpoints {
+		parsed, err := url.Parse(endpoint)
+		if err != nil {
+			continue
+		}
+		host := parsed.Hostname()
+		if host == "localhost" {
+			continue
+		}
+		if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+			continue
+		}
+		crosses = true
+	}
+	return &crosses
+}
+
+// serviceBomRef names a service after the client that references it and the
+// configuration file it is defined in, since the same name may be configured
+// in several projects, with different endpoints, and each is reported
+func serviceBomRef(comp scanners.Component) string {
+	ref := "service:" + comp.Name
+	if client := comp.Properties["ide"]; client != "" {
+		ref = fmt.Sprintf("service:%s:%s", client, comp.Name)
+	}
+	return withConfigFile(ref, comp)
+}
+
+// withConfigFile appends the configuration file a server is defined in, its
+// location, to a bom-ref
+func withConfigFile(ref string, comp scanners.Component) string {
+	if comp.Location != "" {
+		ref += "@" + filepath.ToSlash(comp.Location)
+	}
+	return ref
+}
+
+// sortServices orders services by bom-ref and their properties by name, then value
+func sortServices(services *[]cdx.Service) {
+	if services == nil {
+		return
+	}
+
+	list := *services
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].BOMRef < list[j].BOMRef
+	})
+
+	for i := range list {
+		if list[i].Properties != nil {
+			props := *list[i].Properties
+			sort.SliceStable(props, func(a, b int) bool {
+				if props[a].Name != props[b].Name {
+					return props[a].Name < props[b].Name
+				}
+				return props[a].Value < props[b].Value
+			})
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package sbom
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/session"
+	"github.com/eapolsniper/endpointbom/internal/system"
+)
+
+// buildSingleBOM assembles one BOM for the whole endpoint. The device stays the
+// root; each non-empty category becomes an assembly component that nests every
+// component first reached from that category. Components shared between
+// categories appear once and are referenced by bom-ref from the others.
+// Services are listed at the top level and referenced by their category.
+func buildSingleBOM(categories []scanCategory, scannerRecords []scanners.ScannerRecord, sysInfo *system.Info, scan *session.Session) *cdx.BOM {
+	bom := cdx.NewBOM()
+	bom.SerialNumber = "urn:uuid:" + generateUUID()
+	bom.Version = 1
+	bom.Metadata = buildMetadata(sysInfo, scannerRecords, scan, "all")
+	rootBomRef := bom.Metadata.Component.BOMRef
+
+	componentMap := make(map[string]cdx.Component)
+	serviceMap := make(map[string]cdx.Service)
+	owner := make(map[string]string) // bom-ref -> category that first reached it
+	ownedRefs := make(map[string][]string)
+	var dependencies []cdx.Dependency
+	var rootDependsOn []string
+	var included []scanCategory
+
+	for _, category := range categories {
+		if len(category.Components) == 0 {
+			continue
+		}
+		included = append(included, category)
+
+		components, services := splitServices(category.Components)
+		topLevel, deps := convertComponents(components, componentMap)
+		dependencies = append(dependencies, deps...)
+
+		serviceRefs, serviceDeps := convertServices(services, serviceMap)
+		topLevel = append(topLevel, serviceRefs...)
+		dependencies = append(dependencies, serviceDeps...)
+
+		for ref := range componentMap {
+			if _, exists := owner[ref]; !exists {
+				owner[ref] = category.Name
+				ownedRefs[category.Name] = append(ownedRefs[category.Name], ref)
+			}
+		}
+
+		assemblyRef := categoryBomRef(category.Name)
+		rootDependsOn = append(rootDependsOn, assemblyRef)
+		dependencies = append(dependencies, cdx.Dependency{
+			Ref:          assemblyRef,
+			Dependencies: &topLevel,
+		})
+	}
+
+	dependencies = append(dependencies, linkInstalledApplications(categories, componentMap)...)
+
+	var assemblies []cdx.Component
+	for _, category := range included {
+		var nested []cdx.Component
+		for _, ref := range ownedRefs[category.Name] {
+			nested = append(nested, componentMap[ref])
+		}
+
+		assembly := cdx.Component{
+			BOMRef:      categoryBomRef(category.Name),
+			Type:        cdx.ComponentTypeApplication,
+			Name:        category.Name,
+			Description: fmt.Sprintf("Installed %s on %s", category.Label, sysInfo.Hostname),
+			Properties: &[]cdx.Property{
+				{Name: "scan_category", Value: category.Name},
+			},
+		}
+		if len(nested) > 0 {
+			assembly.Components = &nested
+		}
+		assemblies = append(assemblies, assembly)
+	}
+
+	if len(assemblies) > 0 {
+		bom.Components = &assemblies
+	}
+	bom.Services = servicesFromMap(serviceMap)
+
+	deduplicatedDeps := mergeDependencies(dependencies)
+	if len(rootDependsOn) > 0 {
+		deduplicatedDeps = append([]cdx.Dependency{{
+			Ref:          rootBomRef,
+			Dependencies: &rootDependsOn,
+		}}, deduplicatedDeps...)
+	}
+	if len(deduplicatedDeps) > 0 {
+		bom.Dependencies = &deduplicatedDeps
+	}
+
+	sortBOM(bom)
+	return bom
+}
+
+// categoryBomRef returns the bom-ref of a category's assembly component
+func categoryBomRef(category string) string {
+	return "category:" + category
+}
+
+// linkInstalledApplications links package manager components that install an
+// application (e.g. a Homebrew cask or Chocolatey package) to the matching
+// component found by the application scanner. Matches are by name, ignoring case
+// and punctuation, and by version when both sides have one. The package manager
+// component depends on the application, and the application gets an
+// installed_by property naming the package.
+func linkInstalledApplications(categories []scanCategory, componentMap map[string]cdx.Component) []cdx.Dependency {
+	var packages, applications []scanners.Component
+	for _, category := range categories {
+		switch category.Name {
+		case "package-managers":
+			packages = category.Components
+		case "applications":
+			applications = category.Components
+		}
+	}
+	if len(packages) == 0 || len(applications) == 0 {
+		return nil
+	}
+
+	byName := make(map[string][]scanners.Component)
+	for _, app := range applications {
+		if key := matchKey(app.Name); key != "" {
+			byName[key] = append(byName[key], app)
+		}
+	}
+
+	var dependencies []cdx.Dependency
+	for _, pkg := range packages {
+		if pkg.Type != "application" {
+			continue
+		}
+
+		pkgRef := generateBomRef(pkg)
+		var linked []string
+		for _, app := range byName[matchKey(pkg.Name)] {
+			if !versionsMatch(pkg.Version, app.Version) {
+				continue
+			}
+
+			appRef := generateBomRef(app)
+			if appRef == pkgRef {
+				continue
+			}
+			linked = append(linked, appRef)
+
+			if cdxApp, ok := componentMap[appRef]; ok {
+				props := []cdx.Property{{Name: "installed_by", Value: pkgRef}}
+				if cdxApp.Properties != nil {
+					props = append(*cdxApp.Properties, props...)
+				}
+				cdxApp.Properties = &props
+				componentMap[appRef] = cdxApp
+			}
+		}
+
+		if len(linked) > 0 {
+			dependencies = append(dependencies, cdx.Dependency{
+				Ref:          pkgRef,
+				Dependencies: &linked,
+			})
+		}
+	}
+
+	return dependencies
+}
+
+// matchKey reduces a name to lowercase letters and digits, so "Visual Studio
+// Code" matches the cask token "visual-studio-code"
+func matchKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// versionsMatch reports whether two versions plausibly describe the same
+// install. Package managers often append build metadata ("1.85.0,8b3775030e"),
+// so one version extending the other at a separator is accepted.
+func versionsMatch(packageVersion, appVersion string) bool {
+	if packageVersion == "" || appVersion == "" || packageVersion == "latest" {
+		return true
+	}
+	return versionExtends(packageVersion, appVersion) || versionExtends(appVersion, packageVersion)
+}
+
+// versionExtends reports whether version equals prefix or continues it after a separator
+func versionExtends(version, prefix string) bool {
+	if !strings.HasPrefix(version, prefix) {
+		return false
+	}
+	return len(version) == len(prefix) || strings.ContainsRune(".,-_+ ", rune(version[len(prefix)]))
+}
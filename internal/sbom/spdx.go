@@ -0,0 +1,516 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/eapolsniper/endpointbom/internal/version"
+)
+
+// spdxNoAssertion is used for required SPDX fields the scan cannot determine
+const spdxNoAssertion = "NOASSERTION"
+
+// spdxDocument is an SPDX 2.3 document. Only the fields the CycloneDX model can
+// populate are included.
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+	Annotations       []spdxAnnotation   `json:"annotations,omitempty"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	Supplier              string            `json:"supplier,omitempty"`
+	Originator            string            `json:"originator,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	Checksums             []spdxChecksum    `json:"checksums,omitempty"`
+	Homepage              string            `json:"homepage,omitempty"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	CopyrightText         string            `json:"copyrightText"`
+	Description           string            `json:"description,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+	Annotations           []spdxAnnotation  `json:"annotations,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdxAnnotation carries CycloneDX properties, which SPDX has no field for, as
+// "name: value" lines in the comment
+type spdxAnnotation struct {
+	Annotator      string `json:"annotator"`
+	AnnotationDate string `json:"annotationDate"`
+	AnnotationType string `json:"annotationType"`
+	Comment        string `json:"comment"`
+}
+
+// spdxIDUnsafe matches characters not allowed in an SPDX identifier
+var spdxIDUnsafe = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// convertToSPDX converts a CycloneDX BOM to an SPDX 2.3 document. The device
+// becomes a DEVICE package the document describes, dependencies become
+// DEPENDS_ON relationships and properties become annotations.
+func convertToSPDX(bom *cdx.BOM) *spdxDocument {
+	created := time.Now().UTC()
+	if bom.Metadata != nil {
+		if parsed, err := time.Parse(time.RFC3339, bom.Metadata.Timestamp); err == nil {
+			created = parsed.UTC()
+		}
+	}
+	createdStr := created.Format("2006-01-02T15:04:05Z")
+	tool := "Tool: EndpointBOM-" + version.Short()
+
+	doc := &spdxDocument{
+		SPDXVersion: "SPDX-2.3",
+		DataLicense: "CC0-1.0",
+		SPDXID:      "SPDXRef-DOCUMENT",
+		CreationInfo: spdxCreationInfo{
+			Created:  createdStr,
+			Creators: []string{tool},
+		},
+	}
+
+	annotate := func(props *[]cdx.Property, extra ...string) []spdxAnnotation {
+		lines := extra
+		if props != nil {
+			for _, prop := range *props {
+				lines = append(lines, prop.Name+": "+prop.Value)
+			}
+		}
+		if len(lines) == 0 {
+			return nil
+		}
+		return []spdxAnnotation{{
+			Annotator:      tool,
+			AnnotationDate: createdStr,
+			AnnotationType: "OTHER",
+			Comment:        strings.Join(lines, "\n"),
+		}}
+	}
+
+	// Map every bom-ref to a unique SPDX identifier
+	ids := make(map[string]string)
+	used := make(map[string]bool)
+	idFor := func(bomRef, name string) string {
+		if id, ok := ids[bomRef]; ok {
+			return id
+		}
+		base := "SPDXRef-Package-" + strings.Trim(spdxIDUnsafe.ReplaceAllString(name, "-"), "-")
+		id := base
+		for n := 2; used[id]; n++ {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[id] = true
+		ids[bomRef] = id
+		return id
+	}
+
+	var serial string
+	if bom.SerialNumber != "" {
+		serial = strings.TrimPrefix(bom.SerialNumber, "urn:uuid:")
+	} else {
+		serial = generateUUID()
+	}
+
+	if bom.Metadata != nil && bom.Metadata.Component != nil {
+		device := bom.Metadata.Component
+		ids[device.BOMRef] = "SPDXRef-Device"
+		used["SPDXRef-Device"] = true
+
+		var category string
+		if device.Properties != nil {
+			for _, prop := range *device.Properties {
+				if prop.Name == "scan_category" {
+					category = prop.Value
+				}
+			}
+		}
+		doc.Name = strings.TrimSuffix(device.Name+" "+category, " ")
+		doc.DocumentNamespace = fmt.Sprintf("https://spdx.org/spdxdocs/endpointbom/%s-%s", spdxIDUnsafe.ReplaceAllString(doc.Name, "-"), serial)
+
+		pkg := convertToSPDXPackage(*device, "SPDXRef-Device")
+		pkg.Annotations = annotate(device.Properties)
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      doc.SPDXID,
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: "SPDXRef-Device",
+		})
+
+		// Scanner provenance applies to the whole document
+		if bom.Metadata.Properties != nil {
+			doc.Annotations = annotate(bom.Metadata.Properties)
+		}
+	} else {
+		doc.Name = "endpointbom"
+		doc.DocumentNamespace = "https://spdx.org/spdxdocs/endpointbom/" + serial
+	}
+
+	// Nested components (single-BOM category assemblies) become packages too;
+	// their links are already in the dependency graph
+	var addPackages func(components *[]cdx.Component)
+	addPackages = func(components *[]cdx.Component) {
+		if components == nil {
+			return
+		}
+		for _, comp := range *components {
+			pkg := convertToSPDXPackage(comp, idFor(comp.BOMRef, comp.Name))
+			var extra []string
+			if comp.Group != "" {
+				extra = append(extra, "group: "+comp.Group)
+			}
+			// SPDX has no occurrence evidence; list every install location instead
+			if comp.Evidence != nil && comp.Evidence.Occurrences != nil {
+				for _, occurrence := range *comp.Evidence.Occurrences {
+					extra = append(extra, "occurrence: "+occurrence.Location)
+				}
+			}
+			pkg.Annotations = annotate(comp.Properties, extra...)
+			doc.Packages = append(doc.Packages, pkg)
+			addPackages(comp.Components)
+		}
+	}
+	addPackages(bom.Components)
+
+	// SPDX 2.3 has no services; each becomes an OTHER package annotated with
+	// its endpoints
+	if bom.Services != nil {
+		for _, service := range *bom.Services {
+			pkg := spdxPackage{
+				Name:                  service.Name,
+				SPDXID:                idFor(service.BOMRef, service.Name),
+				VersionInfo:           service.Version,
+				DownloadLocation:      spdxNoAssertion,
+				LicenseConcluded:      spdxNoAssertion,
+				LicenseDeclared:       spdxNoAssertion,
+				CopyrightText:         spdxNoAssertion,
+				Description:           service.Description,
+				PrimaryPackagePurpose: "OTHER",
+			}
+			var extra []string
+			if service.Endpoints != nil {
+				for _, endpoint := range *service.Endpoints {
+					extra = append(extra, "endpoint: "+endpoint)
+				}
+			}
+			if service.Authenticated != nil {
+				extra = append(extra, fmt.Sprintf("authenticated: %t", *service.Authenticated))
+			}
+			pkg.Annotations = annotate(service.Properties, extra...)
+			doc.Packages = append(doc.Packages, pkg)
+		}
+	}
+
+	if bom.Dependencies != nil {
+		for _, dep := range *bom.Dependencies {
+			from, ok := ids[dep.Ref]
+			if !ok || dep.Dependencies == nil {
+				continue
+			}
+			for _, ref := range *dep.Dependencies {
+				if to, ok := ids[ref]; ok {
+					doc.Relationships = append(doc.Relationships, spdxRelationship{
+						SPDXElementID:      from,
+						RelationshipType:   "DEPENDS_ON",
+						RelatedSPDXElement: to,
+					})
+				}
+			}
+		}
+	}
+
+	return doc
+}
+
+// convertToSPDXPackage maps the component fields SPDX has equivalents for
+func convertToSPDXPackage(comp cdx.Component, id string) spdxPackage {
+	pkg := spdxPackage{
+		Name:                  comp.Name,
+		SPDXID:                id,
+		VersionInfo:           comp.Version,
+		DownloadLocation:      spdxNoAssertion,
+		LicenseConcluded:      spdxNoAssertion,
+		LicenseDeclared:       spdxLicenseExpression(comp.Licenses),
+		CopyrightText:         spdxNoAssertion,
+		Description:           comp.Description,
+		PrimaryPackagePurpose: spdxPurpose(comp.Type),
+	}
+
+	if comp.Copyright != "" {
+		pkg.CopyrightText = comp.Copyright
+	}
+	if comp.Supplier != nil && comp.Supplier.Name != "" {
+		pkg.Supplier = "Organization: " + comp.Supplier.Name
+	}
+	pkg.Originator = spdxOriginator(comp)
+	if comp.PackageURL != "" {
+		pkg.ExternalRefs = append(pkg.ExternalRefs, spdxExternalRef{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  comp.PackageURL,
+		})
+	}
+
+	if comp.ExternalReferences != nil {
+		for _, ref := range *comp.ExternalReferences {
+			switch ref.Type {
+			case cdx.ERTypeWebsite:
+				if pkg.Homepage == "" {
+					pkg.Homepage = ref.URL
+				}
+			case cdx.ERTypeDistribution, cdx.ERTypeVCS:
+				if pkg.DownloadLocation == spdxNoAssertion {
+					pkg.DownloadLocation = ref.URL
+				}
+			}
+		}
+	}
+
+	if comp.Hashes != nil {
+		for _, hash := range *comp.Hashes {
+			if algorithm := spdxChecksumAlgorithm(hash.Algorithm); algorithm != "" {
+				pkg.Checksums = append(pkg.Checksums, spdxChecksum{Algorithm: algorithm, ChecksumValue: hash.Value})
+			}
+		}
+	}
+
+	return pkg
+}
+
+// spdxLicenseExpression joins the component's SPDX license IDs and expressions.
+// Licenses known only by name cannot be expressed without extracted licensing
+// info, so a component with any of them yields NOASSERTION rather than an
+// expression that leaves part of its licensing out.
+func spdxLicenseExpression(licenses *cdx.Licenses) string {
+	if licenses == nil {
+		return spdxNoAssertion
+	}
+
+	var parts []string
+	for _, choice := range *licenses {
+		switch {
+		case choice.Expression != "":
+			parts = append(parts, choice.Expression)
+		case choice.License != nil && choice.License.ID != "":
+			parts = append(parts, choice.License.ID)
+		case choice.License != nil:
+			return spdxNoAssertion
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return spdxNoAssertion
+	case 1:
+		return parts[0]
+	default:
+		for i, part := range parts {
+			if strings.Contains(part, " ") {
+				parts[i] = "(" + part + ")"
+			}
+		}
+		return strings.Join(parts, " AND ")
+	}
+}
+
+// organizationSuffixes end the names of companies, foundations and projects
+var organizationSuffixes = regexp.MustCompile(`(?i)(?:^|\s)(?:inc|llc|ltd|limited|gmbh|corp|corporation|co|foundation|team|project|contributors|developers|authors|community)\.?$`)
+
+// spdxOriginator returns the package originator. The author may be a person,
+// an organization or several of either, and SPDX requires the type, so the
+// field is only set when the author is known to be an organization: it is the
+// supplier or its name says so. Otherwise it is left out.
+func spdxOriginator(comp cdx.Component) string {
+	author := strings.TrimSpace(comp.Author)
+	// A comma separates several authors, except in "Example, Inc."
+	name := author
+	if i := strings.LastIndex(author, ","); i >= 0 && organizationSuffixes.MatchString(strings.TrimSpace(author[i+1:])) {
+		name = author[:i]
+	}
+	if author == "" || strings.Contains(name, ",") {
+		return ""
+	}
+	if comp.Supplier != nil && strings.EqualFold(comp.Supplier.Name, author) {
+		return "Organization: " + author
+	}
+	if organizationSuffixes.MatchString(author) {
+		return "Organization: " + author
+	}
+	return ""
+}
+
+// spdxPurpose maps a CycloneDX component type to an SPDX primary package purpose
+func spdxPurpose(componentType cdx.ComponentType) string {
+	switch componentType {
+	case cdx.ComponentTypeApplication:
+		return "APPLICATION"
+	case cdx.ComponentTypeLibrary:
+		return "LIBRARY"
+	case cdx.ComponentTypeFramework:
+		return "FRAMEWORK"
+	case cdx.ComponentTypeDevice:
+		return "DEVICE"
+	case cdx.ComponentTypeOS:
+		return "OPERATING-SYSTEM"
+	case cdx.ComponentTypeContainer:
+		return "CONTAINER"
+	case cdx.ComponentTypeFirmware:
+		return "FIRMWARE"
+	case cdx.ComponentTypeFile:
+		return "FILE"
+	default:
+		return "OTHER"
+	}
+}
+
+// spdxChecksumAlgorithm maps a CycloneDX hash algorithm to its SPDX name
+func spdxChecksumAlgorithm(algorithm cdx.HashAlgorithm) string {
+	switch algorithm {
+	case cdx.HashAlgoMD5:
+		return "MD5"
+	case cdx.HashAlgoSHA1:
+		return "SHA1"
+	case cdx.HashAlgoSHA256:
+		return "SHA256"
+	case cdx.HashAlgoSHA384:
+		return "SHA384"
+	case cdx.HashAlgoSHA512:
+		return "SHA512"
+	case cdx.HashAlgoSHA3_256:
+		return "SHA3-256"
+	case cdx.HashAlgoSHA3_512:
+		return "SHA3-512"
+	default:
+		return ""
+	}
+}
+
+// spdxJSONWriter writes SPDX 2.3 JSON
+type spdxJSONWriter struct{}
+
+func (spdxJSONWriter) Extension() string {
+	return "spdx.json"
+}
+
+func (spdxJSONWriter) Write(w io.Writer, bom *cdx.BOM) error {
+	data, err := json.MarshalIndent(convertToSPDX(bom), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SPDX document: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// spdxTagValueWriter writes the SPDX 2.3 tag-value format
+type spdxTagValueWriter struct{}
+
+func (spdxTagValueWriter) Extension() string {
+	return "spdx"
+}
+
+func (spdxTagValueWriter) Write(w io.Writer, bom *cdx.BOM) error {
+	doc := convertToSPDX(bom)
+	var b strings.Builder
+
+	tag := func(name, value string) {
+		if value == "" {
+			return
+		}
+		if strings.ContainsAny(value, "\n\r") {
+			value = "<text>" + value + "</text>"
+		}
+		fmt.Fprintf(&b, "%s: %s\n", name, value)
+	}
+	annotations := func(id string, list []spdxAnnotation) {
+		for _, annotation := range list {
+			b.WriteString("\n")
+			tag("Annotator", annotation.Annotator)
+			tag("AnnotationDate", annotation.AnnotationDate)
+			tag("AnnotationType", annotation.AnnotationType)
+			tag("SPDXREF", id)
+			b.WriteString("AnnotationComment: <text>" + annotation.Comment + "</text>\n")
+		}
+	}
+
+	tag("SPDXVersion", doc.SPDXVersion)
+	tag("DataLicense", doc.DataLicense)
+	tag("SPDXID", doc.SPDXID)
+	tag("DocumentName", doc.Name)
+	tag("DocumentNamespace", doc.DocumentNamespace)
+	for _, creator := range doc.CreationInfo.Creators {
+		tag("Creator", creator)
+	}
+	tag("Created", doc.CreationInfo.Created)
+	annotations(doc.SPDXID, doc.Annotations)
+
+	for _, pkg := range doc.Packages {
+		b.WriteString("\n")
+		tag("PackageName", pkg.Name)
+		tag("SPDXID", pkg.SPDXID)
+		tag("PackageVersion", pkg.VersionInfo)
+		tag("PackageSupplier", pkg.Supplier)
+		tag("PackageOriginator", pkg.Originator)
+		tag("PackageDownloadLocation", pkg.DownloadLocation)
+		tag("FilesAnalyzed", fmt.Sprintf("%t", pkg.FilesAnalyzed))
+		for _, checksum := range pkg.Checksums {
+			tag("PackageChecksum", checksum.Algorithm+": "+checksum.ChecksumValue)
+		}
+		tag("PackageHomePage", pkg.Homepage)
+		tag("PackageLicenseConcluded", pkg.LicenseConcluded)
+		tag("PackageLicenseDeclared", pkg.LicenseDeclared)
+		tag("PackageCopyrightText", pkg.CopyrightText)
+		if pkg.Description != "" {
+			b.WriteString("PackageDescription: <text>" + pkg.Description + "</text>\n")
+		}
+		tag("PrimaryPackagePurpose", pkg.PrimaryPackagePurpose)
+		for _, ref := range pkg.ExternalRefs {
+			tag("ExternalRef", ref.ReferenceCategory+" "+ref.ReferenceType+" "+ref.ReferenceLocator)
+		}
+		annotations(pkg.SPDXID, pkg.Annotations)
+	}
+
+	if len(doc.Relationships) > 0 {
+		b.WriteString("\n")
+		for _, rel := range doc.Relationships {
+			tag("Relationship", rel.SPDXElementID+" "+rel.RelationshipType+" "+rel.RelatedSPDXElement)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
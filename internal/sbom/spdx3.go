@@ -0,0 +1,223 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/eapolsniper/endpointbom/internal/version"
+)
+
+// spdx3Context is the JSON-LD context for SPDX 3.0.1 documents
+const spdx3Context = "https://spdx.org/rdf/3.0.1/spdx-context.jsonld"
+
+// spdx3Purposes maps SPDX 2.3 package purposes to SPDX 3 software purposes
+var spdx3Purposes = map[string]string{
+	"APPLICATION":      "application",
+	"LIBRARY":          "library",
+	"FRAMEWORK":        "framework",
+	"DEVICE":           "device",
+	"OPERATING-SYSTEM": "operatingSystem",
+	"CONTAINER":        "container",
+	"FIRMWARE":         "firmware",
+	"FILE":             "file",
+	"OTHER":            "other",
+}
+
+// spdx3Algorithms maps SPDX 2.3 checksum algorithms to SPDX 3 hash algorithms
+var spdx3Algorithms = map[string]string{
+	"MD5":      "md5",
+	"SHA1":     "sha1",
+	"SHA256":   "sha256",
+	"SHA384":   "sha384",
+	"SHA512":   "sha512",
+	"SHA3-256": "sha3_256",
+	"SHA3-512": "sha3_512",
+}
+
+// spdx3JSONWriter writes SPDX 3.0 JSON-LD
+type spdx3JSONWriter struct{}
+
+func (spdx3JSONWriter) Extension() string {
+	return "spdx3.json"
+}
+
+func (spdx3JSONWriter) Write(w io.Writer, bom *cdx.BOM) error {
+	data, err := json.MarshalIndent(convertToSPDX3(convertToSPDX(bom)), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SPDX 3 document: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// convertToSPDX3 converts the SPDX 2.3 model to an SPDX 3.0 JSON-LD graph.
+// Elements are identified by IRIs under the document namespace; declared
+// licenses become LicenseExpression elements linked by hasDeclaredLicense.
+func convertToSPDX3(doc *spdxDocument) map[string]interface{} {
+	const creationInfo = "_:creationinfo"
+	iri := func(id string) string {
+		return doc.DocumentNamespace + "#" + id
+	}
+	agent := iri("SPDXRef-Tool")
+
+	graph := []interface{}{
+		map[string]interface{}{
+			"type":        "CreationInfo",
+			"@id":         creationInfo,
+			"specVersion": "3.0.1",
+			"created":     doc.CreationInfo.Created,
+			"createdBy":   []string{agent},
+		},
+		map[string]interface{}{
+			"type":         "SoftwareAgent",
+			"spdxId":       agent,
+			"creationInfo": creationInfo,
+			"name":         "EndpointBOM " + version.Short(),
+		},
+	}
+
+	var elements, roots []string
+	add := func(element map[string]interface{}) {
+		element["creationInfo"] = creationInfo
+		graph = append(graph, element)
+		elements = append(elements, element["spdxId"].(string))
+	}
+
+	annotationCount := 0
+	annotate := func(subject string, annotations []spdxAnnotation) {
+		for _, annotation := range annotations {
+			annotationCount++
+			add(map[string]interface{}{
+				"type":           "Annotation",
+				"spdxId":         iri(fmt.Sprintf("SPDXRef-Annotation-%d", annotationCount)),
+				"annotationType": "other",
+				"subject":        subject,
+				"statement":      annotation.Comment,
+			})
+		}
+	}
+	annotate(iri(doc.SPDXID), doc.Annotations)
+
+	suppliers := make(map[string]string)
+	relationshipCount := 0
+	relate := func(from, relationshipType string, to []string) {
+		relationshipCount++
+		add(map[string]interface{}{
+			"type":             "Relationship",
+			"spdxId":           iri(fmt.Sprintf("SPDXRef-Relationship-%d", relationshipCount)),
+			"from":             from,
+			"relationshipType": relationshipType,
+			"to":               to,
+		})
+	}
+
+	for _, pkg := range doc.Packages {
+		element := map[string]interface{}{
+			"type":   "software_Package",
+			"spdxId": iri(pkg.SPDXID),
+			"name":   pkg.Name,
+		}
+		if pkg.VersionInfo != "" {
+			element["software_packageVersion"] = pkg.VersionInfo
+		}
+		if pkg.Description != "" {
+			element["description"] = pkg.Description
+		}
+		if purpose, ok := spdx3Purposes[pkg.PrimaryPackagePurpose]; ok {
+			element["software_primaryPurpose"] = purpose
+		}
+		if pkg.DownloadLocation != spdxNoAssertion {
+			element["software_downloadLocation"] = pkg.DownloadLocation
+		}
+		if pkg.Homepage != "" {
+			element["software_homePage"] = pkg.Homepage
+		}
+		if pkg.CopyrightText != spdxNoAssertion {
+			element["software_copyrightText"] = pkg.CopyrightText
+		}
+		for _, ref := range pkg.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				element["software_packageUrl"] = ref.ReferenceLocator
+			}
+		}
+
+		var hashes []interface{}
+		for _, checksum := range pkg.Checksums {
+			if algorithm, ok := spdx3Algorithms[checksum.Algorithm]; ok {
+				hashes = append(hashes, map[string]interface{}{
+					"type":      "Hash",
+					"algorithm": algorithm,
+					"hashValue": checksum.ChecksumValue,
+				})
+			}
+		}
+		if len(hashes) > 0 {
+			element["verifiedUsing"] = hashes
+		}
+
+		if supplier := strings.TrimPrefix(pkg.Supplier, "Organization: "); supplier != "" {
+			id, ok := suppliers[supplier]
+			if !ok {
+				id = iri(fmt.Sprintf("SPDXRef-Organization-%d", len(suppliers)+1))
+				suppliers[supplier] = id
+				add(map[string]interface{}{
+					"type":   "Organization",
+					"spdxId": id,
+					"name":   supplier,
+				})
+			}
+			element["suppliedBy"] = id
+		}
+
+		add(element)
+		annotate(iri(pkg.SPDXID), pkg.Annotations)
+
+		if pkg.LicenseDeclared != spdxNoAssertion {
+			licenseID := iri(pkg.SPDXID + "-License")
+			add(map[string]interface{}{
+				"type":                              "simplelicensing_LicenseExpression",
+				"spdxId":                            licenseID,
+				"simplelicensing_licenseExpression": pkg.LicenseDeclared,
+			})
+			relate(iri(pkg.SPDXID), "hasDeclaredLicense", []string{licenseID})
+		}
+	}
+
+	// Group DEPENDS_ON relationships by source, preserving order
+	var sources []string
+	targets := make(map[string][]string)
+	for _, rel := range doc.Relationships {
+		switch rel.RelationshipType {
+		case "DESCRIBES":
+			roots = append(roots, iri(rel.RelatedSPDXElement))
+		case "DEPENDS_ON":
+			from := iri(rel.SPDXElementID)
+			if _, seen := targets[from]; !seen {
+				sources = append(sources, from)
+			}
+			targets[from] = append(targets[from], iri(rel.RelatedSPDXElement))
+		}
+	}
+	for _, from := range sources {
+		relate(from, "dependsOn", targets[from])
+	}
+
+	graph = append(graph, map[string]interface{}{
+		"type":               "SpdxDocument",
+		"spdxId":             iri(doc.SPDXID),
+		"creationInfo":       creationInfo,
+		"name":               doc.Name,
+		"dataLicense":        "https://spdx.org/licenses/CC0-1.0",
+		"profileConformance": []string{"core", "software", "simpleLicensing"},
+		"rootElement":        roots,
+		"element":            elements,
+	})
+
+	return map[string]interface{}{
+		"@context": spdx3Context,
+		"@graph":   graph,
+	}
+}
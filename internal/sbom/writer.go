@@ -0,0 +1,91 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+)
+
+// DefaultFormat is the output format used when none is configured
+const DefaultFormat = "cdx-json"
+
+// Writer serializes a BOM in one output format. The CycloneDX BOM built for each
+// category is the common model; other formats are converted from it.
+type Writer interface {
+	// Extension is the file name suffix without a leading dot, e.g. "cdx.json"
+	Extension() string
+
+	// Write serializes the BOM to w
+	Write(w io.Writer, bom *cdx.BOM) error
+}
+
+// writers maps each supported --format value to its writer
+var writers = map[string]Writer{
+	"cdx-json":      cdxJSONWriter{},
+	"cdx-xml":       cdxXMLWriter{},
+	"spdx-json":     spdxJSONWriter{},
+	"spdx-tagvalue": spdxTagValueWriter{},
+	"spdx3-json":    spdx3JSONWriter{},
+}
+
+// NewWriter returns the writer for a format name. An empty name selects DefaultFormat.
+func NewWriter(format string) (Writer, error) {
+	if format == "" {
+		format = DefaultFormat
+	}
+	writer, ok := writers[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q (supported: %s)", format, strings.Join(Formats(), ", "))
+	}
+	return writer, nil
+}
+
+// Formats returns the supported output format names
+func Formats() []string {
+	formats := make([]string, 0, len(writers))
+	for format := range writers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Extensions returns the file name suffixes of every output format
+func Extensions() []string {
+	var extensions []string
+	for _, format := range Formats() {
+		extensions = append(extensions, writers[format].Extension())
+	}
+	return extensions
+}
+
+// cdxJSONWriter writes CycloneDX JSON, the default format
+type cdxJSONWriter struct{}
+
+func (cdxJSONWriter) Extension() string {
+	return "cdx.json"
+}
+
+func (cdxJSONWriter) Write(w io.Writer, bom *cdx.BOM) error {
+	data, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal BOM: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// cdxXMLWriter writes CycloneDX XML
+type cdxXMLWriter struct{}
+
+func (cdxXMLWriter) Extension() string {
+	return "cdx.xml"
+}
+
+func (cdxXMLWriter) Write(w io.Writer, bom *cdx.BOM) error {
+	return cdx.NewBOMEncoder(w, cdx.BOMFileFormatXML).SetPretty(true).Encode(bom)
+}
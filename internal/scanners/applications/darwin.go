@@ -1,6 +1,7 @@
 package applications
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,7 +12,7 @@ import (
 )
 
 // scanDarwinApplications scans for installed applications on macOS
-func scanDarwinApplications(cfg *config.Config) ([]scanners.Component, error) {
+func scanDarwinApplications(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	// Standard macOS application locations
@@ -32,11 +33,14 @@ func scanDarwinApplications(cfg *config.Config) ([]scanners.Component, error) {
 	}
 
 	for _, searchPath := range searchPaths {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if cfg.IsPathExcluded(searchPath) {
 			continue
 		}
 
-		apps, err := scanMacOSDirectory(searchPath, cfg)
+		apps, err := scanMacOSDirectory(ctx, searchPath, cfg)
 		if err != nil {
 			if cfg.Debug {
 				fmt.Printf("Error scanning %s: %v\n", searchPath, err)
@@ -49,7 +53,7 @@ func scanDarwinApplications(cfg *config.Config) ([]scanners.Component, error) {
 	return components, nil
 }
 
-func scanMacOSDirectory(dir string, cfg *config.Config) ([]scanners.Component, error) {
+func scanMacOSDirectory(ctx context.Context, dir string, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	entries, err := os.ReadDir(dir)
@@ -58,6 +62,9 @@ func scanMacOSDirectory(dir string, cfg *config.Config) ([]scanners.Component, e
 	}
 
 	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if !entry.IsDir() {
 			continue
 		}
@@ -90,6 +97,16 @@ func scanMacOSDirectory(dir string, cfg *config.Config) ([]scanners.Component, e
 			comp.Name = appInfo.DisplayName
 		}
 
+		if appInfo.BundleIdentifier != "" {
+			comp.Identity = &scanners.Identity{Technique: "manifest-analysis", Confidence: 1, Value: infoPlistPath}
+		} else {
+			comp.Identity = &scanners.Identity{Technique: "filename", Confidence: 0.5, Value: appPath}
+		}
+
+		if appInfo.Executable != "" {
+			hashExecutable(&comp, filepath.Join(appPath, "Contents", "MacOS", appInfo.Executable), cfg)
+		}
+
 		components = append(components, comp)
 	}
 
@@ -100,6 +117,7 @@ type macOSAppInfo struct {
 	BundleIdentifier string
 	Version          string
 	DisplayName      string
+	Executable       string
 }
 
 func parseMacOSAppInfo(plistPath string) macOSAppInfo {
@@ -119,6 +137,9 @@ func parseMacOSAppInfo(plistPath string) macOSAppInfo {
 		info.Version = extractPlistValue(content, "CFBundleVersion")
 	}
 	info.DisplayName = extractPlistValue(content, "CFBundleDisplayName")
+	if executable := extractPlistValue(content, "CFBundleExecutable"); executable != "" {
+		info.Executable = filepath.Base(executable)
+	}
 
 	return info
 }
@@ -1,8 +1,10 @@
 package applications
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -11,7 +13,7 @@ import (
 )
 
 // scanLinuxApplications scans for installed applications on Linux
-func scanLinuxApplications(cfg *config.Config) ([]scanners.Component, error) {
+func scanLinuxApplications(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	// Standard Linux application locations
@@ -33,11 +35,14 @@ func scanLinuxApplications(cfg *config.Config) ([]scanners.Component, error) {
 	}
 
 	for _, searchPath := range searchPaths {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if cfg.IsPathExcluded(searchPath) {
 			continue
 		}
 
-		apps, err := scanLinuxDirectory(searchPath, cfg)
+		apps, err := scanLinuxDirectory(ctx, searchPath, cfg)
 		if err != nil {
 			if cfg.Debug {
 				fmt.Printf("Error scanning %s: %v\n", searchPath, err)
@@ -50,7 +55,7 @@ func scanLinuxApplications(cfg *config.Config) ([]scanners.Component, error) {
 	return components, nil
 }
 
-func scanLinuxDirectory(dir string, cfg *config.Config) ([]scanners.Component, error) {
+func scanLinuxDirectory(ctx context.Context, dir string, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	entries, err := os.ReadDir(dir)
@@ -59,6 +64,9 @@ func scanLinuxDirectory(dir string, cfg *config.Config) ([]scanners.Component, e
 	}
 
 	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if entry.IsDir() {
 			continue
 		}
@@ -88,8 +96,13 @@ func scanLinuxDirectory(dir string, cfg *config.Config) ([]scanners.Component, e
 			comp.Description = appInfo.Comment
 		}
 
+		comp.Identity = &scanners.Identity{Technique: "manifest-analysis", Confidence: 0.7, Value: desktopPath}
+
 		if appInfo.Exec != "" {
 			comp.Properties["exec"] = appInfo.Exec
+			if executable := resolveDesktopExec(appInfo.Exec); executable != "" {
+				hashExecutable(&comp, executable, cfg)
+			}
 		}
 
 		components = append(components, comp)
@@ -98,6 +111,38 @@ func scanLinuxDirectory(dir string, cfg *config.Config) ([]scanners.Component, e
 	return components, nil
 }
 
+// desktopLaunchers are wrappers whose binary says nothing about the application itself
+var desktopLaunchers = map[string]bool{
+	"flatpak": true,
+	"snap":    true,
+	"sh":      true,
+	"bash":    true,
+}
+
+// resolveDesktopExec returns the absolute path of the program a desktop entry's
+// Exec line runs, skipping "env" and its variable assignments. Launchers such
+// as flatpak yield "" because hashing them would not identify the application.
+func resolveDesktopExec(execLine string) string {
+	for _, field := range strings.Fields(execLine) {
+		field = strings.Trim(field, "\"'")
+		if field == "env" || strings.Contains(field, "=") {
+			continue
+		}
+		if desktopLaunchers[filepath.Base(field)] {
+			return ""
+		}
+		path, err := exec.LookPath(field)
+		if err != nil {
+			return ""
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			return abs
+		}
+		return path
+	}
+	return ""
+}
+
 type linuxAppInfo struct {
 	Name    string
 	Version string
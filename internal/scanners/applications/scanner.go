@@ -1,12 +1,15 @@
 package applications
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/hashing"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
 )
 
@@ -18,22 +21,43 @@ func (s *ApplicationScanner) Name() string {
 }
 
 func (s *ApplicationScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext scans the application folders of the current OS, stopping early
+// if ctx is cancelled
+func (s *ApplicationScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("applications") {
 		return nil, nil
 	}
 
 	switch runtime.GOOS {
 	case "darwin":
-		return scanDarwinApplications(cfg)
+		return scanDarwinApplications(ctx, cfg)
 	case "windows":
-		return scanWindowsApplications(cfg)
+		return scanWindowsApplications(ctx, cfg)
 	case "linux":
-		return scanLinuxApplications(cfg)
+		return scanLinuxApplications(ctx, cfg)
 	default:
 		return nil, fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
 	}
 }
 
+// hashExecutable records the SHA-256 of the executable that launches an application,
+// which is what IOC lists publish
+func hashExecutable(comp *scanners.Component, path string, cfg *config.Config) {
+	digest, err := hashing.For(cfg).File(path)
+	if err != nil {
+		if cfg.Debug && !errors.Is(err, hashing.ErrDisabled) {
+			fmt.Printf("Could not hash %s: %v\n", path, err)
+		}
+		return
+	}
+
+	comp.Hashes = map[string]string{hashing.SHA256: digest}
+	comp.Properties["hashed_file"] = path
+}
+
 // getUserProfiles returns user home directories
 func getUserProfiles() ([]string, error) {
 	switch runtime.GOOS {
@@ -1,6 +1,7 @@
 package applications
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,7 +13,7 @@ import (
 )
 
 // scanWindowsApplications scans for installed applications on Windows
-func scanWindowsApplications(cfg *config.Config) ([]scanners.Component, error) {
+func scanWindowsApplications(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	// Scan file system locations
@@ -34,11 +35,14 @@ func scanWindowsApplications(cfg *config.Config) ([]scanners.Component, error) {
 	}
 
 	for _, searchPath := range searchPaths {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if cfg.IsPathExcluded(searchPath) {
 			continue
 		}
 
-		apps, err := scanWindowsDirectory(searchPath, cfg)
+		apps, err := scanWindowsDirectory(ctx, searchPath, cfg)
 		if err != nil {
 			if cfg.Debug {
 				fmt.Printf("Error scanning %s: %v\n", searchPath, err)
@@ -49,7 +53,7 @@ func scanWindowsApplications(cfg *config.Config) ([]scanners.Component, error) {
 	}
 
 	// Scan Windows Registry for installed programs
-	regApps, err := scanWindowsRegistry(cfg)
+	regApps, err := scanWindowsRegistry(ctx, cfg)
 	if err != nil {
 		if cfg.Debug {
 			fmt.Printf("Error scanning registry: %v\n", err)
@@ -61,7 +65,7 @@ func scanWindowsApplications(cfg *config.Config) ([]scanners.Component, error) {
 	return components, nil
 }
 
-func scanWindowsDirectory(dir string, cfg *config.Config) ([]scanners.Component, error) {
+func scanWindowsDirectory(ctx context.Context, dir string, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	entries, err := os.ReadDir(dir)
@@ -70,6 +74,9 @@ func scanWindowsDirectory(dir string, cfg *config.Config) ([]scanners.Component,
 	}
 
 	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if !entry.IsDir() {
 			continue
 		}
@@ -89,6 +96,11 @@ func scanWindowsDirectory(dir string, cfg *config.Config) ([]scanners.Component,
 			Location:   appPath,
 			Properties: make(map[string]string),
 		}
+		comp.Identity = &scanners.Identity{Technique: "filename", Confidence: 0.3, Value: appPath}
+
+		if executable := findWindowsAppExecutable(appPath, entry.Name()); executable != "" {
+			hashExecutable(&comp, executable, cfg)
+		}
 
 		components = append(components, comp)
 	}
@@ -108,7 +120,27 @@ func findWindowsAppVersion(appPath string) string {
 	return "unknown"
 }
 
-func scanWindowsRegistry(cfg *config.Config) ([]scanners.Component, error) {
+// findWindowsAppExecutable picks the executable to hash: one named after the
+// application directory, or the only .exe in it. Directories with several
+// unrelated executables yield "" rather than an arbitrary pick.
+func findWindowsAppExecutable(appPath, name string) string {
+	matches, err := filepath.Glob(filepath.Join(appPath, "*.exe"))
+	if err != nil || len(matches) == 0 {
+		return ""
+	}
+
+	for _, match := range matches {
+		if strings.EqualFold(strings.TrimSuffix(filepath.Base(match), filepath.Ext(match)), name) {
+			return match
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0]
+	}
+	return ""
+}
+
+func scanWindowsRegistry(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	// Use PowerShell to query the registry
@@ -118,7 +150,10 @@ func scanWindowsRegistry(cfg *config.Config) ([]scanners.Component, error) {
 	}
 
 	for _, regPath := range registryPaths {
-		cmd := exec.Command("powershell", "-Command",
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		cmd := exec.CommandContext(ctx, "powershell", "-Command",
 			fmt.Sprintf("Get-ItemProperty '%s' | Select-Object DisplayName, DisplayVersion, Publisher | ConvertTo-Json", regPath))
 		
 		output, err := cmd.Output()
@@ -1,6 +1,7 @@
 package browsers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -19,6 +20,11 @@ func (s *ChromeScanner) Name() string {
 }
 
 func (s *ChromeScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext scans every Chrome profile, stopping early if ctx is cancelled
+func (s *ChromeScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("chrome-extensions") {
 		return nil, nil
 	}
@@ -77,12 +83,15 @@ func (s *ChromeScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 	}
 
 	for _, extDir := range extensionDirs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if cfg.IsPathExcluded(extDir) {
 			continue
 		}
 
 		profileName := profileExtDirs[extDir]
-		exts, err := scanChromeExtensions(extDir, profileName, cfg)
+		exts, err := scanChromeExtensions(ctx, extDir, profileName, cfg)
 		if err != nil {
 			if cfg.Debug {
 				fmt.Printf("Error scanning Chrome extensions in %s: %v\n", extDir, err)
@@ -95,7 +104,7 @@ func (s *ChromeScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 	return components, nil
 }
 
-func scanChromeExtensions(extensionDir string, profileName string, cfg *config.Config) ([]scanners.Component, error) {
+func scanChromeExtensions(ctx context.Context, extensionDir string, profileName string, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	entries, err := os.ReadDir(extensionDir)
@@ -104,6 +113,9 @@ func scanChromeExtensions(extensionDir string, profileName string, cfg *config.C
 	}
 
 	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if !entry.IsDir() {
 			continue
 		}
@@ -190,6 +202,12 @@ func scanChromeExtensions(extensionDir string, profileName string, cfg *config.C
 				comp.Properties["host_permissions"] = hostStr
 			}
 
+			applyManifestLinks(&comp, extensionID, latestManifest.Author, latestManifest.HomepageURL, latestManifest.UpdateURL)
+
+			versionPath := filepath.Join(extensionPath, latestVersion)
+			comp.Identity = &scanners.Identity{Technique: "manifest-analysis", Confidence: 1, Value: filepath.Join(versionPath, "manifest.json")}
+			hashExtension(&comp, versionPath, cfg)
+
 			components = append(components, comp)
 		}
 	}
@@ -198,12 +216,15 @@ func scanChromeExtensions(extensionDir string, profileName string, cfg *config.C
 }
 
 type chromeManifest struct {
-	Name            string   `json:"name"`
-	Version         string   `json:"version"`
-	Description     string   `json:"description"`
-	ManifestVersion int      `json:"manifest_version"`
-	Permissions     []string `json:"permissions"`
-	HostPermissions []string `json:"host_permissions"`
+	Name            string          `json:"name"`
+	Version         string          `json:"version"`
+	Description     string          `json:"description"`
+	ManifestVersion int             `json:"manifest_version"`
+	Permissions     []string        `json:"permissions"`
+	HostPermissions []string        `json:"host_permissions"`
+	Author          json.RawMessage `json:"author"`
+	HomepageURL     string          `json:"homepage_url"`
+	UpdateURL       string          `json:"update_url"`
 }
 
 // discoverChromeProfiles finds all Chrome profile directories
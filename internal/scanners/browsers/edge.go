@@ -1,6 +1,7 @@
 package browsers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -19,6 +20,11 @@ func (s *EdgeScanner) Name() string {
 }
 
 func (s *EdgeScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext scans the Edge profiles, stopping early if ctx is cancelled
+func (s *EdgeScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("edge-extensions") {
 		return nil, nil
 	}
@@ -72,11 +78,14 @@ func (s *EdgeScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 	}
 
 	for _, extDir := range extensionDirs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if cfg.IsPathExcluded(extDir) {
 			continue
 		}
 
-		exts, err := scanEdgeExtensions(extDir, cfg)
+		exts, err := scanEdgeExtensions(ctx, extDir, cfg)
 		if err != nil {
 			if cfg.Debug {
 				fmt.Printf("Error scanning Edge extensions in %s: %v\n", extDir, err)
@@ -89,7 +98,7 @@ func (s *EdgeScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 	return components, nil
 }
 
-func scanEdgeExtensions(extensionDir string, cfg *config.Config) ([]scanners.Component, error) {
+func scanEdgeExtensions(ctx context.Context, extensionDir string, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	entries, err := os.ReadDir(extensionDir)
@@ -98,6 +107,9 @@ func scanEdgeExtensions(extensionDir string, cfg *config.Config) ([]scanners.Com
 	}
 
 	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if !entry.IsDir() {
 			continue
 		}
@@ -173,6 +185,12 @@ func scanEdgeExtensions(extensionDir string, cfg *config.Config) ([]scanners.Com
 				comp.Properties["host_permissions"] = hostStr
 			}
 
+			applyManifestLinks(&comp, extensionID, latestManifest.Author, latestManifest.HomepageURL, latestManifest.UpdateURL)
+
+			versionPath := filepath.Join(extensionPath, latestVersion)
+			comp.Identity = &scanners.Identity{Technique: "manifest-analysis", Confidence: 1, Value: filepath.Join(versionPath, "manifest.json")}
+			hashExtension(&comp, versionPath, cfg)
+
 			components = append(components, comp)
 		}
 	}
@@ -181,11 +199,14 @@ func scanEdgeExtensions(extensionDir string, cfg *config.Config) ([]scanners.Com
 }
 
 type edgeManifest struct {
-	Name            string   `json:"name"`
-	Version         string   `json:"version"`
-	Description     string   `json:"description"`
-	ManifestVersion int      `json:"manifest_version"`
-	Permissions     []string `json:"permissions"`
-	HostPermissions []string `json:"host_permissions"`
+	Name            string          `json:"name"`
+	Version         string          `json:"version"`
+	Description     string          `json:"description"`
+	ManifestVersion int             `json:"manifest_version"`
+	Permissions     []string        `json:"permissions"`
+	HostPermissions []string        `json:"host_permissions"`
+	Author          json.RawMessage `json:"author"`
+	HomepageURL     string          `json:"homepage_url"`
+	UpdateURL       string          `json:"update_url"`
 }
 
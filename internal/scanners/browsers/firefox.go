@@ -1,6 +1,7 @@
 package browsers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -19,6 +20,11 @@ func (s *FirefoxScanner) Name() string {
 }
 
 func (s *FirefoxScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext scans the Firefox profiles, stopping early if ctx is cancelled
+func (s *FirefoxScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("firefox-extensions") {
 		return nil, nil
 	}
@@ -69,11 +75,14 @@ func (s *FirefoxScanner) Scan(cfg *config.Config) ([]scanners.Component, error)
 	}
 
 	for _, profileDir := range profileDirs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if cfg.IsPathExcluded(profileDir) {
 			continue
 		}
 
-		exts, err := scanFirefoxExtensions(profileDir, cfg)
+		exts, err := scanFirefoxExtensions(ctx, profileDir, cfg)
 		if err != nil {
 			if cfg.Debug {
 				fmt.Printf("Error scanning Firefox extensions in %s: %v\n", profileDir, err)
@@ -86,7 +95,7 @@ func (s *FirefoxScanner) Scan(cfg *config.Config) ([]scanners.Component, error)
 	return components, nil
 }
 
-func scanFirefoxExtensions(profilesDir string, cfg *config.Config) ([]scanners.Component, error) {
+func scanFirefoxExtensions(ctx context.Context, profilesDir string, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	// Firefox can have multiple profiles
@@ -96,6 +105,9 @@ func scanFirefoxExtensions(profilesDir string, cfg *config.Config) ([]scanners.C
 	}
 
 	for _, profile := range profiles {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if !profile.IsDir() {
 			continue
 		}
@@ -145,6 +157,8 @@ func scanFirefoxExtensions(profilesDir string, cfg *config.Config) ([]scanners.C
 				},
 			}
 
+			applyManifestLinks(&comp, "", manifest.Author, manifest.HomepageURL, manifest.Applications.Gecko.UpdateURL)
+
 			// Add permissions
 			if len(manifest.Permissions) > 0 {
 				permStr := ""
@@ -165,13 +179,16 @@ func scanFirefoxExtensions(profilesDir string, cfg *config.Config) ([]scanners.C
 }
 
 type firefoxManifest struct {
-	Name         string   `json:"name"`
-	Version      string   `json:"version"`
-	Description  string   `json:"description"`
-	Permissions  []string `json:"permissions"`
+	Name         string          `json:"name"`
+	Version      string          `json:"version"`
+	Description  string          `json:"description"`
+	Permissions  []string        `json:"permissions"`
+	Author       json.RawMessage `json:"author"`
+	HomepageURL  string          `json:"homepage_url"`
 	Applications struct {
 		Gecko struct {
-			ID string `json:"id"`
+			ID        string `json:"id"`
+			UpdateURL string `json:"update_url"` // Only set for self-hosted extensions
 		} `json:"gecko"`
 	} `json:"applications"`
 }
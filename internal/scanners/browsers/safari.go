@@ -1,6 +1,7 @@
 package browsers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -20,13 +21,19 @@ func (s *SafariScanner) Name() string {
 }
 
 func (s *SafariScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext scans the Safari extension locations, stopping early if ctx is
+// cancelled
+func (s *SafariScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("safari-extensions") {
 		return nil, nil
 	}
 
 	// Safari extensions only exist on macOS
 	if runtime.GOOS != "darwin" {
-		return nil, nil
+		return nil, scanners.Skipped("safari is only available on macOS")
 	}
 
 	var components []scanners.Component
@@ -55,11 +62,14 @@ func (s *SafariScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 	}
 
 	for _, extDir := range extensionDirs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if cfg.IsPathExcluded(extDir) {
 			continue
 		}
 
-		exts, err := scanSafariExtensions(extDir, cfg)
+		exts, err := scanSafariExtensions(ctx, extDir, cfg)
 		if err != nil {
 			if cfg.Debug {
 				fmt.Printf("Error scanning Safari extensions in %s: %v\n", extDir, err)
@@ -72,7 +82,7 @@ func (s *SafariScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 	return components, nil
 }
 
-func scanSafariExtensions(extensionDir string, cfg *config.Config) ([]scanners.Component, error) {
+func scanSafariExtensions(ctx context.Context, extensionDir string, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	entries, err := os.ReadDir(extensionDir)
@@ -81,6 +91,9 @@ func scanSafariExtensions(extensionDir string, cfg *config.Config) ([]scanners.C
 	}
 
 	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if !entry.IsDir() {
 			continue
 		}
@@ -1,10 +1,16 @@
 package browsers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/hashing"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
 )
 
 // getUserProfiles returns user home directories
@@ -69,3 +75,59 @@ func getLinuxUserProfiles() ([]string, error) {
 	return profiles, nil
 }
 
+// hashExtension records the SHA-256 of an extension file, or the directory digest
+// of an unpacked extension (see hashing.Budget.Directory)
+func hashExtension(comp *scanners.Component, path string, cfg *config.Config) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+
+	budget := hashing.For(cfg)
+	property := "hashed_file"
+	var digest string
+	if info.IsDir() {
+		property = "hashed_directory"
+		digest, err = budget.Directory(path)
+	} else {
+		digest, err = budget.File(path)
+	}
+	if err != nil {
+		if cfg.Debug && !errors.Is(err, hashing.ErrDisabled) {
+			fmt.Printf("Could not hash %s: %v\n", path, err)
+		}
+		return
+	}
+
+	comp.Hashes = map[string]string{hashing.SHA256: digest}
+	comp.Properties[property] = path
+}
+
+// Update URLs of the extension stores; an extension updating from anywhere else
+// is self-hosted
+const (
+	chromeWebStoreUpdateURL = "https://clients2.google.com/service/update2/crx"
+	edgeAddonsUpdateURL     = "https://edge.microsoft.com/extensionwebstorebase/v1/crx"
+)
+
+// applyManifestLinks records a Chromium extension's author, homepage and where it
+// is distributed from: its store listing, or the update URL of a self-hosted
+// extension. Manifest V3 replaced the author string with an email object, which
+// is not collected.
+func applyManifestLinks(comp *scanners.Component, extensionID string, author json.RawMessage, homepageURL, updateURL string) {
+	var name string
+	if json.Unmarshal(author, &name) == nil {
+		comp.Author = name
+	}
+	comp.AddExternalRef(scanners.RefWebsite, homepageURL)
+
+	switch updateURL {
+	case "":
+	case chromeWebStoreUpdateURL:
+		comp.AddExternalRef(scanners.RefDistribution, "https://chromewebstore.google.com/detail/"+extensionID)
+	case edgeAddonsUpdateURL:
+		comp.AddExternalRef(scanners.RefDistribution, "https://microsoftedge.microsoft.com/addons/detail/"+extensionID)
+	default:
+		comp.AddExternalRef(scanners.RefDistribution, updateURL)
+	}
+}
@@ -0,0 +1,32 @@
+package scanners
+
+import (
+	"sort"
+	"time"
+)
+
+// Event is one change to an installed package found in a package manager's
+// logs or metadata: an install, upgrade, removal and so on. Together the
+// events show which versions were present on the machine, and when, even for
+// packages removed since.
+type Event struct {
+	Time            time.Time         `json:"time"`
+	Action          string            `json:"action"` // install, upgrade, remove, ... (see the historical package)
+	Name            string            `json:"name"`
+	Version         string            `json:"version,omitempty"`          // Version installed, or removed for removals
+	PreviousVersion string            `json:"previous_version,omitempty"` // Version an upgrade or downgrade replaced
+	PackageManager  string            `json:"package_manager"`
+	PackageURL      string            `json:"purl,omitempty"`
+	Installed       bool              `json:"currently_installed"` // Whether Version is installed at scan time
+	Source          string            `json:"source"`              // Where the event was found, e.g. dpkg_log
+	LogFile         string            `json:"log_file,omitempty"`
+	Details         map[string]string `json:"details,omitempty"` // Command line, working directory, user, ...
+}
+
+// SortEvents orders events by time, keeping the recorded order of
+// simultaneous events
+func SortEvents(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+}
@@ -0,0 +1,36 @@
+package scanners
+
+// ExpandDependencyGraph builds one component per root with its dependencies nested
+// beneath it. Each node's dependencies are expanded only the first time the node
+// is reached, which keeps cycles finite and matches how the SBOM generator keeps
+// the first occurrence of a bom-ref.
+func ExpandDependencyGraph(nodes map[string]Component, edges map[string][]string, roots []string) []Component {
+	expanded := make(map[string]bool)
+
+	var build func(key string) Component
+	build = func(key string) Component {
+		comp := nodes[key]
+		if expanded[key] {
+			return comp
+		}
+		expanded[key] = true
+
+		comp.Dependencies = nil
+		linked := make(map[string]bool)
+		for _, depKey := range edges[key] {
+			if _, ok := nodes[depKey]; ok && depKey != key && !linked[depKey] {
+				linked[depKey] = true
+				comp.Dependencies = append(comp.Dependencies, build(depKey))
+			}
+		}
+		return comp
+	}
+
+	components := make([]Component, 0, len(roots))
+	for _, key := range roots {
+		if _, ok := nodes[key]; ok {
+			components = append(components, build(key))
+		}
+	}
+	return components
+}
@@ -0,0 +1,173 @@
+package historical
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// aptHistoryPath is apt's log of each transaction and the command that ran it
+const aptHistoryPath = "/var/log/apt/history.log"
+
+// aptHistoryTimeLayout is the local time stamp of Start-Date lines, after
+// collapsing apt's double space between date and time
+const aptHistoryTimeLayout = "2006-01-02 15:04:05"
+
+// aptPackageEntry matches one package in an action list, such as
+// "curl:amd64 (7.88.1-10+deb12u5)", "libssl3:amd64 (3.0.11-1, automatic)" or
+// "git:amd64 (1:2.39.2-1.1, 1:2.39.5-0+deb12u1)"
+var aptPackageEntry = regexp.MustCompile(`([^\s,()]+) \(([^)]*)\)`)
+
+// aptActions maps history.log action lists to recorded actions
+var aptActions = map[string]string{
+	"Install":   ActionInstall,
+	"Reinstall": ActionReinstall,
+	"Upgrade":   ActionUpgrade,
+	"Downgrade": ActionDowngrade,
+	"Remove":    ActionRemove,
+	"Purge":     ActionPurge,
+}
+
+// AptHistoricalScanner reads apt's history.log, which unlike dpkg.log records
+// the command line and the user behind each change
+type AptHistoricalScanner struct{}
+
+// Name returns the scanner name
+func (s *AptHistoricalScanner) Name() string {
+	return "apt-historical"
+}
+
+func (s *AptHistoricalScanner) Scan(cfg *config.Config) ([]Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext parses history.log and its rotations, stopping early if ctx is
+// cancelled
+func (s *AptHistoricalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]Component, error) {
+	if !cfg.IncludeHistorical {
+		return nil, scanners.Skipped("historical tracking disabled")
+	}
+	if runtime.GOOS != "linux" {
+		return nil, scanners.Skipped("apt-historical is only supported on linux")
+	}
+
+	cutoff := lookbackCutoff(cfg)
+	logs := rotatedLogs(aptHistoryPath, cutoff)
+	if len(logs) == 0 {
+		return nil, scanners.NotInstalled("apt")
+	}
+
+	events := newHistory(cutoff)
+	for _, logFile := range logs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err := parseAptHistory(logFile, events); err != nil && cfg.Debug {
+			fmt.Printf("Failed to read %s: %v\n", logFile, err)
+		}
+	}
+
+	return events.list(), nil
+}
+
+// GetLogFiles returns the apt history logs covering the lookback period
+func (s *AptHistoricalScanner) GetLogFiles(cfg *config.Config) ([]string, error) {
+	if !cfg.IncludeRawLogs {
+		return []string{}, nil
+	}
+	return rotatedLogs(aptHistoryPath, lookbackCutoff(cfg)), nil
+}
+
+// parseAptHistory records the package actions in one history log, made of
+// blank-line separated transactions:
+//
+//	Start-Date: 2024-01-15  10:23:40
+//	Commandline: apt-get install curl
+//	Requested-By: alice (1000)
+//	Install: curl:amd64 (7.88.1-10+deb12u5), libcurl4:amd64 (7.88.1-10+deb12u5, automatic)
+//	End-Date: 2024-01-15  10:23:45
+func parseAptHistory(logFile string, events *history) error {
+	reader, err := openLog(logFile)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	var when time.Time
+	var commandline, requestedBy string
+
+	scanner := bufio.NewScanner(reader)
+	// A large upgrade lists hundreds of packages on one line
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		field, value, found := strings.Cut(scanner.Text(), ": ")
+		if !found {
+			continue
+		}
+
+		switch field {
+		case "Start-Date":
+			when, err = time.ParseInLocation(aptHistoryTimeLayout, strings.Join(strings.Fields(value), " "), time.Local)
+			if err != nil {
+				when = time.Time{}
+			}
+			commandline, requestedBy = "", ""
+			continue
+		case "Commandline":
+			commandline = value
+			continue
+		case "Requested-By":
+			requestedBy = value
+			continue
+		}
+
+		action, known := aptActions[field]
+		if !known || when.IsZero() {
+			continue
+		}
+
+		for _, entry := range aptPackageEntry.FindAllStringSubmatch(value, -1) {
+			name, arch, _ := strings.Cut(entry[1], ":")
+
+			var versions []string
+			automatic := false
+			for _, part := range strings.Split(entry[2], ",") {
+				part = strings.TrimSpace(part)
+				if part == "automatic" {
+					automatic = true
+				} else if part != "" {
+					versions = append(versions, part)
+				}
+			}
+			if len(versions) == 0 {
+				continue
+			}
+
+			// Upgrades and downgrades list the old and the new version
+			comp := dpkgComponent(name, versions[len(versions)-1], arch)
+			if commandline != "" {
+				comp.Properties["commandline"] = commandline
+			}
+			if requestedBy != "" {
+				comp.Properties["requested_by"] = requestedBy
+			}
+			if automatic {
+				comp.Properties["automatic"] = "true"
+			}
+			if len(versions) == 2 {
+				events.recordChange(dpkgComponent(name, versions[0], arch), comp, action, when, "apt_history", logFile)
+			} else {
+				events.record(comp, action, when, "apt_history", logFile)
+			}
+		}
+	}
+
+	return scanner.Err()
+}
@@ -1,12 +1,14 @@
 package historical
 
 import (
+	"context"
 	"encoding/json"
 	"os/exec"
 	"time"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/scanners/packagemanagers"
 )
 
 // BrewHistoricalScanner uses Homebrew's built-in install date tracking
@@ -17,42 +19,51 @@ func (s *BrewHistoricalScanner) Name() string {
 	return "brew-historical"
 }
 
-// Scan performs the historical scan using `brew info --json=v2 --installed`
-// Homebrew already tracks install dates - easy win!
 func (s *BrewHistoricalScanner) Scan(cfg *config.Config) ([]Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the historical scan using `brew info --json=v2 --installed`,
+// stopping early if ctx is cancelled.
+// Homebrew already tracks install dates - easy win!
+func (s *BrewHistoricalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]Component, error) {
 	if !cfg.IncludeHistorical {
-		return []Component{}, nil
+		return nil, scanners.Skipped("historical tracking disabled")
 	}
 
 	// Check if brew is available
 	if _, err := exec.LookPath("brew"); err != nil {
-		return []Component{}, nil
+		return nil, scanners.NotInstalled("brew")
 	}
 
-	lookbackDuration := time.Duration(cfg.HistoricalLookbackDays) * 24 * time.Hour
-	cutoffTime := time.Now().Add(-lookbackDuration)
+	events := newHistory(lookbackCutoff(cfg))
 
 	// Get all installed packages with metadata including install dates
-	cmd := exec.Command("brew", "info", "--json=v2", "--installed")
-	output, err := cmd.Output()
+	output, err := packagemanagers.RunCommand(ctx, "", "brew", "info", "--json=v2", "--installed")
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		return []Component{}, nil
 	}
 
+	// Install times are Unix timestamps; a cask's "version" is the latest
+	// available and "installed" the version on disk
 	var brewInfo struct {
 		Formulae []struct {
 			Name              string `json:"name"`
 			FullName          string `json:"full_name"`
-			Versions          map[string]string `json:"versions"`
 			InstalledVersions []struct {
-				Version       string `json:"version"`
-				InstalledTime string `json:"installed_time"`
+				Version            string `json:"version"`
+				Time               int64  `json:"time"`
+				InstalledOnRequest bool   `json:"installed_on_request"`
 			} `json:"installed"`
 		} `json:"formulae"`
 		Casks []struct {
 			Token         string `json:"token"`
 			Version       string `json:"version"`
-			InstalledTime string `json:"installed_time,omitempty"`
+			Installed     string `json:"installed"`
+			InstalledTime int64  `json:"installed_time"`
 		} `json:"casks"`
 	}
 
@@ -60,71 +71,56 @@ func (s *BrewHistoricalScanner) Scan(cfg *config.Config) ([]Component, error) {
 		return []Component{}, nil
 	}
 
-	var components []Component
-
 	// Process formulae (command-line tools)
 	for _, formula := range brewInfo.Formulae {
 		for _, installed := range formula.InstalledVersions {
-			// Parse install time
-			installTime, err := time.Parse(time.RFC3339, installed.InstalledTime)
-			if err != nil {
+			if installed.Time == 0 {
 				continue
 			}
 
-			// Only include if within lookback period
-			if !installTime.After(cutoffTime) {
-				continue
-			}
-
-			components = append(components, scanners.Component{
-				Type:           "library",
+			comp := scanners.Component{
+				Type:           "application",
 				Name:           formula.Name,
 				Version:        installed.Version,
 				PackageManager: "brew",
 				Properties: map[string]string{
-					"install_date": installTime.Format(time.RFC3339),
-					"install_type": "historical", // Will be overridden if still installed
-					"source":       "brew_info",
 					"package_type": "formula",
 				},
-			})
+			}
+			if !installed.InstalledOnRequest {
+				comp.Properties["installed_as_dependency"] = "true"
+			}
+			events.record(comp, ActionInstall, time.Unix(installed.Time, 0), "brew_info", "")
 		}
 	}
 
 	// Process casks (GUI applications)
 	for _, cask := range brewInfo.Casks {
-		if cask.InstalledTime == "" {
+		if cask.InstalledTime == 0 {
 			continue
 		}
 
-		installTime, err := time.Parse(time.RFC3339, cask.InstalledTime)
-		if err != nil {
-			continue
+		version := cask.Installed
+		if version == "" {
+			version = cask.Version
 		}
 
-		if !installTime.After(cutoffTime) {
-			continue
-		}
-
-		components = append(components, scanners.Component{
+		comp := scanners.Component{
 			Type:           "application",
 			Name:           cask.Token,
-			Version:        cask.Version,
-			PackageManager: "brew",
+			Version:        version,
+			PackageManager: "brew-cask",
 			Properties: map[string]string{
-				"install_date": installTime.Format(time.RFC3339),
-				"install_type": "historical", // Will be overridden if still installed
-				"source":       "brew_info",
 				"package_type": "cask",
 			},
-		})
+		}
+		events.record(comp, ActionInstall, time.Unix(cask.InstalledTime, 0), "brew_info", "")
 	}
 
-	return components, nil
+	return events.list(), nil
 }
 
 // GetLogFiles returns no logs (brew info is not a log file)
 func (s *BrewHistoricalScanner) GetLogFiles(cfg *config.Config) ([]string, error) {
 	return []string{}, nil
 }
-
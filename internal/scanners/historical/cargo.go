@@ -0,0 +1,130 @@
+package historical
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/system"
+)
+
+// cargoInstalls is the part of ~/.cargo/.crates2.json that records what
+// `cargo install` has installed
+type cargoInstalls struct {
+	// Keys read "ripgrep 14.1.0 (registry+https://github.com/rust-lang/crates.io-index)"
+	Installs map[string]struct {
+		Bins []string `json:"bins"`
+	} `json:"installs"`
+}
+
+// CargoHistoricalScanner dates crates installed with `cargo install` from
+// .crates2.json and the binaries each install wrote. cargo uninstall removes
+// the entry, so removed crates leave no trace.
+type CargoHistoricalScanner struct{}
+
+// Name returns the scanner name
+func (s *CargoHistoricalScanner) Name() string {
+	return "cargo-historical"
+}
+
+func (s *CargoHistoricalScanner) Scan(cfg *config.Config) ([]Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext reports crates installed within the lookback period, stopping
+// early if ctx is cancelled
+func (s *CargoHistoricalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]Component, error) {
+	if !cfg.IncludeHistorical {
+		return nil, scanners.Skipped("historical tracking disabled")
+	}
+
+	var cargoHomes []string
+	if cargoHome := os.Getenv("CARGO_HOME"); cargoHome != "" {
+		cargoHomes = append(cargoHomes, cargoHome)
+	}
+	for _, home := range system.HomeDirs(cfg) {
+		cargoHomes = append(cargoHomes, filepath.Join(home, ".cargo"))
+	}
+
+	events := newHistory(lookbackCutoff(cfg))
+	found := false
+	seen := make(map[string]bool)
+	for _, cargoHome := range cargoHomes {
+		if seen[cargoHome] {
+			continue
+		}
+		seen[cargoHome] = true
+
+		cratesFile := filepath.Join(cargoHome, ".crates2.json")
+		data, err := os.ReadFile(cratesFile)
+		if err != nil {
+			continue
+		}
+		found = true
+
+		var installs cargoInstalls
+		if err := json.Unmarshal(data, &installs); err != nil {
+			continue
+		}
+
+		keys := make([]string, 0, len(installs.Installs))
+		for key := range installs.Installs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			install := installs.Installs[key]
+			fields := strings.Fields(key)
+			if len(fields) < 2 {
+				continue
+			}
+
+			// The install time is that of the newest binary it wrote
+			var installTime time.Time
+			for _, bin := range install.Bins {
+				if runtime.GOOS == "windows" && !strings.HasSuffix(bin, ".exe") {
+					bin += ".exe"
+				}
+				if info, err := os.Stat(filepath.Join(cargoHome, "bin", bin)); err == nil && info.ModTime().After(installTime) {
+					installTime = info.ModTime()
+				}
+			}
+			if installTime.IsZero() {
+				continue
+			}
+
+			comp := scanners.Component{
+				Type:           "library",
+				Name:           fields[0],
+				Version:        fields[1],
+				PackageManager: "cargo",
+				Location:       cratesFile,
+				Properties:     make(map[string]string),
+			}
+			if len(fields) > 2 {
+				comp.Properties["crate_source"] = strings.Trim(strings.Join(fields[2:], " "), "()")
+			}
+			if len(install.Bins) > 0 {
+				comp.Properties["binaries"] = strings.Join(install.Bins, ", ")
+			}
+			events.record(comp, ActionInstall, installTime, "cargo_crates2", "")
+		}
+	}
+
+	if !found {
+		return nil, scanners.NotInstalled("cargo")
+	}
+
+	return events.list(), nil
+}
@@ -0,0 +1,246 @@
+package historical
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// LogSource is implemented by historical scanners that read log files, so the
+// raw logs they used can be included in the scan archive
+type LogSource interface {
+	GetLogFiles(cfg *config.Config) ([]string, error)
+}
+
+// Actions recorded in the "action" property of historical components
+const (
+	ActionInstall   = "install"
+	ActionReinstall = "reinstall"
+	ActionUpgrade   = "upgrade"
+	ActionDowngrade = "downgrade"
+	ActionChange    = "change" // a different version put in place of an installed one
+	ActionCached    = "cached" // downloaded into a package manager cache
+	ActionRemove    = "remove"
+	ActionPurge     = "purge"    // removed together with its configuration
+	ActionReplaced  = "replaced" // an older version removed by an upgrade or downgrade
+)
+
+// isRemoval reports whether an action takes a package version off the system
+func isRemoval(action string) bool {
+	return action == ActionRemove || action == ActionPurge || action == ActionReplaced
+}
+
+// lookbackCutoff returns the start of the historical lookback window
+func lookbackCutoff(cfg *config.Config) time.Time {
+	lookbackDuration := time.Duration(cfg.HistoricalLookbackDays) * 24 * time.Hour
+	return time.Now().Add(-lookbackDuration)
+}
+
+// rotatedLogs returns a log file and its rotations (dpkg.log.1, dpkg.log.2.gz,
+// dnf.rpm.log-20240101, ...) modified after the cutoff, oldest first so that
+// later events are read last
+func rotatedLogs(path string, cutoff time.Time) []string {
+	matches, err := filepath.Glob(path + "*")
+	if err != nil {
+		return nil
+	}
+
+	type logFile struct {
+		path    string
+		modTime time.Time
+	}
+	var logs []logFile
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil || !info.Mode().IsRegular() || !info.ModTime().After(cutoff) {
+			continue
+		}
+		logs = append(logs, logFile{path: match, modTime: info.ModTime()})
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].modTime.Before(logs[j].modTime)
+	})
+
+	paths := make([]string, 0, len(logs))
+	for _, log := range logs {
+		paths = append(paths, log.path)
+	}
+	return paths
+}
+
+// openLog opens a log file, decompressing it when logrotate has gzipped it
+func openLog(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return file, nil
+	}
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &gzipLog{Reader: reader, file: file}, nil
+}
+
+// gzipLog closes both the decompressor and the underlying file
+type gzipLog struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipLog) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
+
+// history collects package events into one component per package version,
+// carrying the dates of its latest install and removal, its latest action and
+// every event recorded for it
+type history struct {
+	cutoff     time.Time
+	order      []string
+	components map[string]*Component
+	latest     map[string]time.Time
+}
+
+func newHistory(cutoff time.Time) *history {
+	return &history{
+		cutoff:     cutoff,
+		components: make(map[string]*Component),
+		latest:     make(map[string]time.Time),
+	}
+}
+
+// record adds an event for comp. Events before the cutoff are ignored.
+func (h *history) record(comp Component, action string, when time.Time, source, logFile string) {
+	if !when.After(h.cutoff) {
+		return
+	}
+	h.add(comp, action, when, source, logFile, "")
+}
+
+// recordChange adds an upgrade or downgrade from previous to comp as one event.
+// The previous version is recorded as replaced.
+func (h *history) recordChange(previous, comp Component, action string, when time.Time, source, logFile string) {
+	if !when.After(h.cutoff) {
+		return
+	}
+	if previous.Version == comp.Version {
+		h.add(comp, ActionReinstall, when, source, logFile, "")
+		return
+	}
+
+	if previous.Properties == nil {
+		previous.Properties = make(map[string]string)
+	}
+	previous.Properties["replaced_by"] = comp.Version
+	h.update(previous, ActionReplaced, when, source, logFile)
+
+	h.add(comp, action, when, source, logFile, previous.Version)
+}
+
+// add updates the component for comp's package version and attaches the event
+func (h *history) add(comp Component, action string, when time.Time, source, logFile, previousVersion string) {
+	details := make(map[string]string, len(comp.Properties))
+	for property, value := range comp.Properties {
+		details[property] = value
+	}
+	if previousVersion != "" {
+		if comp.Properties == nil {
+			comp.Properties = make(map[string]string)
+		}
+		comp.Properties["previous_version"] = previousVersion
+	}
+
+	existing := h.update(comp, action, when, source, logFile)
+	existing.Events = append(existing.Events, scanners.Event{
+		Time:            when,
+		Action:          action,
+		Name:            comp.Name,
+		Version:         comp.Version,
+		PreviousVersion: previousVersion,
+		PackageManager:  comp.PackageManager,
+		PackageURL:      comp.PackageURL,
+		Source:          source,
+		LogFile:         logFile,
+		Details:         details,
+	})
+}
+
+// update merges an event into the component for comp's package version. The
+// properties of the latest event for a package version win.
+func (h *history) update(comp Component, action string, when time.Time, source, logFile string) *Component {
+	key := comp.PackageManager + ":" + comp.Name + ":" + comp.Version + ":" + comp.Properties["arch"]
+	existing, found := h.components[key]
+	if !found {
+		stored := comp
+		stored.Properties = make(map[string]string, len(comp.Properties))
+		existing = &stored
+		h.components[key] = existing
+		h.order = append(h.order, key)
+	}
+
+	dateProperty := "install_date"
+	if isRemoval(action) {
+		dateProperty = "removed_date"
+	}
+	if previous, err := time.Parse(time.RFC3339, existing.Properties[dateProperty]); err != nil || when.After(previous) {
+		existing.Properties[dateProperty] = when.Format(time.RFC3339)
+	}
+
+	if found && when.Before(h.latest[key]) {
+		return existing
+	}
+	h.latest[key] = when
+	for property, value := range comp.Properties {
+		existing.Properties[property] = value
+	}
+	existing.Properties["action"] = action
+	existing.Properties["install_type"] = getInstallType(when)
+	existing.Properties["source"] = source
+	if logFile != "" {
+		existing.Location = logFile
+		existing.Properties["log_file"] = filepath.Base(logFile)
+	}
+	return existing
+}
+
+// list returns the collected components in the order they were first seen
+func (h *history) list() []Component {
+	components := make([]Component, 0, len(h.order))
+	for _, key := range h.order {
+		components = append(components, *h.components[key])
+	}
+	return components
+}
+
+// readNameVersion reads the name and version fields of a JSON document, such
+// as a package.json or a pnpm store index file
+func readNameVersion(path string) (name, version string, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", false
+	}
+
+	var pkg struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || pkg.Name == "" || pkg.Version == "" {
+		return "", "", false
+	}
+	return pkg.Name, pkg.Version, true
+}
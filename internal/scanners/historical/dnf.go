@@ -0,0 +1,197 @@
+package historical
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/scanners/ospackages"
+)
+
+// dnfRPMLogPath is where dnf logs each rpm transaction element
+const dnfRPMLogPath = "/var/log/dnf.rpm.log"
+
+// dnfLogLine matches a package line of dnf.rpm.log, such as
+// "2024-01-15T10:23:45+0000 SUBDEBUG Upgrade: curl-7.76.1-29.el9_4.x86_64"
+var dnfLogLine = regexp.MustCompile(`^(\S+) \S+ (\w+): (\S+)$`)
+
+// dnfTimeLayouts are the time stamp formats written by dnf releases
+var dnfTimeLayouts = []string{
+	"2006-01-02T15:04:05-0700",
+	time.RFC3339,
+}
+
+// dnfActions maps dnf's transaction element names to recorded actions. The
+// past tense names are the versions an upgrade, downgrade or obsoletion removed.
+var dnfActions = map[string]string{
+	"Install":     ActionInstall,
+	"Installed":   ActionInstall,
+	"Reinstall":   ActionReinstall,
+	"Reinstalled": ActionReinstall,
+	"Upgrade":     ActionUpgrade,
+	"Upgraded":    ActionReplaced,
+	"Downgrade":   ActionDowngrade,
+	"Downgraded":  ActionReplaced,
+	"Obsoleted":   ActionReplaced,
+	"Erase":       ActionRemove,
+	"Erased":      ActionRemove,
+	"Removed":     ActionRemove,
+}
+
+// DnfHistoricalScanner reads dnf.rpm.log for RPM packages installed, upgraded
+// or removed with dnf within the lookback period
+type DnfHistoricalScanner struct{}
+
+// Name returns the scanner name
+func (s *DnfHistoricalScanner) Name() string {
+	return "dnf-historical"
+}
+
+func (s *DnfHistoricalScanner) Scan(cfg *config.Config) ([]Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext parses dnf.rpm.log and its rotations, stopping early if ctx is
+// cancelled
+func (s *DnfHistoricalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]Component, error) {
+	if !cfg.IncludeHistorical {
+		return nil, scanners.Skipped("historical tracking disabled")
+	}
+	if runtime.GOOS != "linux" {
+		return nil, scanners.Skipped("dnf-historical is only supported on linux")
+	}
+
+	cutoff := lookbackCutoff(cfg)
+	logs := rotatedLogs(dnfRPMLogPath, cutoff)
+	if len(logs) == 0 {
+		return nil, scanners.NotInstalled("dnf")
+	}
+
+	events := newHistory(cutoff)
+	for _, logFile := range logs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err := parseDnfRPMLog(logFile, events); err != nil && cfg.Debug {
+			fmt.Printf("Failed to read %s: %v\n", logFile, err)
+		}
+	}
+
+	return events.list(), nil
+}
+
+// GetLogFiles returns the dnf rpm logs covering the lookback period
+func (s *DnfHistoricalScanner) GetLogFiles(cfg *config.Config) ([]string, error) {
+	if !cfg.IncludeRawLogs {
+		return []string{}, nil
+	}
+	return rotatedLogs(dnfRPMLogPath, lookbackCutoff(cfg)), nil
+}
+
+// parseDnfRPMLog records the package actions in one dnf rpm log
+func parseDnfRPMLog(logFile string, events *history) error {
+	reader, err := openLog(logFile)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// An upgrade logs the new package ("Upgrade") then the one it replaced
+	// ("Upgraded"); pair them into one change per package and arch
+	type pendingChange struct {
+		comp   Component
+		action string
+		when   time.Time
+	}
+	pending := make(map[string]pendingChange)
+	var pendingOrder []string
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		match := dnfLogLine.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+
+		action, known := dnfActions[match[2]]
+		if !known {
+			continue
+		}
+		when, ok := parseDnfTime(match[1])
+		if !ok {
+			continue
+		}
+		name, version, arch, ok := parseNEVRA(match[3])
+		if !ok {
+			continue
+		}
+
+		comp := ospackages.NewPackageComponent("rpm", "rpm", name, version, arch, "redhat")
+		key := name + "." + arch
+
+		switch {
+		case action == ActionUpgrade || action == ActionDowngrade:
+			if _, found := pending[key]; !found {
+				pendingOrder = append(pendingOrder, key)
+			}
+			pending[key] = pendingChange{comp: comp, action: action, when: when}
+		case action == ActionReplaced && match[2] != "Obsoleted":
+			if change, found := pending[key]; found {
+				delete(pending, key)
+				events.recordChange(comp, change.comp, change.action, change.when, "dnf_log", logFile)
+			} else {
+				events.record(comp, action, when, "dnf_log", logFile)
+			}
+		default:
+			events.record(comp, action, when, "dnf_log", logFile)
+		}
+	}
+
+	for _, key := range pendingOrder {
+		if change, found := pending[key]; found {
+			delete(pending, key)
+			events.record(change.comp, change.action, change.when, "dnf_log", logFile)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// parseDnfTime parses a dnf log time stamp
+func parseDnfTime(value string) (time.Time, bool) {
+	for _, layout := range dnfTimeLayouts {
+		if when, err := time.Parse(layout, value); err == nil {
+			return when, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseNEVRA splits "name-[epoch:]version-release.arch" into the name, the
+// "[epoch:]version-release" the rpm scanner reports as version, and the arch
+func parseNEVRA(nevra string) (name, version, arch string, ok bool) {
+	dot := strings.LastIndex(nevra, ".")
+	if dot <= 0 {
+		return "", "", "", false
+	}
+	nevr, arch := nevra[:dot], nevra[dot+1:]
+
+	releaseDash := strings.LastIndex(nevr, "-")
+	if releaseDash <= 0 {
+		return "", "", "", false
+	}
+	versionDash := strings.LastIndex(nevr[:releaseDash], "-")
+	if versionDash <= 0 {
+		return "", "", "", false
+	}
+
+	// The rpm scanner omits a zero epoch
+	version = strings.TrimPrefix(nevr[versionDash+1:], "0:")
+	return nevr[:versionDash], version, arch, true
+}
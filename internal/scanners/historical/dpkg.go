@@ -0,0 +1,124 @@
+package historical
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/scanners/ospackages"
+)
+
+// dpkgLogPath is dpkg's own log of every package action, rotated by logrotate
+const dpkgLogPath = "/var/log/dpkg.log"
+
+// dpkgLogTimeLayout is the local time stamp at the start of each dpkg.log line
+const dpkgLogTimeLayout = "2006-01-02 15:04:05"
+
+// DpkgHistoricalScanner reads dpkg.log for Debian packages installed, upgraded,
+// removed or purged within the lookback period, whether by apt, dpkg -i or a
+// desktop package tool
+type DpkgHistoricalScanner struct{}
+
+// Name returns the scanner name
+func (s *DpkgHistoricalScanner) Name() string {
+	return "dpkg-historical"
+}
+
+func (s *DpkgHistoricalScanner) Scan(cfg *config.Config) ([]Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext parses dpkg.log and its rotations, stopping early if ctx is
+// cancelled
+func (s *DpkgHistoricalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]Component, error) {
+	if !cfg.IncludeHistorical {
+		return nil, scanners.Skipped("historical tracking disabled")
+	}
+	if runtime.GOOS != "linux" {
+		return nil, scanners.Skipped("dpkg-historical is only supported on linux")
+	}
+
+	cutoff := lookbackCutoff(cfg)
+	logs := rotatedLogs(dpkgLogPath, cutoff)
+	if len(logs) == 0 {
+		return nil, scanners.NotInstalled("dpkg")
+	}
+
+	events := newHistory(cutoff)
+	for _, logFile := range logs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err := parseDpkgLog(logFile, events); err != nil && cfg.Debug {
+			fmt.Printf("Failed to read %s: %v\n", logFile, err)
+		}
+	}
+
+	return events.list(), nil
+}
+
+// GetLogFiles returns the dpkg logs covering the lookback period
+func (s *DpkgHistoricalScanner) GetLogFiles(cfg *config.Config) ([]string, error) {
+	if !cfg.IncludeRawLogs {
+		return []string{}, nil
+	}
+	return rotatedLogs(dpkgLogPath, lookbackCutoff(cfg)), nil
+}
+
+// parseDpkgLog records the package actions in one dpkg log, whose lines read
+// "2024-01-15 10:23:45 upgrade curl:amd64 7.88.1-10 7.88.1-10+deb12u5"
+func parseDpkgLog(logFile string, events *history) error {
+	reader, err := openLog(logFile)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 6 {
+			continue
+		}
+
+		action := fields[2]
+		switch action {
+		case ActionInstall, ActionUpgrade, ActionRemove, ActionPurge:
+		default:
+			// status, configure, trigproc and startup lines repeat the above
+			continue
+		}
+
+		when, err := time.ParseInLocation(dpkgLogTimeLayout, fields[0]+" "+fields[1], time.Local)
+		if err != nil {
+			continue
+		}
+
+		name, arch, _ := strings.Cut(fields[3], ":")
+		oldVersion, newVersion := fields[4], fields[5]
+
+		switch action {
+		case ActionInstall, ActionUpgrade:
+			if oldVersion != "<none>" {
+				events.recordChange(dpkgComponent(name, oldVersion, arch), dpkgComponent(name, newVersion, arch), action, when, "dpkg_log", logFile)
+			} else {
+				events.record(dpkgComponent(name, newVersion, arch), action, when, "dpkg_log", logFile)
+			}
+		default:
+			events.record(dpkgComponent(name, oldVersion, arch), action, when, "dpkg_log", logFile)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// dpkgComponent builds a component matching the one the dpkg scanner reports
+// for an installed package
+func dpkgComponent(name, version, arch string) Component {
+	return ospackages.NewPackageComponent("dpkg", "deb", name, version, arch, "debian")
+}
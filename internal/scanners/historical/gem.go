@@ -0,0 +1,123 @@
+package historical
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/system"
+)
+
+// gemspecFileName splits an installed gemspec's file name into the gem name,
+// version and optional platform, as in "nokogiri-1.16.0-x86_64-linux.gemspec"
+var gemspecFileName = regexp.MustCompile(`^(.+?)-(\d[^-]*)(?:-(.+))?\.gemspec$`)
+
+// GemHistoricalScanner dates gem installs from the gemspecs RubyGems writes to
+// each gem directory's specifications folder at install time. Default gems
+// bundled with Ruby live in specifications/default and are not reported.
+type GemHistoricalScanner struct{}
+
+// Name returns the scanner name
+func (s *GemHistoricalScanner) Name() string {
+	return "gem-historical"
+}
+
+func (s *GemHistoricalScanner) Scan(cfg *config.Config) ([]Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext reports gems installed within the lookback period, stopping early
+// if ctx is cancelled
+func (s *GemHistoricalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]Component, error) {
+	if !cfg.IncludeHistorical {
+		return nil, scanners.Skipped("historical tracking disabled")
+	}
+
+	specDirs := gemSpecificationDirs(cfg)
+	if len(specDirs) == 0 {
+		return nil, scanners.NotInstalled("gem")
+	}
+
+	events := newHistory(lookbackCutoff(cfg))
+	for _, specDir := range specDirs {
+		gemspecs, _ := filepath.Glob(filepath.Join(specDir, "*.gemspec"))
+		for _, gemspec := range gemspecs {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			info, err := os.Stat(gemspec)
+			if err != nil || !info.ModTime().After(events.cutoff) {
+				continue
+			}
+
+			match := gemspecFileName.FindStringSubmatch(filepath.Base(gemspec))
+			if match == nil {
+				continue
+			}
+
+			comp := scanners.Component{
+				Type:           "library",
+				Name:           match[1],
+				Version:        match[2],
+				PackageManager: "gem",
+				Location:       gemspec,
+				Properties:     make(map[string]string),
+			}
+			if match[3] != "" {
+				comp.Properties["platform"] = match[3]
+			}
+			events.record(comp, ActionInstall, info.ModTime(), "gemspec", "")
+		}
+	}
+
+	return events.list(), nil
+}
+
+// gemSpecificationDirs returns the specifications directories of system, user,
+// Homebrew, rbenv, rvm and asdf gem paths
+func gemSpecificationDirs(cfg *config.Config) []string {
+	var patterns []string
+	switch runtime.GOOS {
+	case "windows":
+		patterns = append(patterns, `C:\Ruby*\lib\ruby\gems\*\specifications`)
+		for _, home := range system.HomeDirs(cfg) {
+			patterns = append(patterns, filepath.Join(home, ".gem", "ruby", "*", "specifications"))
+		}
+	default:
+		patterns = append(patterns,
+			"/var/lib/gems/*/specifications",
+			"/usr/share/gems/specifications",
+			"/usr/lib/ruby/gems/*/specifications",
+			"/usr/local/lib/ruby/gems/*/specifications",
+			"/usr/local/share/gems/specifications",
+			"/opt/homebrew/lib/ruby/gems/*/specifications",
+			"/Library/Ruby/Gems/*/specifications",
+		)
+		for _, home := range system.HomeDirs(cfg) {
+			patterns = append(patterns,
+				filepath.Join(home, ".gem", "ruby", "*", "specifications"),
+				filepath.Join(home, ".local", "share", "gem", "ruby", "*", "specifications"),
+				filepath.Join(home, ".rbenv", "versions", "*", "lib", "ruby", "gems", "*", "specifications"),
+				filepath.Join(home, ".rvm", "gems", "*", "specifications"),
+				filepath.Join(home, ".asdf", "installs", "ruby", "*", "lib", "ruby", "gems", "*", "specifications"),
+			)
+		}
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		for _, dir := range matches {
+			if real, err := filepath.EvalSymlinks(dir); err == nil && !seen[real] {
+				seen[real] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
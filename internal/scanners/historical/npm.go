@@ -2,17 +2,48 @@ package historical
 
 import (
 	"bufio"
+	"context"
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/system"
 )
 
-// NPMHistoricalScanner scans npm installation logs for historical package data
+// npmLogLine matches a debug log line: "<n> <level> <message>"
+var npmLogLine = regexp.MustCompile(`^\d+ (\w+) (.*)$`)
+
+// npmPlaceDep matches arborist placing a package in the ideal tree, such as
+// "placeDep ROOT lodash@4.17.21 OK for:  want: ^4.17.0" or
+// "placeDep node_modules/foo @scope/bar@1.0.0 REPLACE for: foo@1.0.0 want: ^1.0.0"
+var npmPlaceDep = regexp.MustCompile(`^placeDep (\S+) (\S+) (OK|REPLACE) for:`)
+
+// npmReifyChange matches the changes reify made to node_modules, such as
+// "ADD node_modules/@scope/bar"
+var npmReifyChange = regexp.MustCompile(`^(ADD|CHANGE|REMOVE) (\S+)$`)
+
+// npmTarballFetch matches a package tarball download, such as
+// "fetch GET 200 https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz 52ms (cache miss)"
+var npmTarballFetch = regexp.MustCompile(`^fetch GET \d+ (\S+\.tgz)\b`)
+
+// npmReifyActions maps reify changes to recorded actions
+var npmReifyActions = map[string]string{
+	"ADD":    ActionInstall,
+	"CHANGE": ActionChange,
+	"REMOVE": ActionRemove,
+}
+
+// NPMHistoricalScanner scans npm debug logs for packages added, changed and
+// removed by npm 7-10 within the lookback period
 type NPMHistoricalScanner struct{}
 
 // Name returns the scanner name
@@ -20,52 +51,39 @@ func (s *NPMHistoricalScanner) Name() string {
 	return "npm-historical"
 }
 
-// Scan performs the historical scan (best effort - parse simple log entries)
 func (s *NPMHistoricalScanner) Scan(cfg *config.Config) ([]Component, error) {
-	if !cfg.IncludeHistorical {
-		return []Component{}, nil
-	}
-
-	var components []Component
-	lookbackDuration := time.Duration(cfg.HistoricalLookbackDays) * 24 * time.Hour
-	cutoffTime := time.Now().Add(-lookbackDuration)
-
-	// Find npm log directory
-	logDir := filepath.Join(os.Getenv("HOME"), ".npm", "_logs")
-	if os.Getenv("USERPROFILE") != "" { // Windows
-		logDir = filepath.Join(os.Getenv("USERPROFILE"), ".npm", "_logs")
-	}
+	return s.ScanContext(context.Background(), cfg)
+}
 
-	if _, err := os.Stat(logDir); err != nil {
-		return components, nil // No logs, skip silently
+// ScanContext parses the debug logs of every user, oldest first, stopping early
+// if ctx is cancelled
+func (s *NPMHistoricalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]Component, error) {
+	if !cfg.IncludeHistorical {
+		return nil, scanners.Skipped("historical tracking disabled")
 	}
 
-	// Read log files within lookback period
-	logs, err := filepath.Glob(filepath.Join(logDir, "*-debug*.log"))
-	if err != nil {
-		return components, nil
-	}
+	cutoff := lookbackCutoff(cfg)
+	events := newHistory(cutoff)
 
-	seen := make(map[string]bool) // Deduplicate packages
+	// Removals name only the node_modules path, so the version comes from the
+	// last install into that path seen in an earlier log
+	placed := make(map[string]string)
 
-	for _, logFile := range logs {
-		fileInfo, err := os.Stat(logFile)
-		if err != nil || !fileInfo.ModTime().After(cutoffTime) {
-			continue
+	for _, logFile := range npmDebugLogs(cfg, cutoff) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-
-		// Simple parse - just look for "npm install package@version" lines
-		parsed := parseNPMLogSimple(logFile, fileInfo.ModTime())
-		for _, comp := range parsed {
-			key := comp.Name + "@" + comp.Version
-			if !seen[key] {
-				components = append(components, comp)
-				seen[key] = true
+		session, err := parseNPMDebugLog(logFile)
+		if err != nil {
+			if cfg.Debug {
+				fmt.Printf("Failed to read %s: %v\n", logFile, err)
 			}
+			continue
 		}
+		session.record(events, placed)
 	}
 
-	return components, nil
+	return events.list(), nil
 }
 
 // GetLogFiles returns relevant log files for archiving
@@ -73,87 +91,280 @@ func (s *NPMHistoricalScanner) GetLogFiles(cfg *config.Config) ([]string, error)
 	if !cfg.IncludeRawLogs {
 		return []string{}, nil
 	}
+	return npmDebugLogs(cfg, lookbackCutoff(cfg)), nil
+}
 
-	lookbackDuration := time.Duration(cfg.HistoricalLookbackDays) * 24 * time.Hour
-	cutoffTime := time.Now().Add(-lookbackDuration)
+// npmDebugLogs returns the debug logs of every user modified after the cutoff,
+// oldest first. Log names start with the UTC time npm started.
+func npmDebugLogs(cfg *config.Config, cutoff time.Time) []string {
+	var logs []string
+	for _, home := range system.HomeDirs(cfg) {
+		logDirs := []string{filepath.Join(home, ".npm", "_logs")}
+		if runtime.GOOS == "windows" {
+			logDirs = append(logDirs, filepath.Join(home, "AppData", "Local", "npm-cache", "_logs"))
+		}
 
-	logDir := filepath.Join(os.Getenv("HOME"), ".npm", "_logs")
-	if os.Getenv("USERPROFILE") != "" {
-		logDir = filepath.Join(os.Getenv("USERPROFILE"), ".npm", "_logs")
+		for _, logDir := range logDirs {
+			// npm 7 and 8 write "<time>-debug.log", later releases "<time>-debug-<n>.log"
+			matches, err := filepath.Glob(filepath.Join(logDir, "*-debug*.log"))
+			if err != nil {
+				continue
+			}
+			for _, match := range matches {
+				if info, err := os.Stat(match); err == nil && info.ModTime().After(cutoff) {
+					logs = append(logs, match)
+				}
+			}
+		}
 	}
 
-	logs, err := filepath.Glob(filepath.Join(logDir, "*-debug*.log"))
-	if err != nil {
-		return []string{}, nil
-	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		return filepath.Base(logs[i]) < filepath.Base(logs[j])
+	})
+	return logs
+}
 
-	var relevantLogs []string
-	for _, log := range logs {
-		fileInfo, err := os.Stat(log)
-		if err == nil && fileInfo.ModTime().After(cutoffTime) {
-			relevantLogs = append(relevantLogs, log)
-		}
-	}
+// npmSession is what one npm command's debug log records
+type npmSession struct {
+	logFile string
+	started time.Time
+	command string // e.g. "npm install lodash"
+	cwd     string
+	global  bool
+	exit    string
 
-	return relevantLogs, nil
+	placed   map[string]string // node_modules path -> name@version
+	tarballs map[string]string // name@version -> tarball URL
+	changes  []npmChange
 }
 
-// parseNPMLogSimple does simple best-effort parsing
-func parseNPMLogSimple(logPath string, logTime time.Time) []Component {
-	var components []Component
+// npmChange is one change reify made to node_modules
+type npmChange struct {
+	action   string
+	location string // e.g. "node_modules/@scope/bar"
+}
 
-	file, err := os.Open(logPath)
+// parseNPMDebugLog reads the command, working directory, placed packages,
+// downloaded tarballs and node_modules changes from a debug log
+func parseNPMDebugLog(logFile string) (*npmSession, error) {
+	file, err := os.Open(logFile)
 	if err != nil {
-		return components
+		return nil, err
 	}
 	defer file.Close()
 
-	// Simple regex to find package installations
-	// Matches lines like: "npm install react@18.2.0" or "added 5 packages"
-	installRegex := regexp.MustCompile(`(?:npm (?:install|i|add)\s+|added\s+\d+\s+packages?)([a-zA-Z0-9@/_-]+)`)
+	session := &npmSession{
+		logFile:  logFile,
+		started:  npmLogTime(logFile),
+		placed:   make(map[string]string),
+		tarballs: make(map[string]string),
+	}
 
+	var argv []string
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	for scanner.Scan() {
-		line := scanner.Text()
+		match := npmLogLine.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		level, message := match[1], match[2]
 
-		matches := installRegex.FindStringSubmatch(line)
-		if len(matches) > 1 {
-			pkg := strings.TrimSpace(matches[1])
-			if pkg == "" || strings.HasPrefix(pkg, "-") {
-				continue
+		switch level {
+		case "verbose":
+			key, value, _ := strings.Cut(message, " ")
+			switch key {
+			case "title":
+				session.command = value
+			case "argv":
+				argv = npmArgv(value)
+			case "cwd":
+				session.cwd = value
+			case "exit":
+				session.exit = strings.TrimSpace(value)
 			}
-
-			name := pkg
-			version := ""
-
-			// Split package@version
-			if strings.Contains(pkg, "@") && !strings.HasPrefix(pkg, "@") {
-				parts := strings.Split(pkg, "@")
-				name = parts[0]
-				if len(parts) > 1 {
-					version = parts[1]
+		case "silly":
+			if m := npmPlaceDep.FindStringSubmatch(message); m != nil {
+				name, version := splitNPMSpec(m[2])
+				if name != "" && version != "" {
+					session.placed[npmPlacement(m[1], name)] = name + "@" + version
 				}
+			} else if m := npmReifyChange.FindStringSubmatch(message); m != nil {
+				session.changes = append(session.changes, npmChange{action: npmReifyActions[m[1]], location: m[2]})
 			}
-
-			if name != "" {
-				components = append(components, scanners.Component{
-					Type:           "library",
-					Name:           name,
-					Version:        version,
-					PackageManager: "npm",
-					Location:       logPath,
-					Properties: map[string]string{
-						"install_date": logTime.Format(time.RFC3339),
-						"install_type": getInstallType(logTime),
-						"source":       "npm_log",
-						"log_file":     filepath.Base(logPath),
-					},
-				})
+		case "http":
+			if m := npmTarballFetch.FindStringSubmatch(message); m != nil {
+				if name, version, ok := npmTarballPackage(m[1]); ok {
+					session.tarballs[name+"@"+version] = m[1]
+				}
 			}
 		}
 	}
 
-	return components
+	for _, arg := range argv {
+		if arg == "-g" || arg == "--global" || arg == "--location=global" {
+			session.global = true
+		}
+	}
+	if session.command == "" && len(argv) > 0 {
+		session.command = "npm " + strings.Join(argv, " ")
+	}
+
+	return session, scanner.Err()
+}
+
+// record adds the session's node_modules changes to events. placed carries the
+// versions installed into each project's node_modules across sessions.
+func (s *npmSession) record(events *history, placed map[string]string) {
+	root := s.cwd
+	if s.global {
+		root = "global"
+	}
+
+	for _, change := range s.changes {
+		name := npmLocationName(change.location)
+		if name == "" {
+			continue
+		}
+		key := root + "|" + change.location
+
+		if change.action == ActionRemove {
+			_, version := splitNPMSpec(placed[key])
+			events.record(s.component(name, version, change.location), ActionRemove, s.started, "npm_log", s.logFile)
+			delete(placed, key)
+			continue
+		}
+
+		spec, found := s.placed[change.location]
+		if !found {
+			continue
+		}
+		_, version := splitNPMSpec(spec)
+
+		previous, replaced := placed[key]
+		placed[key] = spec
+
+		comp := s.component(name, version, change.location)
+		if replaced {
+			_, oldVersion := splitNPMSpec(previous)
+			events.recordChange(s.component(name, oldVersion, change.location), comp, change.action, s.started, "npm_log", s.logFile)
+		} else {
+			events.record(comp, change.action, s.started, "npm_log", s.logFile)
+		}
+	}
+}
+
+// component builds the component for a package the session changed
+func (s *npmSession) component(name, version, location string) Component {
+	comp := scanners.Component{
+		Type:           "library",
+		Name:           name,
+		Version:        version,
+		PackageManager: "npm",
+		Properties:     make(map[string]string),
+	}
+	if s.command != "" {
+		comp.Properties["command"] = s.command
+	}
+	if s.global {
+		comp.Properties["global"] = "true"
+	} else if s.cwd != "" {
+		comp.Properties["cwd"] = s.cwd
+		comp.Properties["install_path"] = filepath.Join(s.cwd, filepath.FromSlash(location))
+	}
+	if s.exit != "" && s.exit != "0" {
+		comp.Properties["exit_code"] = s.exit
+	}
+
+	if tarball, found := s.tarballs[name+"@"+version]; found {
+		comp.AddExternalRef(scanners.RefDistribution, tarball)
+		if i := strings.Index(tarball, "/"+name+"/-/"); i > 0 {
+			comp.Properties["registry"] = tarball[:i+1]
+		}
+	}
+	return comp
+}
+
+// npmLogTime returns the UTC start time encoded in a debug log's name, such as
+// "2024-01-15T10_23_45_123Z-debug-0.log", falling back to its modification time
+func npmLogTime(logFile string) time.Time {
+	base := filepath.Base(logFile)
+	if len(base) >= 19 {
+		if started, err := time.Parse("2006-01-02T15_04_05", base[:19]); err == nil {
+			return started
+		}
+	}
+	if info, err := os.Stat(logFile); err == nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
+
+// npmArgv splits the quoted argument list of an argv line:
+// `"install" "lodash" "--save-dev"`
+func npmArgv(value string) []string {
+	var argv []string
+	for _, field := range strings.Fields(value) {
+		if arg, err := strconv.Unquote(field); err == nil {
+			argv = append(argv, arg)
+		} else {
+			argv = append(argv, field)
+		}
+	}
+	return argv
+}
+
+// npmPlacement returns the node_modules path a placeDep line puts a package in
+func npmPlacement(parent, name string) string {
+	if parent == "ROOT" {
+		return "node_modules/" + name
+	}
+	return parent + "/node_modules/" + name
+}
+
+// npmLocationName returns the package name at the end of a node_modules path
+func npmLocationName(location string) string {
+	i := strings.LastIndex(location, "node_modules/")
+	if i < 0 {
+		return ""
+	}
+	return location[i+len("node_modules/"):]
+}
+
+// splitNPMSpec splits "name@version", where scoped names start with '@'
+func splitNPMSpec(spec string) (name, version string) {
+	i := strings.LastIndex(spec, "@")
+	if i <= 0 {
+		return spec, ""
+	}
+	return spec[:i], spec[i+1:]
+}
+
+// npmTarballPackage reads the package name and version from a registry tarball
+// URL, ".../<name>/-/<unscoped name>-<version>.tgz"
+func npmTarballPackage(tarball string) (name, version string, ok bool) {
+	parsed, err := url.Parse(tarball)
+	if err != nil {
+		return "", "", false
+	}
+	packagePath, fileName, found := strings.Cut(parsed.Path, "/-/")
+	if !found {
+		return "", "", false
+	}
+
+	// The name follows the registry path: one segment, or two when scoped
+	segments := strings.Split(strings.Trim(packagePath, "/"), "/")
+	name = segments[len(segments)-1]
+	if len(segments) > 1 && strings.HasPrefix(segments[len(segments)-2], "@") {
+		name = segments[len(segments)-2] + "/" + name
+	}
+
+	unscoped := name[strings.LastIndex(name, "/")+1:]
+	version, found = strings.CutPrefix(strings.TrimSuffix(fileName, ".tgz"), unscoped+"-")
+	if !found || version == "" {
+		return "", "", false
+	}
+	return name, version, true
 }
 
 // getInstallType returns "historical" - will be overridden to "current" during deduplication
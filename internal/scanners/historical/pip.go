@@ -0,0 +1,113 @@
+package historical
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/scanners/packagemanagers"
+)
+
+// PipHistoricalScanner dates Python package installs from their dist-info
+// directories, which pip writes when it installs a distribution. pip keeps no
+// log, so packages uninstalled since leave no trace.
+type PipHistoricalScanner struct{}
+
+// Name returns the scanner name
+func (s *PipHistoricalScanner) Name() string {
+	return "pip-historical"
+}
+
+func (s *PipHistoricalScanner) Scan(cfg *config.Config) ([]Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext reports distributions in the global site-packages directories
+// installed within the lookback period, stopping early if ctx is cancelled
+func (s *PipHistoricalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]Component, error) {
+	if !cfg.IncludeHistorical {
+		return nil, scanners.Skipped("historical tracking disabled")
+	}
+
+	sitePackagesDirs := packagemanagers.GlobalSitePackages(cfg)
+	if len(sitePackagesDirs) == 0 {
+		return nil, scanners.NotInstalled("pip")
+	}
+
+	events := newHistory(lookbackCutoff(cfg))
+	for _, sitePackages := range sitePackagesDirs {
+		entries, err := os.ReadDir(sitePackages)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if !entry.IsDir() || !strings.HasSuffix(entry.Name(), ".dist-info") {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || !info.ModTime().After(events.cutoff) {
+				continue
+			}
+
+			distInfo := filepath.Join(sitePackages, entry.Name())
+			name, version, ok := readDistName(filepath.Join(distInfo, "METADATA"))
+			if !ok {
+				continue
+			}
+
+			comp := scanners.Component{
+				Type:           "library",
+				Name:           name,
+				Version:        version,
+				PackageManager: "pip",
+				Location:       sitePackages,
+				Properties: map[string]string{
+					"metadata_path": distInfo,
+				},
+			}
+			events.record(comp, ActionInstall, info.ModTime(), "site_packages", "")
+		}
+	}
+
+	return events.list(), nil
+}
+
+// readDistName reads the Name and Version headers of a METADATA file, which the
+// pip scanner reports rather than the normalized directory name
+func readDistName(path string) (name, version string, ok bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", "", false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		switch key {
+		case "Name":
+			name = strings.TrimSpace(value)
+		case "Version":
+			version = strings.TrimSpace(value)
+		}
+		if name != "" && version != "" {
+			return name, version, true
+		}
+	}
+	return "", "", false
+}
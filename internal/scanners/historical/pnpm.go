@@ -0,0 +1,114 @@
+package historical
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/system"
+)
+
+// pnpmIndexPatterns locate the per-package index files inside a pnpm store:
+// files/<xx>/<hash>-index.json up to store v3, index/<xx>/<hash>-<name>@<version>.json
+// from v10. Index files written by older releases without the package name and
+// version are skipped.
+var pnpmIndexPatterns = []string{
+	filepath.Join("v*", "files", "*", "*-index.json"),
+	filepath.Join("v*", "index", "*", "*.json"),
+}
+
+// PnpmHistoricalScanner reports packages added to the pnpm content-addressable
+// store within the lookback period. Every project on the machine installs from
+// the store, so it records downloads for projects deleted since.
+type PnpmHistoricalScanner struct{}
+
+// Name returns the scanner name
+func (s *PnpmHistoricalScanner) Name() string {
+	return "pnpm-historical"
+}
+
+func (s *PnpmHistoricalScanner) Scan(cfg *config.Config) ([]Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext reads the pnpm store of every user, stopping early if ctx is
+// cancelled
+func (s *PnpmHistoricalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]Component, error) {
+	if !cfg.IncludeHistorical {
+		return nil, scanners.Skipped("historical tracking disabled")
+	}
+
+	storeDirs := pnpmStoreDirs(cfg)
+	if len(storeDirs) == 0 {
+		return nil, scanners.NotInstalled("pnpm")
+	}
+
+	events := newHistory(lookbackCutoff(cfg))
+	for _, storeDir := range storeDirs {
+		for _, pattern := range pnpmIndexPatterns {
+			indexFiles, _ := filepath.Glob(filepath.Join(storeDir, pattern))
+			for _, indexFile := range indexFiles {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				info, err := os.Stat(indexFile)
+				if err != nil || !info.ModTime().After(events.cutoff) {
+					continue
+				}
+
+				name, version, ok := readNameVersion(indexFile)
+				if !ok {
+					continue
+				}
+
+				comp := scanners.Component{
+					Type:           "library",
+					Name:           name,
+					Version:        version,
+					PackageManager: "pnpm",
+					Location:       storeDir,
+					Properties:     make(map[string]string),
+				}
+				events.record(comp, ActionCached, info.ModTime(), "pnpm_store", "")
+			}
+		}
+	}
+
+	return events.list(), nil
+}
+
+// pnpmStoreDirs returns the pnpm store directories that exist for each user
+func pnpmStoreDirs(cfg *config.Config) []string {
+	var candidates []string
+	if pnpmHome := os.Getenv("PNPM_HOME"); pnpmHome != "" {
+		candidates = append(candidates, filepath.Join(pnpmHome, "store"))
+	}
+	for _, home := range system.HomeDirs(cfg) {
+		switch runtime.GOOS {
+		case "windows":
+			candidates = append(candidates, filepath.Join(home, "AppData", "Local", "pnpm", "store"))
+		case "darwin":
+			candidates = append(candidates, filepath.Join(home, "Library", "pnpm", "store"))
+		default:
+			candidates = append(candidates, filepath.Join(home, ".local", "share", "pnpm", "store"))
+		}
+		// Older releases kept the store in the home directory
+		candidates = append(candidates, filepath.Join(home, ".pnpm-store"))
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, dir := range candidates {
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,104 @@
+package historical
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/system"
+)
+
+// YarnHistoricalScanner reports packages yarn (v1) downloaded into its cache
+// within the lookback period. Each cache entry (v6/npm-<name>-<version>-...)
+// holds the unpacked package, so it covers installs into any project, including
+// ones deleted since.
+type YarnHistoricalScanner struct{}
+
+// Name returns the scanner name
+func (s *YarnHistoricalScanner) Name() string {
+	return "yarn-historical"
+}
+
+func (s *YarnHistoricalScanner) Scan(cfg *config.Config) ([]Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext reads the yarn cache of every user, stopping early if ctx is
+// cancelled
+func (s *YarnHistoricalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]Component, error) {
+	if !cfg.IncludeHistorical {
+		return nil, scanners.Skipped("historical tracking disabled")
+	}
+
+	cacheDirs := yarnCacheDirs(cfg)
+	if len(cacheDirs) == 0 {
+		return nil, scanners.NotInstalled("yarn")
+	}
+
+	events := newHistory(lookbackCutoff(cfg))
+	for _, cacheDir := range cacheDirs {
+		entries, _ := filepath.Glob(filepath.Join(cacheDir, "v*", "npm-*"))
+		for _, entry := range entries {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			info, err := os.Stat(entry)
+			if err != nil || !info.IsDir() || !info.ModTime().After(events.cutoff) {
+				continue
+			}
+
+			// The package is unpacked under node_modules/<name>, with scoped
+			// names one level deeper
+			manifests, _ := filepath.Glob(filepath.Join(entry, "node_modules", "*", "package.json"))
+			scoped, _ := filepath.Glob(filepath.Join(entry, "node_modules", "@*", "*", "package.json"))
+			for _, manifest := range append(manifests, scoped...) {
+				name, version, ok := readNameVersion(manifest)
+				if !ok {
+					continue
+				}
+
+				comp := scanners.Component{
+					Type:           "library",
+					Name:           name,
+					Version:        version,
+					PackageManager: "yarn",
+					Location:       entry,
+					Properties:     make(map[string]string),
+				}
+				events.record(comp, ActionCached, info.ModTime(), "yarn_cache", "")
+				break
+			}
+		}
+	}
+
+	return events.list(), nil
+}
+
+// yarnCacheDirs returns the yarn cache directories that exist for each user
+func yarnCacheDirs(cfg *config.Config) []string {
+	var candidates []string
+	if cacheFolder := os.Getenv("YARN_CACHE_FOLDER"); cacheFolder != "" {
+		candidates = append(candidates, cacheFolder)
+	}
+	for _, home := range system.HomeDirs(cfg) {
+		switch runtime.GOOS {
+		case "windows":
+			candidates = append(candidates, filepath.Join(home, "AppData", "Local", "Yarn", "Cache"))
+		case "darwin":
+			candidates = append(candidates, filepath.Join(home, "Library", "Caches", "Yarn"))
+		default:
+			candidates = append(candidates, filepath.Join(home, ".cache", "yarn"))
+		}
+	}
+
+	var dirs []string
+	for _, dir := range candidates {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
@@ -1,6 +1,7 @@
 package ides
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -8,10 +9,11 @@ import (
 	"runtime"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/license"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
 )
 
-// CursorScanner scans for Cursor IDE extensions and MCP servers
+// CursorScanner scans for Cursor IDE extensions
 type CursorScanner struct{}
 
 func (s *CursorScanner) Name() string {
@@ -19,6 +21,12 @@ func (s *CursorScanner) Name() string {
 }
 
 func (s *CursorScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext scans the Cursor extension directories, stopping early if ctx is
+// cancelled
+func (s *CursorScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("cursor") {
 		return nil, nil
 	}
@@ -50,11 +58,14 @@ func (s *CursorScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 	}
 
 	for _, extDir := range extensionDirs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if cfg.IsPathExcluded(extDir) {
 			continue
 		}
 
-		exts, err := scanCursorExtensions(extDir, cfg)
+		exts, err := scanCursorExtensions(ctx, extDir, cfg)
 		if err != nil {
 			if cfg.Debug {
 				fmt.Printf("Error scanning Cursor extensions in %s: %v\n", extDir, err)
@@ -64,14 +75,10 @@ func (s *CursorScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 		components = append(components, exts...)
 	}
 
-	// Scan for MCP servers in Cursor
-	mcpServers := scanCursorMCPServers(cfg)
-	components = append(components, mcpServers...)
-
 	return components, nil
 }
 
-func scanCursorExtensions(extensionDir string, cfg *config.Config) ([]scanners.Component, error) {
+func scanCursorExtensions(ctx context.Context, extensionDir string, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	entries, err := os.ReadDir(extensionDir)
@@ -80,6 +87,9 @@ func scanCursorExtensions(extensionDir string, cfg *config.Config) ([]scanners.C
 	}
 
 	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if !entry.IsDir() {
 			continue
 		}
@@ -102,103 +112,41 @@ func scanCursorExtensions(extensionDir string, cfg *config.Config) ([]scanners.C
 			Version:     pkgInfo.Version,
 			Description: pkgInfo.Description,
 			Location:    filepath.Join(extensionDir, entry.Name()),
+			License:     license.FromPackageJSON(pkgInfo.License, pkgInfo.Licenses),
+			Supplier:    pkgInfo.Publisher,
+			Publisher:   pkgInfo.Publisher,
 			Properties: map[string]string{
-				"ide":       "cursor",
-				"publisher": pkgInfo.Publisher,
+				"ide": "cursor",
 			},
 		}
+		pkgInfo.ApplyTo(&comp)
+		// Cursor installs extensions from Open VSX rather than the VS Code Marketplace
+		if pkgInfo.Publisher != "" && pkgInfo.Name != "" {
+			comp.AddExternalRef(scanners.RefDistribution, "https://open-vsx.org/extension/" + pkgInfo.Publisher + "/" + pkgInfo.Name)
+		}
 
 		if pkgInfo.DisplayName != "" {
 			comp.Properties["display_name"] = pkgInfo.DisplayName
 		}
 
+		comp.Identity = &scanners.Identity{Technique: "manifest-analysis", Confidence: 1, Value: packagePath}
+		hashExtension(&comp, comp.Location, cfg)
+
 		components = append(components, comp)
 	}
 
 	return components, nil
 }
 
-func scanCursorMCPServers(cfg *config.Config) []scanners.Component {
-	var components []scanners.Component
-
-	// Cursor MCP settings location
-	var mcpConfigPaths []string
-
-	switch runtime.GOOS {
-	case "darwin":
-		home, _ := os.UserHomeDir()
-		mcpConfigPaths = append(mcpConfigPaths,
-			filepath.Join(home, ".cursor", "mcp.json"),
-			filepath.Join(home, "Library", "Application Support", "Cursor", "User", "globalStorage", "mcp.json"),
-		)
-	case "windows":
-		home, _ := os.UserHomeDir()
-		mcpConfigPaths = append(mcpConfigPaths,
-			filepath.Join(home, ".cursor", "mcp.json"),
-			filepath.Join(home, "AppData", "Roaming", "Cursor", "User", "globalStorage", "mcp.json"),
-		)
-	case "linux":
-		home, _ := os.UserHomeDir()
-		mcpConfigPaths = append(mcpConfigPaths,
-			filepath.Join(home, ".cursor", "mcp.json"),
-			filepath.Join(home, ".config", "Cursor", "User", "globalStorage", "mcp.json"),
-		)
-	}
-
-	for _, configPath := range mcpConfigPaths {
-		if cfg.IsPathExcluded(configPath) {
-			continue
-		}
-
-		data, err := os.ReadFile(configPath)
-		if err != nil {
-			continue
-		}
-
-		var mcpConfig map[string]interface{}
-		if err := json.Unmarshal(data, &mcpConfig); err != nil {
-			continue
-		}
-
-		// Parse MCP server configurations
-		if servers, ok := mcpConfig["mcpServers"].(map[string]interface{}); ok {
-			for serverName, serverConfig := range servers {
-				comp := scanners.Component{
-					Type:     "mcp-server",
-					Name:     serverName,
-					Location: configPath,
-					Properties: map[string]string{
-						"ide": "cursor",
-					},
-				}
-
-				// Extract server details without secrets
-				if configMap, ok := serverConfig.(map[string]interface{}); ok {
-					if command, ok := configMap["command"].(string); ok {
-						comp.Properties["command"] = command
-					}
-					if args, ok := configMap["args"].([]interface{}); ok {
-						comp.Properties["args_count"] = fmt.Sprintf("%d", len(args))
-					}
-					if env, ok := configMap["env"].(map[string]interface{}); ok {
-						// Count env vars but don't expose values
-						comp.Properties["env_vars_count"] = fmt.Sprintf("%d", len(env))
-					}
-				}
-
-				components = append(components, comp)
-			}
-		}
-	}
-
-	return components
-}
-
 type cursorPackageJSON struct {
-	Name        string `json:"name"`
-	DisplayName string `json:"displayName"`
-	Version     string `json:"version"`
-	Publisher   string `json:"publisher"`
-	Description string `json:"description"`
+	Name        string          `json:"name"`
+	DisplayName string          `json:"displayName"`
+	Version     string          `json:"version"`
+	Publisher   string          `json:"publisher"`
+	Description string          `json:"description"`
+	License     json.RawMessage `json:"license"`
+	Licenses    json.RawMessage `json:"licenses"`
+
+	scanners.PackageJSONMetadata
 }
 
@@ -1,8 +1,10 @@
 package ides
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -20,6 +22,12 @@ func (s *JetBrainsScanner) Name() string {
 }
 
 func (s *JetBrainsScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext scans the plugin directories of every JetBrains IDE, stopping
+// early if ctx is cancelled
+func (s *JetBrainsScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("jetbrains") {
 		return nil, nil
 	}
@@ -30,11 +38,14 @@ func (s *JetBrainsScanner) Scan(cfg *config.Config) ([]scanners.Component, error
 	configDirs := getJetBrainsConfigDirs()
 
 	for _, configDir := range configDirs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if cfg.IsPathExcluded(configDir) {
 			continue
 		}
 
-		plugins, err := scanJetBrainsPlugins(configDir, cfg)
+		plugins, err := scanJetBrainsPlugins(ctx, configDir, cfg)
 		if err != nil {
 			if cfg.Debug {
 				fmt.Printf("Error scanning JetBrains plugins in %s: %v\n", configDir, err)
@@ -105,7 +116,7 @@ func getJetBrainsConfigDirs() []string {
 	return dirs
 }
 
-func scanJetBrainsPlugins(configDir string, cfg *config.Config) ([]scanners.Component, error) {
+func scanJetBrainsPlugins(ctx context.Context, configDir string, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	// Plugins are typically in config/plugins directory
@@ -120,6 +131,9 @@ func scanJetBrainsPlugins(configDir string, cfg *config.Config) ([]scanners.Comp
 	ideName := extractIDEName(configDir)
 
 	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if !entry.IsDir() {
 			continue
 		}
@@ -143,6 +157,8 @@ func scanJetBrainsPlugins(configDir string, cfg *config.Config) ([]scanners.Comp
 			Version:     pluginInfo.Version,
 			Description: pluginInfo.Description,
 			Location:    filepath.Join(pluginsDir, entry.Name()),
+			Supplier:    strings.TrimSpace(pluginInfo.Vendor),
+			Publisher:   strings.TrimSpace(pluginInfo.Vendor),
 			Properties: map[string]string{
 				"ide":       "jetbrains",
 				"ide_name":  ideName,
@@ -150,8 +166,20 @@ func scanJetBrainsPlugins(configDir string, cfg *config.Config) ([]scanners.Comp
 			},
 		}
 
-		if pluginInfo.Vendor != "" {
-			comp.Properties["vendor"] = pluginInfo.Vendor
+		comp.AddExternalRef(scanners.RefWebsite, pluginInfo.URL)
+		if pluginInfo.ID != "" {
+			comp.AddExternalRef(scanners.RefDistribution, "https://plugins.jetbrains.com/plugin/index?xmlId="+url.QueryEscape(pluginInfo.ID))
+		}
+
+		comp.Identity = &scanners.Identity{Technique: "manifest-analysis", Confidence: 1, Value: pluginXMLPath}
+
+		// A plugin's main jar is conventionally named after its directory; hash
+		// that jar so the digest matches published IOCs, else the whole plugin
+		mainJar := filepath.Join(comp.Location, "lib", entry.Name()+".jar")
+		if _, err := os.Stat(mainJar); err == nil {
+			hashExtension(&comp, mainJar, cfg)
+		} else {
+			hashExtension(&comp, comp.Location, cfg)
 		}
 
 		components = append(components, comp)
@@ -175,6 +203,7 @@ func extractIDEName(configDir string) string {
 
 type jetBrainsPluginXML struct {
 	XMLName     xml.Name `xml:"idea-plugin"`
+	URL         string   `xml:"url,attr"`
 	ID          string   `xml:"id"`
 	Name        string   `xml:"name"`
 	Version     string   `xml:"version"`
@@ -1,6 +1,7 @@
 package ides
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -19,6 +20,12 @@ func (s *SublimeScanner) Name() string {
 }
 
 func (s *SublimeScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext scans the Sublime Text package directories, stopping early if ctx
+// is cancelled
+func (s *SublimeScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("sublime") {
 		return nil, nil
 	}
@@ -48,11 +55,14 @@ func (s *SublimeScanner) Scan(cfg *config.Config) ([]scanners.Component, error)
 	}
 
 	for _, pkgDir := range packageDirs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if cfg.IsPathExcluded(pkgDir) {
 			continue
 		}
 
-		pkgs, err := scanSublimePackages(pkgDir, cfg)
+		pkgs, err := scanSublimePackages(ctx, pkgDir, cfg)
 		if err != nil {
 			if cfg.Debug {
 				fmt.Printf("Error scanning Sublime packages in %s: %v\n", pkgDir, err)
@@ -65,7 +75,7 @@ func (s *SublimeScanner) Scan(cfg *config.Config) ([]scanners.Component, error)
 	return components, nil
 }
 
-func scanSublimePackages(packageDir string, cfg *config.Config) ([]scanners.Component, error) {
+func scanSublimePackages(ctx context.Context, packageDir string, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	entries, err := os.ReadDir(packageDir)
@@ -74,6 +84,9 @@ func scanSublimePackages(packageDir string, cfg *config.Config) ([]scanners.Comp
 	}
 
 	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if !entry.IsDir() {
 			continue
 		}
@@ -100,15 +113,12 @@ func scanSublimePackages(packageDir string, cfg *config.Config) ([]scanners.Comp
 			Version:     pkgInfo.Version,
 			Description: pkgInfo.Description,
 			Location:    packagePath,
+			Author:      pkgInfo.Author,
 			Properties: map[string]string{
 				"ide": "sublime",
 			},
 		}
 
-		if pkgInfo.Author != "" {
-			comp.Properties["author"] = pkgInfo.Author
-		}
-
 		components = append(components, comp)
 	}
 
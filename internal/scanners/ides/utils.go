@@ -1,10 +1,15 @@
 package ides
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/hashing"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
 )
 
 // getUserProfiles returns user home directories
@@ -69,3 +74,30 @@ func getLinuxUserProfiles() ([]string, error) {
 	return profiles, nil
 }
 
+// hashExtension records the SHA-256 of an extension file, or the directory digest
+// of an unpacked extension (see hashing.Budget.Directory)
+func hashExtension(comp *scanners.Component, path string, cfg *config.Config) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+
+	budget := hashing.For(cfg)
+	property := "hashed_file"
+	var digest string
+	if info.IsDir() {
+		property = "hashed_directory"
+		digest, err = budget.Directory(path)
+	} else {
+		digest, err = budget.File(path)
+	}
+	if err != nil {
+		if cfg.Debug && !errors.Is(err, hashing.ErrDisabled) {
+			fmt.Printf("Could not hash %s: %v\n", path, err)
+		}
+		return
+	}
+
+	comp.Hashes = map[string]string{hashing.SHA256: digest}
+	comp.Properties[property] = path
+}
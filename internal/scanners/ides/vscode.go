@@ -1,6 +1,7 @@
 package ides
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -8,6 +9,7 @@ import (
 	"runtime"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/license"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
 )
 
@@ -19,6 +21,12 @@ func (s *VSCodeScanner) Name() string {
 }
 
 func (s *VSCodeScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext scans the VSCode extension directories, stopping early if ctx is
+// cancelled
+func (s *VSCodeScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("vscode") {
 		return nil, nil
 	}
@@ -50,11 +58,14 @@ func (s *VSCodeScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 	}
 
 	for _, extDir := range extensionDirs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if cfg.IsPathExcluded(extDir) {
 			continue
 		}
 
-		exts, err := scanVSCodeExtensions(extDir, cfg)
+		exts, err := scanVSCodeExtensions(ctx, extDir, cfg)
 		if err != nil {
 			if cfg.Debug {
 				fmt.Printf("Error scanning VSCode extensions in %s: %v\n", extDir, err)
@@ -64,14 +75,10 @@ func (s *VSCodeScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 		components = append(components, exts...)
 	}
 
-	// Also scan for MCP servers in VSCode settings
-	mcpServers := scanVSCodeMCPServers(cfg)
-	components = append(components, mcpServers...)
-
 	return components, nil
 }
 
-func scanVSCodeExtensions(extensionDir string, cfg *config.Config) ([]scanners.Component, error) {
+func scanVSCodeExtensions(ctx context.Context, extensionDir string, cfg *config.Config) ([]scanners.Component, error) {
 	var components []scanners.Component
 
 	entries, err := os.ReadDir(extensionDir)
@@ -80,6 +87,9 @@ func scanVSCodeExtensions(extensionDir string, cfg *config.Config) ([]scanners.C
 	}
 
 	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if !entry.IsDir() {
 			continue
 		}
@@ -103,97 +113,40 @@ func scanVSCodeExtensions(extensionDir string, cfg *config.Config) ([]scanners.C
 			Version:     pkgInfo.Version,
 			Description: pkgInfo.Description,
 			Location:    filepath.Join(extensionDir, entry.Name()),
+			License:     license.FromPackageJSON(pkgInfo.License, pkgInfo.Licenses),
+			Supplier:    pkgInfo.Publisher,
+			Publisher:   pkgInfo.Publisher,
 			Properties: map[string]string{
-				"ide":       "vscode",
-				"publisher": pkgInfo.Publisher,
+				"ide": "vscode",
 			},
 		}
+		pkgInfo.ApplyTo(&comp)
+		if pkgInfo.Publisher != "" && pkgInfo.Name != "" {
+			comp.AddExternalRef(scanners.RefDistribution, "https://marketplace.visualstudio.com/items?itemName=" + pkgInfo.Publisher + "." + pkgInfo.Name)
+		}
 
 		if pkgInfo.DisplayName != "" {
 			comp.Properties["display_name"] = pkgInfo.DisplayName
 		}
 
+		comp.Identity = &scanners.Identity{Technique: "manifest-analysis", Confidence: 1, Value: packagePath}
+		hashExtension(&comp, comp.Location, cfg)
+
 		components = append(components, comp)
 	}
 
 	return components, nil
 }
 
-func scanVSCodeMCPServers(cfg *config.Config) []scanners.Component {
-	var components []scanners.Component
-
-	// VSCode MCP settings are typically in settings.json
-	var settingsPaths []string
-
-	switch runtime.GOOS {
-	case "darwin":
-		home, _ := os.UserHomeDir()
-		settingsPaths = append(settingsPaths,
-			filepath.Join(home, "Library", "Application Support", "Code", "User", "settings.json"),
-		)
-	case "windows":
-		home, _ := os.UserHomeDir()
-		settingsPaths = append(settingsPaths,
-			filepath.Join(home, "AppData", "Roaming", "Code", "User", "settings.json"),
-		)
-	case "linux":
-		home, _ := os.UserHomeDir()
-		settingsPaths = append(settingsPaths,
-			filepath.Join(home, ".config", "Code", "User", "settings.json"),
-		)
-	}
-
-	for _, settingsPath := range settingsPaths {
-		if cfg.IsPathExcluded(settingsPath) {
-			continue
-		}
-
-		data, err := os.ReadFile(settingsPath)
-		if err != nil {
-			continue
-		}
-
-		var settings map[string]interface{}
-		if err := json.Unmarshal(data, &settings); err != nil {
-			continue
-		}
-
-		// Look for MCP server configurations
-		// This is a placeholder - actual MCP config structure may vary
-		if mcpConfig, ok := settings["mcp.servers"].(map[string]interface{}); ok {
-			for serverName, serverConfig := range mcpConfig {
-				comp := scanners.Component{
-					Type:     "mcp-server",
-					Name:     serverName,
-					Location: settingsPath,
-					Properties: map[string]string{
-						"ide": "vscode",
-					},
-				}
-
-				// Extract server details without secrets
-				if configMap, ok := serverConfig.(map[string]interface{}); ok {
-					if command, ok := configMap["command"].(string); ok {
-						comp.Properties["command"] = command
-					}
-					if args, ok := configMap["args"].([]interface{}); ok {
-						comp.Properties["args_count"] = fmt.Sprintf("%d", len(args))
-					}
-				}
-
-				components = append(components, comp)
-			}
-		}
-	}
-
-	return components
-}
-
 type vscodePackageJSON struct {
-	Name        string `json:"name"`
-	DisplayName string `json:"displayName"`
-	Version     string `json:"version"`
-	Publisher   string `json:"publisher"`
-	Description string `json:"description"`
+	Name        string          `json:"name"`
+	DisplayName string          `json:"displayName"`
+	Version     string          `json:"version"`
+	Publisher   string          `json:"publisher"`
+	Description string          `json:"description"`
+	License     json.RawMessage `json:"license"`
+	Licenses    json.RawMessage `json:"licenses"`
+
+	scanners.PackageJSONMetadata
 }
 
@@ -0,0 +1,193 @@
+package mcp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFile is an MCP client configuration file to read
+type configFile struct {
+	ide     string // Client that reads the file, e.g. "claude-desktop"
+	editor  string // Editor hosting the client, for extensions such as Cline
+	path    string
+	project string   // Project directory for project-level configs
+	homes   []string // Home directories whose package caches the servers use
+	parse   func(data []byte) ([]serverConfig, error)
+}
+
+// clineSettings is where the Cline extension keeps its MCP servers inside an
+// editor's globalStorage
+var clineSettings = filepath.Join("globalStorage", "saoudrizwan.claude-dev", "settings", "cline_mcp_settings.json")
+
+// userConfigs returns the MCP configuration files of one user
+func userConfigs(home string) []configFile {
+	configs := []configFile{
+		{ide: "claude-desktop", path: filepath.Join(appConfigDir(home, "Claude"), "claude_desktop_config.json"), parse: serversKey("mcpServers")},
+		{ide: "cursor", path: filepath.Join(home, ".cursor", "mcp.json"), parse: serversKey("mcpServers")},
+		{ide: "cursor", path: filepath.Join(appConfigDir(home, "Cursor"), "User", "globalStorage", "mcp.json"), parse: serversKey("mcpServers")},
+		{ide: "vscode", path: filepath.Join(appConfigDir(home, "Code"), "User", "settings.json"), parse: parseVSCodeSettings},
+		{ide: "vscode", path: filepath.Join(appConfigDir(home, "Code"), "User", "mcp.json"), parse: serversKey("servers")},
+		{ide: "windsurf", path: filepath.Join(home, ".codeium", "windsurf", "mcp_config.json"), parse: serversKey("mcpServers")},
+		{ide: "zed", path: zedSettingsPath(home), parse: parseZedSettings},
+		{ide: "continue", path: filepath.Join(home, ".continue", "config.json"), parse: parseContinueJSON},
+		{ide: "continue", path: filepath.Join(home, ".continue", "config.yaml"), parse: parseContinueYAML},
+	}
+
+	// Continue also loads one block per file from ~/.continue/mcpServers, in
+	// its own YAML format or copied from another client's JSON config
+	blocks, _ := filepath.Glob(filepath.Join(home, ".continue", "mcpServers", "*"))
+	for _, block := range blocks {
+		switch strings.ToLower(filepath.Ext(block)) {
+		case ".yaml", ".yml":
+			configs = append(configs, configFile{ide: "continue", path: block, parse: parseContinueYAML})
+		case ".json":
+			configs = append(configs, configFile{ide: "continue", path: block, parse: serversKey("mcpServers")})
+		}
+	}
+
+	// Cline runs inside VS Code and its forks
+	for _, editor := range []struct{ name, dir string }{
+		{"vscode", "Code"},
+		{"cursor", "Cursor"},
+		{"windsurf", "Windsurf"},
+	} {
+		configs = append(configs, configFile{
+			ide:    "cline",
+			editor: editor.name,
+			path:   filepath.Join(appConfigDir(home, editor.dir), "User", clineSettings),
+			parse:  serversKey("mcpServers"),
+		})
+	}
+
+	return configs
+}
+
+// projectConfigs returns the MCP configuration files a project directory may hold
+func projectConfigs(project string) []configFile {
+	return []configFile{
+		{ide: "vscode", path: filepath.Join(project, ".vscode", "mcp.json"), project: project, parse: serversKey("servers")},
+		{ide: "cursor", path: filepath.Join(project, ".cursor", "mcp.json"), project: project, parse: serversKey("mcpServers")},
+	}
+}
+
+// appConfigDir returns an application's per-user configuration directory
+func appConfigDir(home, app string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", app)
+	case "windows":
+		return filepath.Join(home, "AppData", "Roaming", app)
+	default:
+		return filepath.Join(home, ".config", app)
+	}
+}
+
+// zedSettingsPath returns the location of Zed's settings file
+func zedSettingsPath(home string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "AppData", "Roaming", "Zed", "settings.json")
+	}
+	return filepath.Join(home, ".config", "zed", "settings.json")
+}
+
+// serversKey returns a parser for JSON(C) files holding servers keyed by name
+// under key
+func serversKey(key string) func(data []byte) ([]serverConfig, error) {
+	return func(data []byte) ([]serverConfig, error) {
+		var doc map[string]interface{}
+		if err := unmarshalJSONC(data, &doc); err != nil {
+			return nil, err
+		}
+		servers, _ := doc[key].(map[string]interface{})
+		return parseServerMap(servers), nil
+	}
+}
+
+// parseVSCodeSettings reads the servers in VS Code's settings.json: the
+// "mcp": {"servers": {...}} object, or the older flat "mcp.servers" key
+func parseVSCodeSettings(data []byte) ([]serverConfig, error) {
+	var settings map[string]interface{}
+	if err := unmarshalJSONC(data, &settings); err != nil {
+		return nil, err
+	}
+
+	servers, _ := settings["mcp.servers"].(map[string]interface{})
+	if mcp, ok := settings["mcp"].(map[string]interface{}); ok {
+		if nested, ok := mcp["servers"].(map[string]interface{}); ok {
+			servers = nested
+		}
+	}
+	return parseServerMap(servers), nil
+}
+
+// parseZedSettings reads Zed's "context_servers". Servers provided by a Zed
+// extension have no command of their own and are reported by name.
+func parseZedSettings(data []byte) ([]serverConfig, error) {
+	var settings struct {
+		ContextServers map[string]interface{} `json:"context_servers"`
+	}
+	if err := unmarshalJSONC(data, &settings); err != nil {
+		return nil, err
+	}
+	return parseServerMap(settings.ContextServers), nil
+}
+
+// parseContinueJSON reads the servers in Continue's deprecated config.json,
+// listed under experimental.modelContextProtocolServers without names
+func parseContinueJSON(data []byte) ([]serverConfig, error) {
+	var config struct {
+		Experimental struct {
+			Servers []map[string]interface{} `json:"modelContextProtocolServers"`
+		} `json:"experimental"`
+	}
+	if err := unmarshalJSONC(data, &config); err != nil {
+		return nil, err
+	}
+
+	var servers []serverConfig
+	for i, entry := range config.Experimental.Servers {
+		server := parseServer(entry)
+		server.Name = stringValue(entry["name"])
+		if server.Name == "" {
+			server.Name = fmt.Sprintf("server-%d", i+1)
+		}
+		servers = append(servers, server)
+	}
+	return servers, nil
+}
+
+// parseContinueYAML reads the "mcpServers" list of a Continue config.yaml or
+// block file
+func parseContinueYAML(data []byte) ([]serverConfig, error) {
+	var config struct {
+		MCPServers []map[string]interface{} `yaml:"mcpServers"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	var servers []serverConfig
+	for i, entry := range config.MCPServers {
+		server := parseServer(entry)
+		server.Name = stringValue(entry["name"])
+		if server.Name == "" {
+			server.Name = fmt.Sprintf("server-%d", i+1)
+		}
+		servers = append(servers, server)
+	}
+	return servers, nil
+}
+
+// readConfig reads and parses a configuration file
+func readConfig(config configFile) ([]serverConfig, error) {
+	data, err := os.ReadFile(config.path)
+	if err != nil {
+		return nil, err
+	}
+	return config.parse(data)
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"encoding/json"
+)
+
+// unmarshalJSONC decodes JSON with comments and trailing commas, as written by
+// VS Code, Zed and other editors in their settings files
+func unmarshalJSONC(data []byte, v interface{}) error {
+	return json.Unmarshal(stripJSONC(data), v)
+}
+
+// stripJSONC removes // and /* */ comments and trailing commas outside strings
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		case c == ',':
+			// Drop a trailing comma before a closing bracket
+			if closesAfter(data, i+1) {
+				continue
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// closesAfter reports whether the next token at or after i is a closing
+// bracket, skipping whitespace and comments
+func closesAfter(data []byte, i int) bool {
+	for i < len(data) {
+		switch c := data[i]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i += 2
+		default:
+			return c == '}' || c == ']'
+		}
+	}
+	return false
+}
@@ -0,0 +1,415 @@
+package mcp
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// exactVersion matches a pinned version rather than a range or dist-tag
+var exactVersion = regexp.MustCompile(`^v?\d+(\.\d+)*([-+.][0-9A-Za-z.+-]+)?$`)
+
+// launchFlags lists, per launcher, the options that take a value, so the value
+// is not mistaken for the package. Options not listed are treated as switches.
+var launchFlags = map[string]map[string]bool{
+	"npx": {
+		"-p": true, "--package": true, "--registry": true, "-c": true, "--call": true,
+		"--cache": true, "--userconfig": true, "-w": true, "--workspace": true,
+	},
+	"uvx": {
+		"--from": true, "--with": true, "--with-editable": true, "--with-requirements": true,
+		"-p": true, "--python": true, "--index": true, "--index-url": true, "-i": true,
+		"--default-index": true, "--extra-index-url": true, "--cache-dir": true, "--directory": true,
+	},
+	"docker": {
+		"-e": true, "--env": true, "--env-file": true, "-v": true, "--volume": true,
+		"--mount": true, "--name": true, "--network": true, "--net": true, "-p": true,
+		"--publish": true, "-w": true, "--workdir": true, "-u": true, "--user": true,
+		"--entrypoint": true, "--platform": true, "-l": true, "--label": true,
+		"-h": true, "--hostname": true, "--add-host": true, "--cpus": true,
+		"-m": true, "--memory": true, "--pull": true, "--cap-add": true,
+		"--cap-drop": true, "--security-opt": true, "--tmpfs": true, "--device": true,
+		"--dns": true, "--ipc": true, "--pid": true, "--runtime": true, "--gpus": true,
+		"--shm-size": true, "--ulimit": true, "--log-driver": true, "--log-opt": true,
+		"--restart": true, "--stop-signal": true, "--init-path": true, "--cidfile": true,
+	},
+}
+
+// launch is what a server's command line runs
+type launch struct {
+	launcher string // npx, uvx, docker, node, ...
+	manager  string // package manager of the package: npm, pip or docker
+	spec     string // package as given on the command line
+	registry string // registry or index set on the command line
+	script   string // script path run by node, bun or deno
+}
+
+// resolvePackage returns the package a stdio server's command line runs, or
+// false when it runs something that is not a package (a shell script, a local
+// binary). Argument values other than the package are never exported.
+// homes are searched for the npx and uv caches to find the version pulled.
+func resolvePackage(server serverConfig, homes []string) (scanners.Component, bool) {
+	l, ok := parseLaunch(server.Command, server.Args)
+	if !ok {
+		return scanners.Component{}, false
+	}
+
+	switch l.manager {
+	case "npm":
+		if l.script != "" {
+			return scriptPackage(l)
+		}
+		return npmPackage(l, homes)
+	case "pip":
+		return pythonPackage(l, homes)
+	case "docker":
+		return imagePackage(l)
+	}
+	return scanners.Component{}, false
+}
+
+// parseLaunch works out the launcher and the package from a command line
+func parseLaunch(command string, args []string) (launch, bool) {
+	name := strings.ToLower(filepath.Base(command))
+	for _, ext := range []string{".cmd", ".exe", ".bat", ".ps1"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	// Multi-word launchers: "npm exec", "pnpm dlx", "uv tool run", "docker run", ...
+	switch {
+	case name == "npx" || name == "bunx":
+		return parseLauncherArgs(launch{launcher: name, manager: "npm"}, "npx", args)
+	case (name == "npm" && len(args) > 0 && (args[0] == "exec" || args[0] == "x")) ||
+		((name == "pnpm" || name == "yarn") && len(args) > 0 && args[0] == "dlx"):
+		return parseLauncherArgs(launch{launcher: name + " " + args[0], manager: "npm"}, "npx", args[1:])
+	case name == "uvx" || name == "pipx":
+		if name == "pipx" {
+			if len(args) == 0 || args[0] != "run" {
+				return launch{}, false
+			}
+			args = args[1:]
+		}
+		return parseLauncherArgs(launch{launcher: name, manager: "pip"}, "uvx", args)
+	case name == "uv" && len(args) > 1 && args[0] == "tool" && args[1] == "run":
+		return parseLauncherArgs(launch{launcher: "uv tool run", manager: "pip"}, "uvx", args[2:])
+	case name == "docker" || name == "podman" || name == "nerdctl":
+		if len(args) > 1 && args[0] == "container" {
+			args = args[1:]
+		}
+		if len(args) == 0 || args[0] != "run" {
+			return launch{}, false
+		}
+		return parseLauncherArgs(launch{launcher: name + " run", manager: "docker"}, "docker", args[1:])
+	case name == "node" || name == "bun" || name == "deno":
+		for _, arg := range args {
+			if arg == "run" && name != "node" {
+				continue
+			}
+			if strings.HasPrefix(arg, "-") {
+				continue
+			}
+			// A relative script depends on the client's working directory
+			switch strings.ToLower(filepath.Ext(arg)) {
+			case ".js", ".mjs", ".cjs", ".ts":
+				if filepath.IsAbs(arg) {
+					return launch{launcher: name, manager: "npm", script: arg}, true
+				}
+			}
+			return launch{}, false
+		}
+	}
+	return launch{}, false
+}
+
+// parseLauncherArgs finds the package among a launcher's arguments: the value
+// of --package/--from, or else the first positional argument
+func parseLauncherArgs(l launch, flagSet string, args []string) (launch, bool) {
+	valueFlags := launchFlags[flagSet]
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			if l.spec == "" && i+1 < len(args) {
+				l.spec = args[i+1]
+			}
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			if l.spec == "" {
+				l.spec = arg
+			}
+			break
+		}
+
+		flag, value, inline := strings.Cut(arg, "=")
+		if !inline && valueFlags[flag] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+
+		switch flag {
+		case "-c", "--call":
+			return launch{}, false // runs a shell command
+		case "-p", "--package", "--from":
+			if flagSet != "docker" && !(flagSet == "uvx" && flag == "-p") {
+				l.spec = value
+			}
+		case "--registry", "--index", "--index-url", "-i", "--default-index":
+			l.registry = stripURL(value)
+		}
+	}
+
+	if l.spec == "" || !isRegistrySpec(l.spec) {
+		return launch{}, false
+	}
+	return l, true
+}
+
+// isRegistrySpec reports whether a package spec names a registry package
+// rather than a URL, git repository, tarball or local path
+func isRegistrySpec(spec string) bool {
+	if strings.Contains(spec, "://") || strings.HasPrefix(spec, "git+") || strings.HasPrefix(spec, "github:") ||
+		strings.HasPrefix(spec, "file:") || strings.HasPrefix(spec, ".") || strings.HasPrefix(spec, "~") ||
+		filepath.IsAbs(spec) || strings.HasSuffix(spec, ".tgz") || strings.HasSuffix(spec, ".whl") {
+		return false
+	}
+	return true
+}
+
+// stripURL removes credentials, query string and fragment from a URL
+func stripURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	parsed.User = nil
+	parsed.RawQuery = ""
+	parsed.Fragment = ""
+	return parsed.String()
+}
+
+// newPackage builds the component for a launched package
+func newPackage(l launch, name, version string) scanners.Component {
+	comp := scanners.Component{
+		Type:           "library",
+		Name:           name,
+		Version:        version,
+		PackageManager: l.manager,
+		Properties: map[string]string{
+			"launcher": l.launcher,
+		},
+	}
+	if l.registry != "" {
+		comp.Properties["registry"] = l.registry
+	}
+	return comp
+}
+
+// npmPackage resolves "name", "name@1.2.3" or "@scope/name@^1" run by npx. A
+// version that is not pinned is looked up in the npx cache, in the directory
+// npx installed this exact spec into; a copy installed for another spec may not
+// satisfy it and is not used.
+func npmPackage(l launch, homes []string) (scanners.Component, bool) {
+	name, requested := l.spec, ""
+	if i := strings.LastIndex(l.spec, "@"); i > 0 {
+		name, requested = l.spec[:i], l.spec[i+1:]
+	}
+
+	comp := newPackage(l, name, "")
+	if exactVersion.MatchString(requested) {
+		comp.Version = strings.TrimPrefix(requested, "v")
+		comp.Properties["version_source"] = "config"
+		return comp, true
+	}
+	if requested != "" {
+		comp.Properties["requested_version"] = requested
+	}
+
+	var cacheDirs []string
+	for _, home := range homes {
+		cacheDirs = append(cacheDirs, filepath.Join(home, ".npm", "_npx"))
+		if runtime.GOOS == "windows" {
+			cacheDirs = append(cacheDirs, filepath.Join(home, "AppData", "Local", "npm-cache", "_npx"))
+		}
+	}
+	for _, cacheDir := range cacheDirs {
+		manifest := filepath.Join(cacheDir, npxCacheKey(l.spec), "node_modules", filepath.FromSlash(name), "package.json")
+		if _, version, ok := readPackageJSON(manifest); ok {
+			comp.Version = version
+			comp.Location = filepath.Dir(manifest)
+			comp.Properties["version_source"] = "npx-cache"
+			break
+		}
+	}
+	return comp, true
+}
+
+// npxCacheKey returns the name of the npx cache directory a spec is installed
+// into: the start of the SHA-512 of the specs npx was asked for, one per line
+func npxCacheKey(spec string) string {
+	sum := sha512.Sum512([]byte(spec))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// pythonPackage resolves "name", "name==1.2.3", "name@1.2.3" or
+// "name[extra]>=1" run by uvx or pipx. A version that is not pinned is looked
+// up in the uv cache, taking the newest cached version that meets the
+// requested specifier.
+func pythonPackage(l launch, homes []string) (scanners.Component, bool) {
+	spec := strings.TrimSpace(l.spec)
+	end := strings.IndexAny(spec, "[<>=!~@; ")
+	name := spec
+	if end > 0 {
+		name = spec[:end]
+	}
+	rest := strings.TrimSpace(spec[len(name):])
+	if i := strings.Index(rest, "]"); strings.HasPrefix(rest, "[") && i > 0 {
+		rest = strings.TrimSpace(rest[i+1:])
+	}
+
+	comp := newPackage(l, name, "")
+	requested := strings.TrimLeft(rest, "=@")
+	switch {
+	case (strings.HasPrefix(rest, "==") || strings.HasPrefix(rest, "@")) && exactVersion.MatchString(requested):
+		comp.Version = requested
+		comp.Properties["version_source"] = "config"
+		return comp, true
+	case rest != "":
+		comp.Properties["requested_version"] = rest
+	}
+
+	// uvx installs each tool into an environment under the cache's archive
+	// directory, with the dist-info name normalized to underscores
+	distName := strings.ToLower(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+	for _, home := range homes {
+		cacheDir := filepath.Join(home, ".cache", "uv")
+		if runtime.GOOS == "windows" {
+			cacheDir = filepath.Join(home, "AppData", "Local", "uv", "cache")
+		}
+		distInfos, _ := filepath.Glob(filepath.Join(cacheDir, "archive-v0", "*", "lib", "python*", "site-packages", distName+"-*.dist-info"))
+		windowsInfos, _ := filepath.Glob(filepath.Join(cacheDir, "archive-v0", "*", "Lib", "site-packages", distName+"-*.dist-info"))
+		var candidates []string
+		for _, distInfo := range append(distInfos, windowsInfos...) {
+			if rest == "" || satisfiesSpecifier(distInfoVersion(distInfo), rest) {
+				candidates = append(candidates, distInfo)
+			}
+		}
+		if distInfo, ok := newest(candidates); ok {
+			comp.Version = distInfoVersion(distInfo)
+			comp.Location = distInfo
+			comp.Properties["version_source"] = "uv-cache"
+			break
+		}
+	}
+	return comp, true
+}
+
+// distInfoVersion returns the version in a dist-info directory name, e.g.
+// "1.2.3" for mcp_server_fetch-1.2.3.dist-info
+func distInfoVersion(distInfo string) string {
+	_, version, _ := strings.Cut(strings.TrimSuffix(filepath.Base(distInfo), ".dist-info"), "-")
+	return version
+}
+
+// imagePackage resolves a container image reference such as
+// "ghcr.io/github/github-mcp-server:v1@sha256:..." or "mcp/fetch"
+func imagePackage(l launch) (scanners.Component, bool) {
+	ref := l.spec
+	digest := ""
+	if i := strings.Index(ref, "@"); i > 0 {
+		ref, digest = ref[:i], ref[i+1:]
+	}
+
+	tag := ""
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref, tag = ref[:i], ref[i+1:]
+	}
+
+	// The first segment is a registry when it looks like a host name
+	registry := "docker.io"
+	if first, rest, found := strings.Cut(ref, "/"); found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, ref = first, rest
+	}
+	if registry == "docker.io" {
+		ref = strings.TrimPrefix(ref, "library/")
+	}
+	if tag == "" && digest == "" {
+		tag = "latest"
+	}
+
+	comp := newPackage(l, ref, tag)
+	comp.Type = "container"
+	comp.Properties["registry"] = registry
+	if digest != "" {
+		comp.Properties["digest"] = digest
+	}
+	comp.Properties["version_source"] = "config"
+	return comp, true
+}
+
+// scriptPackage resolves a script run by node, bun or deno to the package
+// that contains it, from the nearest package.json above the script
+func scriptPackage(l launch) (scanners.Component, bool) {
+	dir := filepath.Dir(l.script)
+	for i := 0; i < 6; i++ {
+		manifest := filepath.Join(dir, "package.json")
+		if name, version, ok := readPackageJSON(manifest); ok {
+			comp := newPackage(l, name, version)
+			comp.Location = dir
+			comp.Properties["version_source"] = "package.json"
+			comp.Properties["install_path"] = dir
+			// Only a package installed from the registry has an npm purl
+			if !strings.Contains(filepath.ToSlash(dir), "/node_modules/") {
+				comp.PackageManager = ""
+			}
+			return comp, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return scanners.Component{}, false
+}
+
+// readPackageJSON reads the name and version of a package.json
+func readPackageJSON(path string) (name, version string, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", false
+	}
+	var pkg struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || pkg.Name == "" {
+		return "", "", false
+	}
+	return pkg.Name, pkg.Version, true
+}
+
+// newest returns the most recently modified of paths
+func newest(paths []string) (string, bool) {
+	var found string
+	var foundTime time.Time
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if found == "" || info.ModTime().After(foundTime) {
+			found, foundTime = path, info.ModTime()
+		}
+	}
+	return found, found != ""
+}
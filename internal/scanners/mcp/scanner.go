@@ -0,0 +1,167 @@
+// Package mcp finds the Model Context Protocol servers configured for AI
+// clients and editors: Claude Desktop, Cursor, VS Code, Windsurf, Zed,
+// Continue and Cline, in every user profile and in local projects.
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/discovery"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/system"
+)
+
+// MCPScanner scans MCP client configurations for configured servers
+type MCPScanner struct{}
+
+func (s *MCPScanner) Name() string {
+	return "mcp-servers"
+}
+
+func (s *MCPScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext reads the user-level configurations of every profile, then the
+// project-level .vscode/mcp.json and .cursor/mcp.json files found by project
+// discovery
+func (s *MCPScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
+	if cfg.IsScannerDisabled("mcp-servers") {
+		return nil, nil
+	}
+
+	homes := system.HomeDirs(cfg)
+	var configs []configFile
+	for _, home := range homes {
+		for _, configFile := range userConfigs(home) {
+			configFile.homes = []string{home}
+			configs = append(configs, configFile)
+		}
+	}
+
+	// A project may belong to any user, so its servers are looked up in every cache
+	if result, err := discovery.Discover(ctx, cfg); err == nil {
+		for _, project := range result.Projects {
+			if project.Has(".vscode") || project.Has(".cursor") {
+				for _, configFile := range projectConfigs(project.Path) {
+					configFile.homes = homes
+					configs = append(configs, configFile)
+				}
+			}
+		}
+	}
+
+	var components []scanners.Component
+	seen := make(map[string]bool)
+	for _, configFile := range configs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if seen[configFile.path] || cfg.IsPathExcluded(configFile.path) {
+			continue
+		}
+		seen[configFile.path] = true
+
+		servers, err := readConfig(configFile)
+		if err != nil {
+			if cfg.Debug && !os.IsNotExist(err) {
+				fmt.Printf("Error reading MCP config %s: %v\n", configFile.path, err)
+			}
+			continue
+		}
+
+		for _, server := range servers {
+			if server.remote() {
+				components = append(components, serviceComponent(configFile, server))
+			} else {
+				components = append(components, serverComponent(configFile, server))
+			}
+		}
+	}
+
+	return components, nil
+}
+
+// serverComponent builds the component for a configured server, recording how
+// it is launched without exporting argument, environment or header values. The
+// package its command runs becomes a dependency of the server.
+func serverComponent(configFile configFile, server serverConfig) scanners.Component {
+	comp := scanners.Component{
+		Type:       "mcp-server",
+		Name:       server.Name,
+		Location:   configFile.path,
+		Properties: serverProperties(configFile, server),
+	}
+
+	if server.Command != "" {
+		comp.Properties["command"] = server.Command
+	}
+	if len(server.Args) > 0 {
+		comp.Properties["args_count"] = fmt.Sprintf("%d", len(server.Args))
+	}
+	if len(server.Env) > 0 {
+		// Count env vars but don't expose values
+		comp.Properties["env_vars_count"] = fmt.Sprintf("%d", len(server.Env))
+	}
+
+	if pkg, ok := resolvePackage(server, configFile.homes); ok {
+		comp.Properties["launcher"] = pkg.Properties["launcher"]
+		comp.Dependencies = []scanners.Component{pkg}
+	}
+
+	return comp
+}
+
+// serviceComponent builds the service for a remote server the client connects
+// to over HTTP. The endpoint keeps no credentials or query string, and header
+// values are never exported: headers or OAuth settings only mark the service
+// as authenticated.
+func serviceComponent(configFile configFile, server serverConfig) scanners.Component {
+	comp := scanners.Component{
+		Type:        "service",
+		Name:        server.Name,
+		Description: "Remote Model Context Protocol (MCP) server",
+		Location:    configFile.path,
+		Properties:  serverProperties(configFile, server),
+	}
+
+	if endpoint := stripURL(server.URL); endpoint != "" {
+		comp.Endpoints = []string{endpoint}
+	}
+
+	authenticated := server.OAuth || len(server.Headers) > 0
+	comp.Properties["authenticated"] = fmt.Sprintf("%t", authenticated)
+	if len(server.Headers) > 0 {
+		comp.Properties["headers_count"] = fmt.Sprintf("%d", len(server.Headers))
+	}
+
+	return comp
+}
+
+// serverProperties returns the properties shared by launched and remote servers:
+// the client that references the server, where it is configured and its transport
+func serverProperties(configFile configFile, server serverConfig) map[string]string {
+	props := map[string]string{
+		"ide":          configFile.ide,
+		"config_scope": "user",
+	}
+
+	if transport := server.transport(); transport != "" {
+		props["transport"] = transport
+	}
+	if configFile.editor != "" {
+		props["editor"] = configFile.editor
+	}
+	if configFile.project != "" {
+		props["config_scope"] = "project"
+		props["project_path"] = configFile.project
+	}
+	if server.Disabled {
+		props["disabled"] = "true"
+	}
+
+	return props
+}
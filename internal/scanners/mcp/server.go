@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// serverConfig is one MCP server entry, normalized from the formats the
+// clients use. Env and header values are read so their presence can be
+// reported, never exported.
+type serverConfig struct {
+	Name     string
+	Command  string
+	Args     []string
+	Env      map[string]string
+	URL      string
+	Type     string // transport as configured: stdio, sse, http, streamable-http, ...
+	Headers  map[string]string
+	OAuth    bool // OAuth or other authentication settings are configured
+	Disabled bool
+}
+
+// remote reports whether the client connects to the server at a URL rather
+// than launching it
+func (s serverConfig) remote() bool {
+	return s.Command == "" && s.URL != ""
+}
+
+// transport returns the server's transport, inferring it when not configured.
+// It is empty for servers launched by an editor extension (Zed).
+func (s serverConfig) transport() string {
+	switch {
+	case s.Type != "":
+		return strings.ToLower(s.Type)
+	case s.Command != "":
+		return "stdio"
+	case s.URL != "":
+		return "http"
+	}
+	return ""
+}
+
+// parseServerMap reads servers keyed by name, the format of Claude Desktop,
+// Cursor, Windsurf, Cline and VS Code ("mcpServers" or "servers")
+func parseServerMap(servers map[string]interface{}) []serverConfig {
+	var configs []serverConfig
+	for name, raw := range servers {
+		entry, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		server := parseServer(entry)
+		server.Name = name
+		configs = append(configs, server)
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].Name < configs[j].Name
+	})
+	return configs
+}
+
+// parseServer reads the fields of one server entry
+func parseServer(entry map[string]interface{}) serverConfig {
+	server := serverConfig{
+		Command:  stringValue(entry["command"]),
+		Args:     stringList(entry["args"]),
+		Env:      stringMap(entry["env"]),
+		URL:      stringValue(entry["url"]),
+		Type:     stringValue(entry["type"]),
+		Headers:  stringMap(entry["headers"]),
+		OAuth:    entry["oauth"] != nil || entry["auth"] != nil,
+		Disabled: entry["disabled"] == true,
+	}
+
+	// Windsurf names the URL of a remote server serverUrl
+	if server.URL == "" {
+		server.URL = stringValue(entry["serverUrl"])
+	}
+	if server.Type == "" {
+		server.Type = stringValue(entry["transportType"])
+	}
+	if enabled, ok := entry["enabled"].(bool); ok && !enabled {
+		server.Disabled = true
+	}
+
+	// Continue sends headers from requestOptions
+	if len(server.Headers) == 0 {
+		if options, ok := entry["requestOptions"].(map[string]interface{}); ok {
+			server.Headers = stringMap(options["headers"])
+		}
+	}
+
+	// Zed and Continue nest the launch command: {"command": {"path": ..., "args": [...]}}
+	// or {"transport": {"type": "stdio", "command": ..., "args": [...]}}
+	for _, key := range []string{"command", "transport"} {
+		nested, ok := entry[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if server.Command == "" {
+			server.Command = stringValue(nested["path"])
+		}
+		if server.Command == "" {
+			server.Command = stringValue(nested["command"])
+		}
+		if len(server.Args) == 0 {
+			server.Args = stringList(nested["args"])
+		}
+		if len(server.Env) == 0 {
+			server.Env = stringMap(nested["env"])
+		}
+		if server.URL == "" {
+			server.URL = stringValue(nested["url"])
+		}
+		if server.Type == "" {
+			server.Type = stringValue(nested["type"])
+		}
+		if len(server.Headers) == 0 {
+			server.Headers = stringMap(nested["headers"])
+		}
+	}
+
+	// "type" and "transport" may also be plain strings
+	if server.Type == "" {
+		server.Type = stringValue(entry["transport"])
+	}
+
+	return server
+}
+
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func stringList(v interface{}) []string {
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		switch item := item.(type) {
+		case string:
+			list = append(list, item)
+		default:
+			list = append(list, fmt.Sprint(item))
+		}
+	}
+	return list
+}
+
+func stringMap(v interface{}) map[string]string {
+	entries, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	m := make(map[string]string, len(entries))
+	for key, value := range entries {
+		m[key] = fmt.Sprint(value)
+	}
+	return m
+}
@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// releaseVersion splits a version into its release segments and the rest,
+// e.g. "1.2.0rc1" into "1.2.0" and "rc1"
+var releaseVersion = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)(.*)$`)
+
+// satisfiesSpecifier reports whether version meets a Python version specifier
+// such as ">=1.2,<2", "~=1.4" or "==1.*". Specifiers it cannot evaluate are
+// not met, so a cached version is never taken for one it may not satisfy.
+func satisfiesSpecifier(version, specifier string) bool {
+	if !releaseVersion.MatchString(version) {
+		return false
+	}
+
+	// Environment markers ("; python_version >= '3.10'") do not constrain the version
+	specifier, _, _ = strings.Cut(specifier, ";")
+	for _, clause := range strings.Split(specifier, ",") {
+		clause = strings.TrimSpace(clause)
+		want := strings.TrimLeft(clause, "<>=!~")
+		op := clause[:len(clause)-len(want)]
+		want = strings.TrimSpace(want)
+		if want == "" {
+			return false
+		}
+
+		if prefix, wildcard := strings.CutSuffix(want, ".*"); wildcard {
+			switch op {
+			case "==":
+				if !hasReleasePrefix(version, prefix) {
+					return false
+				}
+			case "!=":
+				if hasReleasePrefix(version, prefix) {
+					return false
+				}
+			default:
+				return false
+			}
+			continue
+		}
+
+		if !exactVersion.MatchString(want) {
+			return false
+		}
+		cmp := compareVersions(version, want)
+		switch op {
+		case "==", "===":
+			if cmp != 0 {
+				return false
+			}
+		case "!=":
+			if cmp == 0 {
+				return false
+			}
+		case ">=":
+			if cmp < 0 {
+				return false
+			}
+		case "<=":
+			if cmp > 0 {
+				return false
+			}
+		case ">":
+			if cmp <= 0 {
+				return false
+			}
+		case "<":
+			if cmp >= 0 {
+				return false
+			}
+		case "~=":
+			// ~=1.4.2 means >=1.4.2 and ==1.4.*
+			release := releaseVersion.FindStringSubmatch(want)[1]
+			i := strings.LastIndex(release, ".")
+			if i < 0 || cmp < 0 || !hasReleasePrefix(version, release[:i]) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// hasReleasePrefix reports whether the release segments of version start with
+// those of prefix, e.g. "1.4.2" with "1.4"
+func hasReleasePrefix(version, prefix string) bool {
+	got := releaseSegments(version)
+	want := releaseSegments(prefix)
+	if len(want) == 0 || len(got) < len(want) {
+		return false
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersions orders two versions by their release segments, then puts
+// pre-releases (a, b, rc, dev) before the release and post-releases after it
+func compareVersions(a, b string) int {
+	aParts, bParts := releaseSegments(a), releaseSegments(b)
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var x, y int
+		if i < len(aParts) {
+			x = aParts[i]
+		}
+		if i < len(bParts) {
+			y = bParts[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	aSuffix, bSuffix := versionSuffix(a), versionSuffix(b)
+	aRank, bRank := suffixRank(aSuffix), suffixRank(bSuffix)
+	switch {
+	case aRank != bRank:
+		if aRank < bRank {
+			return -1
+		}
+		return 1
+	case aSuffix < bSuffix:
+		return -1
+	case aSuffix > bSuffix:
+		return 1
+	}
+	return 0
+}
+
+// releaseSegments returns the numeric release segments of a version
+func releaseSegments(version string) []int {
+	match := releaseVersion.FindStringSubmatch(version)
+	if match == nil {
+		return nil
+	}
+	var segments []int
+	for _, part := range strings.Split(match[1], ".") {
+		n, _ := strconv.Atoi(part)
+		segments = append(segments, n)
+	}
+	return segments
+}
+
+// versionSuffix returns what follows the release segments, without separators
+func versionSuffix(version string) string {
+	match := releaseVersion.FindStringSubmatch(version)
+	if match == nil {
+		return ""
+	}
+	return strings.TrimLeft(strings.ToLower(match[2]), ".-_+")
+}
+
+// suffixRank places a pre-release below the release and a post-release above
+func suffixRank(suffix string) int {
+	switch {
+	case suffix == "":
+		return 0
+	case strings.HasPrefix(suffix, "post"):
+		return 1
+	}
+	return -1
+}
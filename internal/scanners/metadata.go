@@ -0,0 +1,114 @@
+package scanners
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// External reference types used by the scanners
+const (
+	RefWebsite       = "website"
+	RefVCS           = "vcs"
+	RefDistribution  = "distribution"
+	RefIssueTracker  = "issue-tracker"
+	RefDocumentation = "documentation"
+)
+
+// AddExternalRef records a URL of the given type on the component. Empty values,
+// values that are not absolute URLs with a host and repeated (type, URL) pairs
+// are ignored.
+func (c *Component) AddExternalRef(refType, rawURL string) {
+	rawURL = strings.TrimSpace(rawURL)
+	if u, err := url.Parse(rawURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return
+	}
+	for _, ref := range c.ExternalRefs {
+		if ref.Type == refType && ref.URL == rawURL {
+			return
+		}
+	}
+	c.ExternalRefs = append(c.ExternalRefs, ExternalRef{Type: refType, URL: rawURL})
+}
+
+// PackageJSONMetadata holds the package.json fields that say who wrote a package
+// and where it lives. Scanners embed it in their package.json types; every
+// field is decoded leniently since hand-written manifests vary in shape.
+type PackageJSONMetadata struct {
+	Author     json.RawMessage `json:"author"`
+	Homepage   json.RawMessage `json:"homepage"`
+	Repository json.RawMessage `json:"repository"`
+	Bugs       json.RawMessage `json:"bugs"`
+}
+
+// ApplyTo sets the component's author and external references
+func (m PackageJSONMetadata) ApplyTo(comp *Component) {
+	if comp.Author == "" {
+		comp.Author = personName(jsonField(m.Author, "name"))
+	}
+	comp.AddExternalRef(RefWebsite, jsonString(m.Homepage))
+	comp.AddExternalRef(RefVCS, RepositoryURL(jsonField(m.Repository, "url")))
+	comp.AddExternalRef(RefIssueTracker, jsonField(m.Bugs, "url"))
+}
+
+// RepositoryURL turns the repository forms used by package manifests into a
+// browsable URL: "git+https://host/x.git" loses its "git+" prefix and
+// "github:user/repo" or bare "user/repo" shorthand expands to a GitHub URL
+func RepositoryURL(repository string) string {
+	repository = strings.TrimSpace(repository)
+	if repository == "" {
+		return ""
+	}
+
+	for prefix, host := range map[string]string{
+		"github:":    "https://github.com/",
+		"gitlab:":    "https://gitlab.com/",
+		"bitbucket:": "https://bitbucket.org/",
+	} {
+		if rest, found := strings.CutPrefix(repository, prefix); found {
+			return host + rest
+		}
+	}
+	if !strings.Contains(repository, ":") && strings.Count(repository, "/") == 1 {
+		return "https://github.com/" + repository
+	}
+
+	repository = strings.TrimPrefix(repository, "git+")
+	if rest, found := strings.CutPrefix(repository, "git@"); found {
+		// git@github.com:user/repo.git
+		repository = "https://" + strings.Replace(rest, ":", "/", 1)
+	}
+	return repository
+}
+
+// jsonString decodes a JSON string, returning "" for any other value
+func jsonString(raw json.RawMessage) string {
+	var s string
+	if len(raw) == 0 || json.Unmarshal(raw, &s) != nil {
+		return ""
+	}
+	return strings.TrimSpace(s)
+}
+
+// jsonField returns a string value given either directly or as the named field
+// of an object (package.json allows both for author, repository and bugs)
+func jsonField(raw json.RawMessage, field string) string {
+	if s := jsonString(raw); s != "" {
+		return s
+	}
+
+	var object map[string]json.RawMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &object) != nil {
+		return ""
+	}
+	return jsonString(object[field])
+}
+
+// personName strips the "<email>" and "(url)" parts of the npm person shorthand
+// "Name <email> (url)"
+func personName(person string) string {
+	if idx := strings.IndexAny(person, "<("); idx != -1 {
+		person = person[:idx]
+	}
+	return strings.TrimSpace(person)
+}
@@ -0,0 +1,196 @@
+package ospackages
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// apkInstalledPath is the apk database of installed packages
+const apkInstalledPath = "/lib/apk/db/installed"
+
+// ApkScanner scans Alpine packages from the apk installed database
+type ApkScanner struct{}
+
+func (s *ApkScanner) Name() string {
+	return "apk"
+}
+
+func (s *ApkScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the apk scan, stopping early if ctx is cancelled
+func (s *ApkScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
+	if cfg.IsScannerDisabled("apk") {
+		return nil, nil
+	}
+
+	if err := requireLinux("apk"); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(apkInstalledPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, scanners.NotInstalled("apk")
+		}
+		return nil, fmt.Errorf("apk scan failed: %w", err)
+	}
+	defer file.Close()
+
+	packages, err := parseApkInstalled(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse apk database: %w", err)
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if cfg.Debug {
+		fmt.Printf("Found %d installed apk packages\n", len(packages))
+	}
+
+	return buildApkComponents(packages, readOSRelease()), nil
+}
+
+// apkPackage is one package record from the installed database
+type apkPackage struct {
+	Name        string
+	Version     string
+	Arch        string
+	License     string
+	Origin      string
+	Maintainer  string
+	URL         string
+	Description string
+	Commit      string
+	Depends     []string
+	Provides    []string
+}
+
+// parseApkInstalled parses the "K:value" records of /lib/apk/db/installed
+func parseApkInstalled(r io.Reader) ([]apkPackage, error) {
+	var packages []apkPackage
+	var current apkPackage
+
+	flush := func() {
+		if current.Name != "" {
+			packages = append(packages, current)
+		}
+		current = apkPackage{}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		if len(line) < 2 || line[1] != ':' {
+			continue
+		}
+
+		value := line[2:]
+		switch line[0] {
+		case 'P':
+			current.Name = value
+		case 'V':
+			current.Version = value
+		case 'A':
+			current.Arch = value
+		case 'L':
+			current.License = value
+		case 'o':
+			current.Origin = value
+		case 'm':
+			current.Maintainer = value
+		case 'U':
+			current.URL = value
+		case 'T':
+			current.Description = value
+		case 'c':
+			current.Commit = value
+		case 'D':
+			current.Depends = strings.Fields(value)
+		case 'p':
+			current.Provides = strings.Fields(value)
+		}
+	}
+	flush()
+
+	return packages, scanner.Err()
+}
+
+// apkDependencyName strips version constraints from "so:libc.musl-x86_64.so.1" or
+// "busybox>=1.36"; negated ("!name") conflicts are ignored by the caller
+func apkDependencyName(dep string) string {
+	if idx := strings.IndexAny(dep, "<>=~"); idx != -1 {
+		dep = dep[:idx]
+	}
+	return dep
+}
+
+// buildApkComponents converts installed packages to components linked by their
+// dependencies, resolving so:/cmd:/pc: names through each package's provides
+func buildApkComponents(packages []apkPackage, release osRelease) []scanners.Component {
+	nodes := make(map[string]scanners.Component)
+	byName := make(map[string]string)
+	var keys []string
+
+	for _, pkg := range packages {
+		if _, exists := nodes[pkg.Name]; exists {
+			continue
+		}
+
+		comp := newPackageComponent("apk", "apk", pkg.Name, pkg.Version, pkg.Arch, release, "alpine")
+		comp.Description = pkg.Description
+		if pkg.Origin != "" {
+			comp.Properties["source_package"] = pkg.Origin
+		}
+		if pkg.Maintainer != "" {
+			comp.Properties["maintainer"] = pkg.Maintainer
+			comp.Supplier = contactName(pkg.Maintainer)
+		}
+		comp.License = pkg.License
+		comp.AddExternalRef(scanners.RefWebsite, pkg.URL)
+		if pkg.Commit != "" {
+			comp.Properties["aports_commit"] = pkg.Commit
+		}
+
+		nodes[pkg.Name] = comp
+		keys = append(keys, pkg.Name)
+		byName[pkg.Name] = pkg.Name
+	}
+
+	for _, pkg := range packages {
+		for _, provided := range pkg.Provides {
+			name := apkDependencyName(provided)
+			if _, exists := byName[name]; !exists {
+				byName[name] = pkg.Name
+			}
+		}
+	}
+
+	edges := make(map[string][]string)
+	for _, pkg := range packages {
+		for _, dep := range pkg.Depends {
+			if strings.HasPrefix(dep, "!") {
+				continue
+			}
+			if depKey, ok := byName[apkDependencyName(dep)]; ok {
+				edges[pkg.Name] = append(edges[pkg.Name], depKey)
+			}
+		}
+	}
+
+	return scanners.ExpandDependencyGraph(nodes, edges, keys)
+}
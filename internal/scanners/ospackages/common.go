@@ -0,0 +1,79 @@
+// Package ospackages inventories packages installed through Linux system package
+// managers by reading their databases directly, without running the package manager.
+package ospackages
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/eapolsniper/endpointbom/internal/purl"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// requireLinux returns a skip error when not running on Linux
+func requireLinux(scanner string) error {
+	if runtime.GOOS != "linux" {
+		return scanners.Skipped(scanner + " is only supported on linux")
+	}
+	return nil
+}
+
+// NewPackageComponent builds the component for a package of this host's
+// distribution as the database scanners do, so that packages found elsewhere
+// (such as in package manager logs) get matching names and purls
+func NewPackageComponent(packageManager, purlType, name, version, arch, fallbackNamespace string) scanners.Component {
+	return newPackageComponent(packageManager, purlType, name, version, arch, hostRelease(), fallbackNamespace)
+}
+
+// hostRelease reads the distribution identity once per process
+var hostRelease = sync.OnceValue(readOSRelease)
+
+// newPackageComponent builds the component for an installed OS package. The purl
+// carries the architecture and distro qualifiers vulnerability databases match on.
+func newPackageComponent(packageManager, purlType, name, version, arch string, release osRelease, fallbackNamespace string) scanners.Component {
+	comp := scanners.Component{
+		Type:           "library",
+		Name:           name,
+		Version:        version,
+		Group:          release.namespace(fallbackNamespace),
+		PackageManager: packageManager,
+		Properties:     make(map[string]string),
+	}
+
+	qualifiers := map[string]string{
+		"arch":   arch,
+		"distro": release.distro(),
+	}
+	purlVersion := version
+	if purlType == "rpm" {
+		// purl carries an rpm epoch as a qualifier rather than in the version
+		if epoch, rest, found := strings.Cut(version, ":"); found && epoch != "0" {
+			qualifiers["epoch"] = epoch
+			purlVersion = rest
+		}
+	}
+
+	comp.PackageURL = purl.PackageURL{
+		Type:       purlType,
+		Namespace:  comp.Group,
+		Name:       name,
+		Version:    purlVersion,
+		Qualifiers: qualifiers,
+	}.String()
+
+	if arch != "" {
+		comp.Properties["arch"] = arch
+	}
+	if distro := release.distro(); distro != "" {
+		comp.Properties["distro"] = distro
+	}
+
+	return comp
+}
+
+// contactName returns the name part of a "Name <email>" maintainer or packager
+func contactName(contact string) string {
+	name, _, _ := strings.Cut(contact, "<")
+	return strings.TrimSpace(name)
+}
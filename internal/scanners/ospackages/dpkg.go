@@ -0,0 +1,280 @@
+package ospackages
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/license"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+const (
+	// dpkgStatusPath is the dpkg database of installed packages
+	dpkgStatusPath = "/var/lib/dpkg/status"
+
+	// dpkgDocDir holds each package's copyright file
+	dpkgDocDir = "/usr/share/doc"
+)
+
+// DpkgScanner scans Debian/Ubuntu packages from the dpkg status database
+type DpkgScanner struct{}
+
+func (s *DpkgScanner) Name() string {
+	return "dpkg"
+}
+
+func (s *DpkgScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the dpkg scan, stopping early if ctx is cancelled
+func (s *DpkgScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
+	if cfg.IsScannerDisabled("dpkg") {
+		return nil, nil
+	}
+
+	if err := requireLinux("dpkg"); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(dpkgStatusPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, scanners.NotInstalled("dpkg")
+		}
+		return nil, fmt.Errorf("dpkg scan failed: %w", err)
+	}
+	defer file.Close()
+
+	packages, err := parseDpkgStatus(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dpkg status: %w", err)
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if cfg.Debug {
+		fmt.Printf("Found %d installed dpkg packages\n", len(packages))
+	}
+
+	for i := range packages {
+		packages[i].License = readDebianCopyright(filepath.Join(dpkgDocDir, packages[i].Package, "copyright"))
+	}
+
+	return buildDpkgComponents(packages, readOSRelease()), nil
+}
+
+// dpkgPackage is one installed package stanza from the status file
+type dpkgPackage struct {
+	Package       string
+	Version       string
+	Architecture  string
+	Source        string
+	Maintainer    string
+	Homepage      string
+	Section       string
+	InstalledSize string
+	Description   string
+	Depends       []string // Each entry is a list of alternatives
+	Provides      []string
+	License       string // From the package's machine-readable copyright file
+}
+
+// parseDpkgStatus parses the RFC 822 style stanzas of a dpkg status file,
+// keeping only packages whose status is "install ok installed"
+func parseDpkgStatus(r io.Reader) ([]dpkgPackage, error) {
+	var packages []dpkgPackage
+	fields := make(map[string]string)
+
+	flush := func() {
+		if strings.HasSuffix(fields["Status"], " installed") && fields["Package"] != "" {
+			pkg := dpkgPackage{
+				Package:       fields["Package"],
+				Version:       fields["Version"],
+				Architecture:  fields["Architecture"],
+				Source:        fields["Source"],
+				Maintainer:    fields["Maintainer"],
+				Homepage:      fields["Homepage"],
+				Section:       fields["Section"],
+				InstalledSize: fields["Installed-Size"],
+				Description:   fields["Description"],
+				Provides:      dpkgRelationNames(fields["Provides"]),
+			}
+			pkg.Depends = append(dpkgAlternatives(fields["Pre-Depends"]), dpkgAlternatives(fields["Depends"])...)
+			packages = append(packages, pkg)
+		}
+		fields = make(map[string]string)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		// Continuation lines (long descriptions, conffiles) are not needed
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		fields[key] = strings.TrimSpace(value)
+	}
+	flush()
+
+	return packages, scanner.Err()
+}
+
+// readDebianCopyright returns the licenses declared in a machine-readable
+// (DEP-5) debian/copyright file, combined with AND. Free-form copyright files
+// carry no parseable license and yield "".
+func readDebianCopyright(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	var names []string
+	seen := make(map[string]bool)
+	machineReadable := false
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "Format:") && strings.Contains(line, "copyright-format") {
+			machineReadable = true
+			continue
+		}
+		if !strings.HasPrefix(line, "License:") {
+			continue
+		}
+		// The first line of a License field is the short name; the license
+		// text follows on continuation lines
+		name := strings.TrimSpace(strings.TrimPrefix(line, "License:"))
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	if !machineReadable {
+		return ""
+	}
+	return license.Join(names)
+}
+
+// dpkgAlternatives parses a relationship field such as
+// "libc6 (>= 2.34), default-mta | mail-transport-agent" into alternative name lists
+func dpkgAlternatives(field string) []string {
+	var result []string
+	for _, relation := range strings.Split(field, ",") {
+		relation = strings.TrimSpace(relation)
+		if relation != "" {
+			result = append(result, relation)
+		}
+	}
+	return result
+}
+
+// dpkgRelationNames returns the package names in a relationship field,
+// dropping version constraints, architecture qualifiers and alternatives
+func dpkgRelationNames(field string) []string {
+	var names []string
+	for _, relation := range dpkgAlternatives(field) {
+		for _, alternative := range strings.Split(relation, "|") {
+			if name := dpkgRelationName(alternative); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// dpkgRelationName extracts the package name from "name:arch (>= version) [arch] <profile>"
+func dpkgRelationName(relation string) string {
+	relation = strings.TrimSpace(relation)
+	if idx := strings.IndexAny(relation, " ([<"); idx != -1 {
+		relation = relation[:idx]
+	}
+	name, _, _ := strings.Cut(relation, ":")
+	return name
+}
+
+// buildDpkgComponents converts installed packages to components linked by their
+// Depends/Pre-Depends relationships. For alternatives ("a | b") the first installed
+// package wins; virtual packages resolve through Provides.
+func buildDpkgComponents(packages []dpkgPackage, release osRelease) []scanners.Component {
+	nodes := make(map[string]scanners.Component)
+	byName := make(map[string]string) // package or virtual name -> node key
+	var keys []string
+
+	for _, pkg := range packages {
+		key := pkg.Package + ":" + pkg.Architecture
+		if _, exists := nodes[key]; exists {
+			continue
+		}
+
+		comp := newPackageComponent("dpkg", "deb", pkg.Package, pkg.Version, pkg.Architecture, release, "debian")
+		comp.Description = pkg.Description
+		if pkg.Source != "" {
+			comp.Properties["source_package"] = pkg.Source
+		}
+		if pkg.Maintainer != "" {
+			comp.Properties["maintainer"] = pkg.Maintainer
+			comp.Supplier = contactName(pkg.Maintainer)
+		}
+		comp.AddExternalRef(scanners.RefWebsite, pkg.Homepage)
+		if pkg.Section != "" {
+			comp.Properties["section"] = pkg.Section
+		}
+		if pkg.InstalledSize != "" {
+			comp.Properties["installed_size_kb"] = pkg.InstalledSize
+		}
+		comp.License = pkg.License
+
+		nodes[key] = comp
+		keys = append(keys, key)
+		if _, exists := byName[pkg.Package]; !exists {
+			byName[pkg.Package] = key
+		}
+	}
+
+	// Real package names take precedence over virtual names provided by others
+	for _, pkg := range packages {
+		for _, provided := range pkg.Provides {
+			if _, exists := byName[provided]; !exists {
+				byName[provided] = pkg.Package + ":" + pkg.Architecture
+			}
+		}
+	}
+
+	edges := make(map[string][]string)
+	for _, pkg := range packages {
+		key := pkg.Package + ":" + pkg.Architecture
+		for _, relation := range pkg.Depends {
+			for _, alternative := range strings.Split(relation, "|") {
+				if depKey, ok := byName[dpkgRelationName(alternative)]; ok {
+					edges[key] = append(edges[key], depKey)
+					break
+				}
+			}
+		}
+	}
+
+	return scanners.ExpandDependencyGraph(nodes, edges, keys)
+}
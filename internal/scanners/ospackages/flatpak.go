@@ -0,0 +1,280 @@
+package ospackages
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/system"
+)
+
+// flatpakSystemInstallation is the system-wide flatpak installation
+const flatpakSystemInstallation = "/var/lib/flatpak"
+
+// FlatpakScanner scans flatpak applications and runtimes from their deployed metadata files
+type FlatpakScanner struct{}
+
+func (s *FlatpakScanner) Name() string {
+	return "flatpak"
+}
+
+func (s *FlatpakScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the flatpak scan, stopping early if ctx is cancelled
+func (s *FlatpakScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
+	if cfg.IsScannerDisabled("flatpak") {
+		return nil, nil
+	}
+
+	if err := requireLinux("flatpak"); err != nil {
+		return nil, err
+	}
+
+	installations := map[string]string{flatpakSystemInstallation: "system"}
+	for _, home := range system.HomeDirs(cfg) {
+		installations[filepath.Join(home, ".local", "share", "flatpak")] = "user"
+	}
+
+	nodes := make(map[string]scanners.Component)
+	edges := make(map[string][]string)
+	var keys []string
+	found := false
+
+	for _, installation := range sortedInstallations(installations) {
+		if _, err := os.Stat(installation); err != nil {
+			continue
+		}
+		found = true
+
+		for _, kind := range []string{"app", "runtime"} {
+			refs, err := filepath.Glob(filepath.Join(installation, kind, "*", "*", "*", "active"))
+			if err != nil {
+				continue
+			}
+
+			for _, activeDir := range refs {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				if cfg.IsPathExcluded(activeDir) {
+					continue
+				}
+
+				ref, comp, runtimeRef, err := readFlatpakDeploy(activeDir, kind)
+				if err != nil {
+					if cfg.Debug {
+						fmt.Printf("Failed to read flatpak %s: %v\n", activeDir, err)
+					}
+					continue
+				}
+				comp.Properties["installation"] = installations[installation]
+				if installations[installation] == "user" {
+					comp.Properties["installation_path"] = installation
+				}
+
+				key := installation + "|" + ref
+				nodes[key] = comp
+				keys = append(keys, key)
+				if runtimeRef != "" {
+					// Apps use runtimes from their own installation or the system one
+					edges[key] = append(edges[key], installation+"|"+runtimeRef, flatpakSystemInstallation+"|"+runtimeRef)
+				}
+			}
+		}
+	}
+
+	if !found {
+		return nil, scanners.NotInstalled("flatpak")
+	}
+
+	return scanners.ExpandDependencyGraph(nodes, edges, keys), nil
+}
+
+// sortedInstallations returns installation paths with the system installation first
+func sortedInstallations(installations map[string]string) []string {
+	var userPaths []string
+	for path := range installations {
+		if path != flatpakSystemInstallation {
+			userPaths = append(userPaths, path)
+		}
+	}
+	sort.Strings(userPaths)
+	return append([]string{flatpakSystemInstallation}, userPaths...)
+}
+
+// readFlatpakDeploy reads the deployment at <installation>/<kind>/<id>/<arch>/<branch>/active.
+// It returns the ref ("id/arch/branch"), the component and, for apps, the runtime ref.
+func readFlatpakDeploy(activeDir, kind string) (string, scanners.Component, string, error) {
+	branchDir := filepath.Dir(activeDir)
+	archDir := filepath.Dir(branchDir)
+	id := filepath.Base(filepath.Dir(archDir))
+	arch := filepath.Base(archDir)
+	branch := filepath.Base(branchDir)
+	ref := id + "/" + arch + "/" + branch
+
+	metadata, err := parseFlatpakMetadata(filepath.Join(activeDir, "metadata"))
+	if err != nil {
+		return "", scanners.Component{}, "", err
+	}
+
+	section := "Application"
+	compType := "application"
+	if kind == "runtime" {
+		section = "Runtime"
+		compType = "library"
+	}
+	if name := metadata[section+".name"]; name != "" {
+		id = name
+	}
+
+	comp := scanners.Component{
+		Type:           compType,
+		Name:           id,
+		Version:        branch,
+		PackageManager: "flatpak",
+		Location:       branchDir,
+		Properties:     make(map[string]string),
+	}
+	if metainfo, ok := readFlatpakMetainfo(activeDir, id); ok {
+		metainfo.applyTo(&comp)
+	}
+
+	comp.Properties["flatpak_kind"] = kind
+	comp.Properties["arch"] = arch
+	comp.Properties["branch"] = branch
+	if commit, err := os.Readlink(activeDir); err == nil {
+		comp.Properties["commit"] = filepath.Base(commit)
+	}
+
+	runtimeRef := metadata[section+".runtime"]
+	if runtimeRef != "" {
+		comp.Properties["runtime"] = runtimeRef
+	}
+	if sdk := metadata[section+".sdk"]; sdk != "" {
+		comp.Properties["sdk"] = sdk
+	}
+
+	return ref, comp, runtimeRef, nil
+}
+
+// parseFlatpakMetadata parses the keyfile-format metadata into "Section.key" entries
+func parseFlatpakMetadata(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	section := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line[1 : len(line)-1]
+			continue
+		}
+		if key, value, found := strings.Cut(line, "="); found {
+			values[section+"."+strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	return values, scanner.Err()
+}
+
+// flatpakMetainfo is the part of an AppStream metainfo file the scanner uses
+type flatpakMetainfo struct {
+	ProjectLicense string          `xml:"project_license"`
+	DeveloperName  []appstreamText `xml:"developer_name"` // Before AppStream 1.0
+	Developer      struct {
+		Name []appstreamText `xml:"name"`
+	} `xml:"developer"`
+	URLs []struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"url"`
+	Releases []struct {
+		Version string `xml:"version,attr"`
+	} `xml:"releases>release"`
+}
+
+// appstreamText is a translatable AppStream element; translations carry xml:lang
+type appstreamText struct {
+	Lang  string `xml:"lang,attr"`
+	Value string `xml:",chardata"`
+}
+
+// untranslated returns the value of the element without xml:lang
+func untranslated(texts []appstreamText) string {
+	for _, text := range texts {
+		if text.Lang == "" {
+			return strings.TrimSpace(text.Value)
+		}
+	}
+	return ""
+}
+
+// flatpakURLTypes maps AppStream url types to external reference types
+var flatpakURLTypes = map[string]string{
+	"homepage":    scanners.RefWebsite,
+	"bugtracker":  scanners.RefIssueTracker,
+	"vcs-browser": scanners.RefVCS,
+	"help":        scanners.RefDocumentation,
+}
+
+// readFlatpakMetainfo reads the deployment's AppStream metainfo, which is the
+// only place flatpak records an upstream version, license and developer
+func readFlatpakMetainfo(activeDir, id string) (flatpakMetainfo, bool) {
+	candidates := []string{
+		filepath.Join(activeDir, "files", "share", "metainfo", id+".metainfo.xml"),
+		filepath.Join(activeDir, "files", "share", "metainfo", id+".appdata.xml"),
+		filepath.Join(activeDir, "files", "share", "appdata", id+".appdata.xml"),
+	}
+
+	for _, path := range candidates {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var metainfo flatpakMetainfo
+		if err := xml.Unmarshal(data, &metainfo); err == nil {
+			return metainfo, true
+		}
+	}
+
+	return flatpakMetainfo{}, false
+}
+
+// applyTo sets the component's version, license, developer and URLs
+func (m flatpakMetainfo) applyTo(comp *scanners.Component) {
+	if len(m.Releases) > 0 && m.Releases[0].Version != "" {
+		comp.Version = m.Releases[0].Version
+	}
+	comp.License = strings.TrimSpace(m.ProjectLicense)
+
+	developer := untranslated(m.Developer.Name)
+	if developer == "" {
+		developer = untranslated(m.DeveloperName)
+	}
+	comp.Supplier = developer
+	comp.Author = developer
+
+	for _, u := range m.URLs {
+		if refType, ok := flatpakURLTypes[u.Type]; ok {
+			comp.AddExternalRef(refType, u.Value)
+		}
+	}
+}
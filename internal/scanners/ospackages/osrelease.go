@@ -0,0 +1,90 @@
+package ospackages
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// osRelease holds the distribution identity from /etc/os-release
+type osRelease struct {
+	ID              string // e.g. "debian", "ubuntu", "fedora", "alpine"
+	IDLike          string
+	VersionID       string // e.g. "12", "22.04", "3.19.1"
+	VersionCodename string // e.g. "bookworm"
+	PrettyName      string
+}
+
+// osReleasePaths are checked in order, as documented in os-release(5)
+var osReleasePaths = []string{"/etc/os-release", "/usr/lib/os-release"}
+
+// readOSRelease reads the distribution identity. A zero value is returned when
+// no os-release file exists.
+func readOSRelease() osRelease {
+	for _, path := range osReleasePaths {
+		if release, err := parseOSRelease(path); err == nil {
+			return release
+		}
+	}
+	return osRelease{}
+}
+
+func parseOSRelease(path string) (osRelease, error) {
+	var release osRelease
+
+	file, err := os.Open(path)
+	if err != nil {
+		return release, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+
+		switch key {
+		case "ID":
+			release.ID = strings.ToLower(value)
+		case "ID_LIKE":
+			release.IDLike = value
+		case "VERSION_ID":
+			release.VersionID = value
+		case "VERSION_CODENAME":
+			release.VersionCodename = value
+		case "PRETTY_NAME":
+			release.PrettyName = value
+		}
+	}
+
+	return release, scanner.Err()
+}
+
+// namespace returns the purl namespace for the distribution, falling back to the
+// given default when os-release does not identify it
+func (r osRelease) namespace(fallback string) string {
+	if r.ID != "" {
+		return r.ID
+	}
+	return fallback
+}
+
+// distro returns the purl distro qualifier, e.g. "debian-12" or "alpine-3.19.1"
+func (r osRelease) distro() string {
+	switch {
+	case r.ID != "" && r.VersionID != "":
+		return r.ID + "-" + r.VersionID
+	case r.ID != "" && r.VersionCodename != "":
+		return r.ID + "-" + r.VersionCodename
+	default:
+		return r.ID
+	}
+}
@@ -0,0 +1,201 @@
+package ospackages
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/license"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// pacmanLocalDB holds one directory per installed package, each with a desc file
+const pacmanLocalDB = "/var/lib/pacman/local"
+
+// PacmanScanner scans Arch Linux packages from the pacman local database
+type PacmanScanner struct{}
+
+func (s *PacmanScanner) Name() string {
+	return "pacman"
+}
+
+func (s *PacmanScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the pacman scan, stopping early if ctx is cancelled
+func (s *PacmanScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
+	if cfg.IsScannerDisabled("pacman") {
+		return nil, nil
+	}
+
+	if err := requireLinux("pacman"); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(pacmanLocalDB)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, scanners.NotInstalled("pacman")
+		}
+		return nil, fmt.Errorf("pacman scan failed: %w", err)
+	}
+
+	var packages []pacmanPackage
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !entry.IsDir() {
+			continue
+		}
+
+		pkg, err := parsePacmanDesc(filepath.Join(pacmanLocalDB, entry.Name(), "desc"))
+		if err != nil {
+			if cfg.Debug {
+				fmt.Printf("Failed to read pacman package %s: %v\n", entry.Name(), err)
+			}
+			continue
+		}
+		packages = append(packages, pkg)
+	}
+
+	if cfg.Debug {
+		fmt.Printf("Found %d installed pacman packages\n", len(packages))
+	}
+
+	return buildPacmanComponents(packages, readOSRelease()), nil
+}
+
+// pacmanPackage is the parsed desc file of an installed package
+type pacmanPackage struct {
+	Name        string
+	Version     string
+	Base        string
+	Arch        string
+	Description string
+	URL         string
+	Packager    string
+	Licenses    []string
+	Depends     []string
+	Provides    []string
+}
+
+// parsePacmanDesc parses a desc file made of "%FIELD%" headers each followed by
+// one value per line and terminated by a blank line
+func parsePacmanDesc(path string) (pacmanPackage, error) {
+	var pkg pacmanPackage
+
+	file, err := os.Open(path)
+	if err != nil {
+		return pkg, err
+	}
+	defer file.Close()
+
+	fields := make(map[string][]string)
+	var current string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			current = ""
+		case strings.HasPrefix(line, "%") && strings.HasSuffix(line, "%"):
+			current = strings.Trim(line, "%")
+		case current != "":
+			fields[current] = append(fields[current], line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return pkg, err
+	}
+
+	first := func(key string) string {
+		if values := fields[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	pkg = pacmanPackage{
+		Name:        first("NAME"),
+		Version:     first("VERSION"),
+		Base:        first("BASE"),
+		Arch:        first("ARCH"),
+		Description: first("DESC"),
+		URL:         first("URL"),
+		Packager:    first("PACKAGER"),
+		Licenses:    fields["LICENSE"],
+		Depends:     fields["DEPENDS"],
+		Provides:    fields["PROVIDES"],
+	}
+	if pkg.Name == "" {
+		return pkg, fmt.Errorf("no %%NAME%% in %s", path)
+	}
+
+	return pkg, nil
+}
+
+// pacmanDependencyName strips version constraints and descriptions from a
+// dependency or provides entry such as "glibc>=2.38" or "sh: for scripts"
+func pacmanDependencyName(dep string) string {
+	if idx := strings.IndexAny(dep, "<>=:"); idx != -1 {
+		dep = dep[:idx]
+	}
+	return strings.TrimSpace(dep)
+}
+
+// buildPacmanComponents converts installed packages to components linked by their
+// dependencies, resolving virtual names through provides
+func buildPacmanComponents(packages []pacmanPackage, release osRelease) []scanners.Component {
+	nodes := make(map[string]scanners.Component)
+	byName := make(map[string]string)
+	var keys []string
+
+	for _, pkg := range packages {
+		if _, exists := nodes[pkg.Name]; exists {
+			continue
+		}
+
+		comp := newPackageComponent("pacman", "alpm", pkg.Name, pkg.Version, pkg.Arch, release, "arch")
+		comp.Description = pkg.Description
+		if pkg.Base != "" && pkg.Base != pkg.Name {
+			comp.Properties["source_package"] = pkg.Base
+		}
+		if pkg.Packager != "" {
+			comp.Properties["packager"] = pkg.Packager
+			comp.Supplier = contactName(pkg.Packager)
+		}
+		comp.License = license.Join(pkg.Licenses)
+		comp.AddExternalRef(scanners.RefWebsite, pkg.URL)
+
+		nodes[pkg.Name] = comp
+		keys = append(keys, pkg.Name)
+		byName[pkg.Name] = pkg.Name
+	}
+
+	for _, pkg := range packages {
+		for _, provided := range pkg.Provides {
+			name := pacmanDependencyName(provided)
+			if _, exists := byName[name]; !exists {
+				byName[name] = pkg.Name
+			}
+		}
+	}
+
+	edges := make(map[string][]string)
+	for _, pkg := range packages {
+		for _, dep := range pkg.Depends {
+			if depKey, ok := byName[pacmanDependencyName(dep)]; ok {
+				edges[pkg.Name] = append(edges[pkg.Name], depKey)
+			}
+		}
+	}
+
+	return scanners.ExpandDependencyGraph(nodes, edges, keys)
+}
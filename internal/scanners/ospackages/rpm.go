@@ -0,0 +1,165 @@
+package ospackages
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// rpmDatabase is one rpm database backend and the file it lives in
+type rpmDatabase struct {
+	path string
+	read func(path string) ([][]byte, error)
+}
+
+// rpmDatabaseDirs are checked in order; newer distributions keep the database
+// under /usr/lib/sysimage/rpm and symlink /var/lib/rpm to it
+var rpmDatabaseDirs = []string{"/var/lib/rpm", "/usr/lib/sysimage/rpm"}
+
+// RpmScanner scans RPM packages (Fedora, RHEL and derivatives, SUSE) from the rpm
+// database. The sqlite, Berkeley DB and NDB backends are all read directly.
+type RpmScanner struct{}
+
+func (s *RpmScanner) Name() string {
+	return "rpm"
+}
+
+func (s *RpmScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the rpm scan, stopping early if ctx is cancelled
+func (s *RpmScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
+	if cfg.IsScannerDisabled("rpm") {
+		return nil, nil
+	}
+
+	if err := requireLinux("rpm"); err != nil {
+		return nil, err
+	}
+
+	db, ok := findRPMDatabase()
+	if !ok {
+		return nil, scanners.NotInstalled("rpm")
+	}
+
+	blobs, err := db.read(db.path)
+	if err != nil {
+		return nil, fmt.Errorf("rpm scan failed: %w", err)
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var packages []*rpmPackage
+	for _, blob := range blobs {
+		pkg, err := parseRPMHeader(blob)
+		if err != nil {
+			if cfg.Debug {
+				fmt.Printf("Skipping unreadable rpm header in %s: %v\n", db.path, err)
+			}
+			continue
+		}
+		// Imported signing keys are stored as pseudo-packages
+		if pkg.Name == "gpg-pubkey" {
+			continue
+		}
+		packages = append(packages, pkg)
+	}
+
+	if cfg.Debug {
+		fmt.Printf("Found %d installed rpm packages in %s\n", len(packages), db.path)
+	}
+
+	return buildRPMComponents(packages, readOSRelease()), nil
+}
+
+// findRPMDatabase returns the first rpm database present, preferring the sqlite
+// backend (rpm 4.16+) over NDB and Berkeley DB
+func findRPMDatabase() (rpmDatabase, bool) {
+	for _, dir := range rpmDatabaseDirs {
+		candidates := []rpmDatabase{
+			{path: filepath.Join(dir, "rpmdb.sqlite"), read: readSQLiteRPMDB},
+			{path: filepath.Join(dir, "Packages.db"), read: readNDBRPMDB},
+			{path: filepath.Join(dir, "Packages"), read: readBerkeleyRPMDB},
+		}
+		for _, candidate := range candidates {
+			if info, err := os.Stat(candidate.path); err == nil && info.Mode().IsRegular() && info.Size() > 0 {
+				return candidate, true
+			}
+		}
+	}
+	return rpmDatabase{}, false
+}
+
+// buildRPMComponents converts installed packages to components linked by their
+// requirements, resolved through what each package provides. File requirements
+// such as /bin/sh are not resolved because file lists are not read.
+func buildRPMComponents(packages []*rpmPackage, release osRelease) []scanners.Component {
+	nodes := make(map[string]scanners.Component)
+	providers := make(map[string]string) // capability -> node key
+	var keys []string
+
+	for _, pkg := range packages {
+		key := pkg.Name + "-" + pkg.evr() + "." + pkg.Arch
+		if _, exists := nodes[key]; exists {
+			continue
+		}
+
+		comp := newPackageComponent("rpm", "rpm", pkg.Name, pkg.evr(), pkg.Arch, release, "redhat")
+		comp.Description = pkg.Summary
+
+		for property, value := range map[string]string{
+			"source_package":   pkg.SourceRPM,
+			"packager":         pkg.Packager,
+			"modularity_label": pkg.ModularityLabel,
+		} {
+			if value != "" && value != "(none)" {
+				comp.Properties[property] = value
+			}
+		}
+		if pkg.Vendor != "(none)" {
+			comp.Supplier = pkg.Vendor
+		}
+		comp.AddExternalRef(scanners.RefWebsite, pkg.URL)
+
+		if pkg.License != "(none)" {
+			comp.License = pkg.License
+		}
+
+		nodes[key] = comp
+		keys = append(keys, key)
+		if _, exists := providers[pkg.Name]; !exists {
+			providers[pkg.Name] = key
+		}
+	}
+
+	for _, pkg := range packages {
+		key := pkg.Name + "-" + pkg.evr() + "." + pkg.Arch
+		for _, provided := range pkg.Provides {
+			if _, exists := providers[provided]; !exists {
+				providers[provided] = key
+			}
+		}
+	}
+
+	edges := make(map[string][]string)
+	for _, pkg := range packages {
+		key := pkg.Name + "-" + pkg.evr() + "." + pkg.Arch
+		for _, required := range pkg.Requires {
+			if strings.HasPrefix(required, "rpmlib(") {
+				continue
+			}
+			if depKey, ok := providers[required]; ok {
+				edges[key] = append(edges[key], depKey)
+			}
+		}
+	}
+
+	return scanners.ExpandDependencyGraph(nodes, edges, keys)
+}
@@ -0,0 +1,156 @@
+package ospackages
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// RPM header tags used for the inventory (see rpmtag.h)
+const (
+	rpmTagName            = 1000
+	rpmTagVersion         = 1001
+	rpmTagRelease         = 1002
+	rpmTagEpoch           = 1003
+	rpmTagSummary         = 1004
+	rpmTagVendor          = 1011
+	rpmTagLicense         = 1014
+	rpmTagPackager        = 1015
+	rpmTagURL             = 1020
+	rpmTagArch            = 1022
+	rpmTagSourceRPM       = 1044
+	rpmTagProvideName     = 1047
+	rpmTagRequireName     = 1049
+	rpmTagModularityLabel = 5096
+)
+
+// RPM header data types
+const (
+	rpmTypeInt32       = 4
+	rpmTypeString      = 6
+	rpmTypeStringArray = 8
+	rpmTypeI18NString  = 9
+)
+
+// rpmPackage is the subset of an installed package header used for the inventory
+type rpmPackage struct {
+	Name            string
+	Version         string
+	Release         string
+	Epoch           int
+	HasEpoch        bool
+	Arch            string
+	Summary         string
+	Vendor          string
+	License         string
+	Packager        string
+	URL             string
+	SourceRPM       string
+	ModularityLabel string
+	Provides        []string
+	Requires        []string
+}
+
+// parseRPMHeader parses a header blob as stored in the rpm database: a big-endian
+// entry count and data length, the index entries, then the data store
+func parseRPMHeader(blob []byte) (*rpmPackage, error) {
+	if len(blob) < 8 {
+		return nil, fmt.Errorf("rpm header too short")
+	}
+
+	indexCount := int(binary.BigEndian.Uint32(blob[0:4]))
+	dataLength := int(binary.BigEndian.Uint32(blob[4:8]))
+	dataStart := 8 + indexCount*16
+	if indexCount <= 0 || dataLength < 0 || dataStart+dataLength > len(blob) {
+		return nil, fmt.Errorf("invalid rpm header (%d entries, %d data bytes)", indexCount, dataLength)
+	}
+	data := blob[dataStart : dataStart+dataLength]
+
+	pkg := &rpmPackage{}
+	for i := 0; i < indexCount; i++ {
+		entry := blob[8+i*16 : 8+(i+1)*16]
+		tag := int(binary.BigEndian.Uint32(entry[0:4]))
+		dataType := int(binary.BigEndian.Uint32(entry[4:8]))
+		offset := int(binary.BigEndian.Uint32(entry[8:12]))
+		count := int(binary.BigEndian.Uint32(entry[12:16]))
+		if offset < 0 || offset >= len(data) {
+			continue
+		}
+
+		switch tag {
+		case rpmTagName:
+			pkg.Name = rpmString(data, offset, dataType)
+		case rpmTagVersion:
+			pkg.Version = rpmString(data, offset, dataType)
+		case rpmTagRelease:
+			pkg.Release = rpmString(data, offset, dataType)
+		case rpmTagEpoch:
+			if dataType == rpmTypeInt32 && offset+4 <= len(data) {
+				pkg.Epoch = int(binary.BigEndian.Uint32(data[offset : offset+4]))
+				pkg.HasEpoch = true
+			}
+		case rpmTagSummary:
+			pkg.Summary = rpmString(data, offset, dataType)
+		case rpmTagVendor:
+			pkg.Vendor = rpmString(data, offset, dataType)
+		case rpmTagLicense:
+			pkg.License = rpmString(data, offset, dataType)
+		case rpmTagPackager:
+			pkg.Packager = rpmString(data, offset, dataType)
+		case rpmTagURL:
+			pkg.URL = rpmString(data, offset, dataType)
+		case rpmTagArch:
+			pkg.Arch = rpmString(data, offset, dataType)
+		case rpmTagSourceRPM:
+			pkg.SourceRPM = rpmString(data, offset, dataType)
+		case rpmTagModularityLabel:
+			pkg.ModularityLabel = rpmString(data, offset, dataType)
+		case rpmTagProvideName:
+			pkg.Provides = rpmStringArray(data, offset, count, dataType)
+		case rpmTagRequireName:
+			pkg.Requires = rpmStringArray(data, offset, count, dataType)
+		}
+	}
+
+	if pkg.Name == "" {
+		return nil, fmt.Errorf("rpm header has no name")
+	}
+	return pkg, nil
+}
+
+// rpmString reads a NUL-terminated string; for I18N strings the first (default locale) is used
+func rpmString(data []byte, offset, dataType int) string {
+	if dataType != rpmTypeString && dataType != rpmTypeI18NString && dataType != rpmTypeStringArray {
+		return ""
+	}
+	end := offset
+	for end < len(data) && data[end] != 0 {
+		end++
+	}
+	return string(data[offset:end])
+}
+
+// rpmStringArray reads count consecutive NUL-terminated strings
+func rpmStringArray(data []byte, offset, count, dataType int) []string {
+	if dataType != rpmTypeStringArray {
+		return nil
+	}
+	values := make([]string, 0, count)
+	for i := 0; i < count && offset < len(data); i++ {
+		value := rpmString(data, offset, dataType)
+		values = append(values, value)
+		offset += len(value) + 1
+	}
+	return values
+}
+
+// evr returns the package version as [epoch:]version-release
+func (p *rpmPackage) evr() string {
+	version := p.Version
+	if p.Release != "" {
+		version += "-" + p.Release
+	}
+	if p.HasEpoch && p.Epoch > 0 {
+		version = fmt.Sprintf("%d:%s", p.Epoch, version)
+	}
+	return version
+}
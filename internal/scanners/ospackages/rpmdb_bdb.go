@@ -0,0 +1,119 @@
+package ospackages
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+)
+
+// Berkeley DB hash database constants (see libdb db_page.h)
+const (
+	bdbHashMagic        = 0x061561
+	bdbPageHeaderSize   = 26
+	bdbHashUnsortedPage = 2
+	bdbOverflowPage     = 7
+	bdbHashPage         = 13
+	bdbOffPageItem      = 3
+	bdbOffPageItemSize  = 12
+	bdbMaxOverflowPages = 1 << 20
+)
+
+// readBerkeleyRPMDB reads every header blob from a Berkeley DB hash Packages
+// database (rpm before 4.16, e.g. RHEL/CentOS 7 and 8).
+//
+// Hash pages hold key/value pairs; package headers are always large enough to be
+// stored off-page, so only HOFFPAGE values are followed through their overflow
+// page chain.
+func readBerkeleyRPMDB(path string) ([][]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	meta := make([]byte, 512)
+	if _, err := file.ReadAt(meta, 0); err != nil {
+		return nil, fmt.Errorf("failed to read berkeley db metadata: %w", err)
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	switch {
+	case binary.LittleEndian.Uint32(meta[12:16]) == bdbHashMagic:
+	case binary.BigEndian.Uint32(meta[12:16]) == bdbHashMagic:
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("not a berkeley db hash database")
+	}
+
+	pageSize := order.Uint32(meta[20:24])
+	if pageSize < 512 || pageSize > 65536 || pageSize&(pageSize-1) != 0 {
+		return nil, fmt.Errorf("unexpected berkeley db page size %d", pageSize)
+	}
+	lastPage := order.Uint32(meta[32:36])
+
+	readPage := func(number uint32) ([]byte, error) {
+		page := make([]byte, pageSize)
+		if _, err := file.ReadAt(page, int64(number)*int64(pageSize)); err != nil {
+			return nil, fmt.Errorf("failed to read berkeley db page %d: %w", number, err)
+		}
+		return page, nil
+	}
+
+	var blobs [][]byte
+	for number := uint32(0); number <= lastPage; number++ {
+		page, err := readPage(number)
+		if err != nil {
+			return nil, err
+		}
+		if page[25] != bdbHashPage && page[25] != bdbHashUnsortedPage {
+			continue
+		}
+
+		// The index array holds key/value pairs; values are at odd positions
+		entries := int(order.Uint16(page[20:22]))
+		for i := 1; i < entries; i += 2 {
+			indexOffset := bdbPageHeaderSize + i*2
+			if indexOffset+2 > len(page) {
+				break
+			}
+			item := int(order.Uint16(page[indexOffset : indexOffset+2]))
+			if item+bdbOffPageItemSize > len(page) || page[item] != bdbOffPageItem {
+				continue
+			}
+
+			blob, err := readBerkeleyOverflow(readPage, order, order.Uint32(page[item+4:item+8]), order.Uint32(page[item+8:item+12]))
+			if err != nil {
+				return nil, err
+			}
+			blobs = append(blobs, blob)
+		}
+	}
+
+	return blobs, nil
+}
+
+// readBerkeleyOverflow concatenates an overflow page chain; each page's
+// hf_offset field holds the number of data bytes it carries
+func readBerkeleyOverflow(readPage func(uint32) ([]byte, error), order binary.ByteOrder, first, totalLength uint32) ([]byte, error) {
+	blob := make([]byte, 0, totalLength)
+	for number, hops := first, 0; number != 0; hops++ {
+		if hops > bdbMaxOverflowPages {
+			return nil, fmt.Errorf("berkeley db overflow chain too long")
+		}
+		page, err := readPage(number)
+		if err != nil {
+			return nil, err
+		}
+		if page[25] != bdbOverflowPage {
+			return nil, fmt.Errorf("berkeley db page %d is not an overflow page", number)
+		}
+
+		length := int(order.Uint16(page[22:24]))
+		if bdbPageHeaderSize+length > len(page) {
+			return nil, fmt.Errorf("corrupt berkeley db overflow page %d", number)
+		}
+		blob = append(blob, page[bdbPageHeaderSize:bdbPageHeaderSize+length]...)
+		number = order.Uint32(page[16:20])
+	}
+	return blob, nil
+}
@@ -0,0 +1,87 @@
+package ospackages
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+)
+
+// rpm NDB backend constants (see rpm lib/backend/ndb/rpmpkg.c)
+const (
+	ndbHeaderMagic  = 'R' | 'p'<<8 | 'm'<<16 | 'P'<<24
+	ndbSlotMagic    = 'S' | 'l'<<8 | 'o'<<16 | 't'<<24
+	ndbBlobMagic    = 'B' | 'l'<<8 | 'b'<<16 | 'S'<<24
+	ndbPageSize     = 4096
+	ndbSlotSize     = 16
+	ndbBlockSize    = 16
+	ndbBlobHeadSize = 16
+	ndbHeaderSlots  = 2
+	ndbMaxSlotPages = 2048
+)
+
+// readNDBRPMDB reads every header blob from an rpm NDB Packages.db database (SUSE).
+//
+// The file starts with a table of 16-byte slots (the first two hold the file
+// header); each used slot points at a blob, stored in 16-byte blocks, that carries
+// one package header.
+func readNDBRPMDB(path string) ([][]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	header := make([]byte, 32)
+	if _, err := file.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read ndb header: %w", err)
+	}
+	if binary.LittleEndian.Uint32(header[0:4]) != ndbHeaderMagic {
+		return nil, fmt.Errorf("not an rpm ndb database")
+	}
+	if version := binary.LittleEndian.Uint32(header[4:8]); version != 0 {
+		return nil, fmt.Errorf("unsupported ndb version %d", version)
+	}
+	slotPages := binary.LittleEndian.Uint32(header[12:16])
+	if slotPages == 0 || slotPages > ndbMaxSlotPages {
+		return nil, fmt.Errorf("invalid ndb slot page count %d", slotPages)
+	}
+
+	slots := make([]byte, int(slotPages)*ndbPageSize)
+	if _, err := file.ReadAt(slots, 0); err != nil {
+		return nil, fmt.Errorf("failed to read ndb slots: %w", err)
+	}
+
+	var blobs [][]byte
+	for offset := ndbHeaderSlots * ndbSlotSize; offset+ndbSlotSize <= len(slots); offset += ndbSlotSize {
+		slot := slots[offset : offset+ndbSlotSize]
+		if binary.LittleEndian.Uint32(slot[0:4]) != ndbSlotMagic {
+			return nil, fmt.Errorf("corrupt ndb slot at offset %d", offset)
+		}
+		pkgIndex := binary.LittleEndian.Uint32(slot[4:8])
+		if pkgIndex == 0 {
+			continue
+		}
+		blockOffset := int64(binary.LittleEndian.Uint32(slot[8:12])) * ndbBlockSize
+
+		blobHeader := make([]byte, ndbBlobHeadSize)
+		if _, err := file.ReadAt(blobHeader, blockOffset); err != nil {
+			return nil, fmt.Errorf("failed to read ndb blob header: %w", err)
+		}
+		if binary.LittleEndian.Uint32(blobHeader[0:4]) != ndbBlobMagic || binary.LittleEndian.Uint32(blobHeader[4:8]) != pkgIndex {
+			return nil, fmt.Errorf("corrupt ndb blob for package %d", pkgIndex)
+		}
+		blobLength := binary.LittleEndian.Uint32(blobHeader[12:16])
+		blockCount := int64(binary.LittleEndian.Uint32(slot[12:16]))
+		if int64(blobLength) > blockCount*ndbBlockSize {
+			return nil, fmt.Errorf("corrupt ndb blob length for package %d", pkgIndex)
+		}
+
+		blob := make([]byte, blobLength)
+		if _, err := file.ReadAt(blob, blockOffset+ndbBlobHeadSize); err != nil {
+			return nil, fmt.Errorf("failed to read ndb blob: %w", err)
+		}
+		blobs = append(blobs, blob)
+	}
+
+	return blobs, nil
+}
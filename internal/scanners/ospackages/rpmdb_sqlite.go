@@ -0,0 +1,314 @@
+package ospackages
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// readSQLiteRPMDB reads every header blob from an rpmdb.sqlite database (rpm 4.16+).
+//
+// Only the parts of the SQLite file format needed to read one table are
+// implemented: the schema table on page 1 is searched for the "Packages" table and
+// its table b-tree is walked, following overflow pages for large blobs. The
+// database is opened read-only; a write-ahead log that has not been checkpointed
+// is not applied.
+func readSQLiteRPMDB(path string) ([][]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	db, err := openSQLite(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var rootPage uint32
+	err = db.walkTable(1, func(record []interface{}) error {
+		if len(record) >= 4 && record[0] == "table" && record[1] == "Packages" {
+			if page, ok := record[3].(int64); ok {
+				rootPage = uint32(page)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rootPage == 0 {
+		return nil, fmt.Errorf("no Packages table in %s", path)
+	}
+
+	// CREATE TABLE Packages (hnum INTEGER PRIMARY KEY AUTOINCREMENT, blob BLOB NOT NULL)
+	var blobs [][]byte
+	err = db.walkTable(rootPage, func(record []interface{}) error {
+		if len(record) >= 2 {
+			if blob, ok := record[1].([]byte); ok {
+				blobs = append(blobs, blob)
+			}
+		}
+		return nil
+	})
+	return blobs, err
+}
+
+// sqliteDB reads pages from a SQLite database file
+type sqliteDB struct {
+	file       io.ReaderAt
+	pageSize   int
+	usableSize int
+	pageCount  uint32
+}
+
+func openSQLite(file *os.File) (*sqliteDB, error) {
+	header := make([]byte, 100)
+	if _, err := file.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("failed to read sqlite header: %w", err)
+	}
+	if !bytes.HasPrefix(header, []byte("SQLite format 3\x00")) {
+		return nil, fmt.Errorf("not a sqlite database")
+	}
+
+	pageSize := int(binary.BigEndian.Uint16(header[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 {
+		return nil, fmt.Errorf("invalid sqlite page size %d", pageSize)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqliteDB{
+		file:       file,
+		pageSize:   pageSize,
+		usableSize: pageSize - int(header[20]),
+		pageCount:  uint32(info.Size() / int64(pageSize)),
+	}, nil
+}
+
+// page reads a 1-based page
+func (db *sqliteDB) page(number uint32) ([]byte, error) {
+	if number == 0 || number > db.pageCount {
+		return nil, fmt.Errorf("sqlite page %d out of range", number)
+	}
+	page := make([]byte, db.pageSize)
+	if _, err := db.file.ReadAt(page, int64(number-1)*int64(db.pageSize)); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// walkTable calls fn with the decoded record of every row in a table b-tree
+func (db *sqliteDB) walkTable(rootPage uint32, fn func(record []interface{}) error) error {
+	visited := make(map[uint32]bool)
+
+	var walk func(number uint32) error
+	walk = func(number uint32) error {
+		if visited[number] {
+			return fmt.Errorf("sqlite b-tree loop at page %d", number)
+		}
+		visited[number] = true
+
+		page, err := db.page(number)
+		if err != nil {
+			return err
+		}
+
+		// Page 1 starts with the 100-byte database header
+		headerOffset := 0
+		if number == 1 {
+			headerOffset = 100
+		}
+		header := page[headerOffset:]
+		pageType := header[0]
+		cellCount := int(binary.BigEndian.Uint16(header[3:5]))
+
+		cellPointers := headerOffset + 8
+		if pageType == 0x05 {
+			cellPointers = headerOffset + 12
+		}
+
+		for i := 0; i < cellCount; i++ {
+			pointerOffset := cellPointers + i*2
+			if pointerOffset+2 > len(page) {
+				return fmt.Errorf("corrupt sqlite page %d", number)
+			}
+			cell := int(binary.BigEndian.Uint16(page[pointerOffset : pointerOffset+2]))
+			if cell >= len(page) {
+				return fmt.Errorf("corrupt sqlite page %d", number)
+			}
+
+			switch pageType {
+			case 0x05: // Table interior: left child pointer, then rowid
+				if cell+4 > len(page) {
+					return fmt.Errorf("corrupt sqlite page %d", number)
+				}
+				if err := walk(binary.BigEndian.Uint32(page[cell : cell+4])); err != nil {
+					return err
+				}
+			case 0x0d: // Table leaf: payload size, rowid, payload
+				record, err := db.readLeafCell(page, cell)
+				if err != nil {
+					return err
+				}
+				if err := fn(record); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unexpected sqlite page type 0x%02x on page %d", pageType, number)
+			}
+		}
+
+		if pageType == 0x05 {
+			return walk(binary.BigEndian.Uint32(header[8:12]))
+		}
+		return nil
+	}
+
+	return walk(rootPage)
+}
+
+// readLeafCell assembles a table leaf cell's payload, following overflow pages
+func (db *sqliteDB) readLeafCell(page []byte, cell int) ([]interface{}, error) {
+	payloadSize, n := sqliteVarint(page[cell:])
+	cell += n
+	// A payload cannot be larger than the file it is stored in
+	if payloadSize > uint64(db.pageCount)*uint64(db.pageSize) {
+		return nil, fmt.Errorf("corrupt sqlite cell")
+	}
+	_, n = sqliteVarint(page[cell:]) // rowid
+	cell += n
+
+	// Local payload size rules from the file format spec (section 1.6)
+	usable := db.usableSize
+	maxLocal := usable - 35
+	minLocal := ((usable-12)*32)/255 - 23
+	local := int(payloadSize)
+	if local > maxLocal {
+		local = minLocal + (int(payloadSize)-minLocal)%(usable-4)
+		if local > maxLocal {
+			local = minLocal
+		}
+	}
+	if cell+local > len(page) {
+		return nil, fmt.Errorf("corrupt sqlite cell")
+	}
+
+	payload := make([]byte, 0, payloadSize)
+	payload = append(payload, page[cell:cell+local]...)
+
+	if int64(local) < int64(payloadSize) {
+		if cell+local+4 > len(page) {
+			return nil, fmt.Errorf("corrupt sqlite cell")
+		}
+		next := binary.BigEndian.Uint32(page[cell+local : cell+local+4])
+		for next != 0 && int64(len(payload)) < int64(payloadSize) {
+			overflow, err := db.page(next)
+			if err != nil {
+				return nil, err
+			}
+			next = binary.BigEndian.Uint32(overflow[0:4])
+			chunk := overflow[4:usable]
+			if remaining := int(payloadSize) - len(payload); len(chunk) > remaining {
+				chunk = chunk[:remaining]
+			}
+			payload = append(payload, chunk...)
+		}
+	}
+
+	return decodeSQLiteRecord(payload)
+}
+
+// decodeSQLiteRecord decodes a record into int64, float64 (as raw bits), string,
+// []byte or nil values
+func decodeSQLiteRecord(payload []byte) ([]interface{}, error) {
+	headerSize, n := sqliteVarint(payload)
+	if int(headerSize) > len(payload) {
+		return nil, fmt.Errorf("corrupt sqlite record")
+	}
+
+	var serialTypes []uint64
+	for offset := n; offset < int(headerSize); {
+		serialType, n := sqliteVarint(payload[offset:])
+		serialTypes = append(serialTypes, serialType)
+		offset += n
+	}
+
+	values := make([]interface{}, 0, len(serialTypes))
+	body := payload[headerSize:]
+	for _, serialType := range serialTypes {
+		var size int
+		switch {
+		case serialType == 0, serialType == 8, serialType == 9:
+			size = 0
+		case serialType <= 4:
+			size = int(serialType)
+		case serialType == 5:
+			size = 6
+		case serialType == 6, serialType == 7:
+			size = 8
+		case serialType >= 12:
+			if serialType-12 > uint64(2*len(body)+1) {
+				return nil, fmt.Errorf("corrupt sqlite record")
+			}
+			size = int(serialType-12) / 2
+		default:
+			return nil, fmt.Errorf("unsupported sqlite serial type %d", serialType)
+		}
+		if size > len(body) {
+			return nil, fmt.Errorf("corrupt sqlite record")
+		}
+		field := body[:size]
+		body = body[size:]
+
+		switch {
+		case serialType == 0:
+			values = append(values, nil)
+		case serialType == 8:
+			values = append(values, int64(0))
+		case serialType == 9:
+			values = append(values, int64(1))
+		case serialType <= 6:
+			// Big-endian two's complement integer of 1-8 bytes
+			var value int64
+			if field[0]&0x80 != 0 {
+				value = -1
+			}
+			for _, b := range field {
+				value = value<<8 | int64(b)
+			}
+			values = append(values, value)
+		case serialType == 7:
+			values = append(values, binary.BigEndian.Uint64(field))
+		case serialType%2 == 0:
+			values = append(values, field)
+		default:
+			values = append(values, string(field))
+		}
+	}
+
+	return values, nil
+}
+
+// sqliteVarint decodes a SQLite big-endian variable-length integer (1-9 bytes)
+func sqliteVarint(b []byte) (uint64, int) {
+	var value uint64
+	for i := 0; i < 9 && i < len(b); i++ {
+		if i == 8 {
+			return value<<8 | uint64(b[i]), 9
+		}
+		value = value<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return value, i + 1
+		}
+	}
+	return value, len(b)
+}
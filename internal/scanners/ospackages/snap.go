@@ -0,0 +1,134 @@
+package ospackages
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// snapMountDirs are where snapd mounts installed snaps, depending on the distribution
+var snapMountDirs = []string{"/snap", "/var/lib/snapd/snap"}
+
+// SnapScanner scans installed snaps from their mounted meta/snap.yaml files
+type SnapScanner struct{}
+
+func (s *SnapScanner) Name() string {
+	return "snap"
+}
+
+func (s *SnapScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the snap scan, stopping early if ctx is cancelled
+func (s *SnapScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
+	if cfg.IsScannerDisabled("snap") {
+		return nil, nil
+	}
+
+	if err := requireLinux("snap"); err != nil {
+		return nil, err
+	}
+
+	var components []scanners.Component
+	found := false
+	seen := make(map[string]bool)
+
+	for _, mountDir := range snapMountDirs {
+		entries, err := os.ReadDir(mountDir)
+		if err != nil {
+			continue
+		}
+		found = true
+
+		for _, entry := range entries {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if !entry.IsDir() || entry.Name() == "bin" || seen[entry.Name()] {
+				continue
+			}
+
+			snapDir := filepath.Join(mountDir, entry.Name())
+			comp, err := readSnap(snapDir)
+			if err != nil {
+				if cfg.Debug {
+					fmt.Printf("Failed to read snap %s: %v\n", snapDir, err)
+				}
+				continue
+			}
+			seen[entry.Name()] = true
+			components = append(components, comp)
+		}
+	}
+
+	if !found {
+		return nil, scanners.NotInstalled("snapd")
+	}
+
+	return components, nil
+}
+
+// snapMeta is the subset of meta/snap.yaml used for the inventory
+type snapMeta struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Summary     string `yaml:"summary"`
+	Type        string `yaml:"type"`
+	Base        string `yaml:"base"`
+	Confinement string `yaml:"confinement"`
+	Grade       string `yaml:"grade"`
+	License     string `yaml:"license"`
+}
+
+// readSnap reads the active revision of a snap; "current" links to the revision directory
+func readSnap(snapDir string) (scanners.Component, error) {
+	currentDir := filepath.Join(snapDir, "current")
+	data, err := os.ReadFile(filepath.Join(currentDir, "meta", "snap.yaml"))
+	if err != nil {
+		return scanners.Component{}, err
+	}
+
+	var meta snapMeta
+	if err := yaml.Unmarshal(data, &meta); err != nil {
+		return scanners.Component{}, err
+	}
+	if meta.Name == "" {
+		meta.Name = filepath.Base(snapDir)
+	}
+
+	comp := scanners.Component{
+		Type:           "application",
+		Name:           meta.Name,
+		Version:        meta.Version,
+		Description:    meta.Summary,
+		PackageManager: "snap",
+		Location:       snapDir,
+		License:        meta.License,
+		Properties:     make(map[string]string),
+	}
+
+	if revision, err := os.Readlink(currentDir); err == nil {
+		comp.Properties["revision"] = filepath.Base(revision)
+	}
+	if meta.Type != "" {
+		comp.Properties["snap_type"] = meta.Type
+	}
+	if meta.Base != "" {
+		comp.Properties["base"] = meta.Base
+	}
+	if meta.Confinement != "" {
+		comp.Properties["confinement"] = meta.Confinement
+	}
+	if meta.Grade != "" {
+		comp.Properties["grade"] = meta.Grade
+	}
+
+	return comp, nil
+}
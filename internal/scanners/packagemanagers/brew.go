@@ -1,9 +1,9 @@
 package packagemanagers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"runtime"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
@@ -18,40 +18,41 @@ func (s *BrewScanner) Name() string {
 }
 
 func (s *BrewScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the brew scan, stopping early if ctx is cancelled
+func (s *BrewScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("brew") {
 		return nil, nil
 	}
 
 	// Homebrew is primarily for macOS and Linux
 	if runtime.GOOS == "windows" {
-		return nil, nil
+		return nil, scanners.Skipped("homebrew is not supported on windows")
 	}
 
 	if !isCommandAvailable("brew") {
 		if cfg.Debug {
 			fmt.Println("brew not found, skipping")
 		}
-		return nil, nil
+		return nil, scanners.NotInstalled("brew")
 	}
 
 	var components []scanners.Component
 
 	// Get installed formulae
-	cmd := exec.Command("brew", "list", "--formula", "--json")
-	output, err := cmd.Output()
+	output, err := RunCommand(ctx, "", "brew", "list", "--formula", "--json")
 	if err != nil {
-		if cfg.Debug {
-			fmt.Printf("brew scan error: %v\n", err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-		return nil, nil
+		return nil, fmt.Errorf("brew scan failed: %w", err)
 	}
 
 	var formulae []brewFormula
 	if err := json.Unmarshal(output, &formulae); err != nil {
-		if cfg.Debug {
-			fmt.Printf("brew parse error: %v\n", err)
-		}
-		return nil, nil
+		return nil, fmt.Errorf("failed to parse brew output: %w", err)
 	}
 
 	for _, formula := range formulae {
@@ -62,19 +63,20 @@ func (s *BrewScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 			PackageManager: "brew",
 			Description:    formula.Desc,
 			Location:       formula.Prefix,
+			License:        formula.License,
 			Properties:     make(map[string]string),
 		}
 
-		if formula.Homepage != "" {
-			comp.Properties["homepage"] = formula.Homepage
-		}
+		comp.AddExternalRef(scanners.RefWebsite, formula.Homepage)
 
 		components = append(components, comp)
 	}
 
 	// Get installed casks
-	cmd = exec.Command("brew", "list", "--cask", "--json")
-	output, err = cmd.Output()
+	output, err = RunCommand(ctx, "", "brew", "list", "--cask", "--json")
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err == nil {
 		var casks []brewCask
 		if err := json.Unmarshal(output, &casks); err == nil {
@@ -102,6 +104,7 @@ type brewFormula struct {
 	Desc     string `json:"desc"`
 	Homepage string `json:"homepage"`
 	Prefix   string `json:"prefix"`
+	License  string `json:"license"` // SPDX expression
 }
 
 type brewCask struct {
@@ -109,4 +112,3 @@ type brewCask struct {
 	Version      string `json:"version"`
 	CaskroomPath string `json:"caskroom_path"`
 }
-
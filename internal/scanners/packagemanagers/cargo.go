@@ -3,12 +3,15 @@ package packagemanagers
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/system"
 )
 
 // CargoScanner scans for Rust cargo installed packages
@@ -19,6 +22,11 @@ func (s *CargoScanner) Name() string {
 }
 
 func (s *CargoScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the cargo scan, stopping early if ctx is cancelled
+func (s *CargoScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("cargo") {
 		return nil, nil
 	}
@@ -27,20 +35,21 @@ func (s *CargoScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 		if cfg.Debug {
 			fmt.Println("cargo not found, skipping")
 		}
-		return nil, nil
+		return nil, scanners.NotInstalled("cargo")
 	}
 
 	var components []scanners.Component
 
-	cmd := exec.Command("cargo", "install", "--list")
-	output, err := cmd.Output()
+	output, err := RunCommand(ctx, "", "cargo", "install", "--list")
 	if err != nil {
-		if cfg.Debug {
-			fmt.Printf("cargo scan error: %v\n", err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-		return nil, nil
+		return nil, fmt.Errorf("cargo scan failed: %w", err)
 	}
 
+	sourceDirs := cargoSourceDirs(cfg)
+
 	scanner := bufio.NewScanner(bytes.NewReader(output))
 	var currentPkg string
 	var currentVersion string
@@ -58,7 +67,7 @@ func (s *CargoScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 			if len(parts) >= 2 {
 				currentPkg = parts[0]
 				currentVersion = strings.TrimPrefix(strings.TrimSuffix(parts[1], ":"), "v")
-				
+
 				comp := scanners.Component{
 					Type:           "library",
 					Name:           currentPkg,
@@ -66,6 +75,7 @@ func (s *CargoScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 					PackageManager: "cargo",
 					Properties:     make(map[string]string),
 				}
+				applyCrateManifest(&comp, sourceDirs)
 				components = append(components, comp)
 			}
 		}
@@ -74,3 +84,88 @@ func (s *CargoScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 	return components, nil
 }
 
+// cargoSourceDirs returns the registry source directories cargo extracts
+// downloaded crates into (~/.cargo/registry/src/<registry>) for every user,
+// plus CARGO_HOME when it is set
+func cargoSourceDirs(cfg *config.Config) []string {
+	var cargoHomes []string
+	if cargoHome := os.Getenv("CARGO_HOME"); cargoHome != "" {
+		cargoHomes = append(cargoHomes, cargoHome)
+	}
+	for _, home := range system.HomeDirs(cfg) {
+		cargoHomes = append(cargoHomes, filepath.Join(home, ".cargo"))
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, cargoHome := range cargoHomes {
+		registries, _ := filepath.Glob(filepath.Join(cargoHome, "registry", "src", "*"))
+		for _, dir := range registries {
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
+// applyCrateManifest sets a crate's license, authors and URLs from the
+// Cargo.toml of its source extracted into one of sourceDirs, if it is on disk
+func applyCrateManifest(comp *scanners.Component, sourceDirs []string) {
+	for _, dir := range sourceDirs {
+		data, err := os.ReadFile(filepath.Join(dir, comp.Name+"-"+comp.Version, "Cargo.toml"))
+		if err != nil {
+			continue
+		}
+		parseCargoTOMLPackage(string(data), comp)
+		return
+	}
+}
+
+// parseCargoTOMLPackage reads the license, authors and URLs of a Cargo.toml's
+// [package] table. Published crates have a normalized manifest, so values are
+// plain strings or arrays of strings on one line.
+func parseCargoTOMLPackage(content string, comp *scanners.Component) {
+	inPackage := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inPackage = line == "[package]"
+			continue
+		}
+		if !inPackage {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		values := parseTOMLStrings(value)
+		if len(values) == 0 {
+			continue
+		}
+
+		switch strings.TrimSpace(key) {
+		case "license":
+			comp.License = values[0]
+		case "authors":
+			// "Name <email>"; addresses are not collected
+			var names []string
+			for _, author := range values {
+				name, _, _ := strings.Cut(author, "<")
+				if name = strings.TrimSpace(name); name != "" {
+					names = append(names, name)
+				}
+			}
+			comp.Author = strings.Join(names, ", ")
+		case "homepage":
+			comp.AddExternalRef(scanners.RefWebsite, values[0])
+		case "repository":
+			comp.AddExternalRef(scanners.RefVCS, values[0])
+		case "documentation":
+			comp.AddExternalRef(scanners.RefDocumentation, values[0])
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package packagemanagers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// CargoLocalScanner scans Rust projects by parsing Cargo.lock
+type CargoLocalScanner struct{}
+
+func (s *CargoLocalScanner) Name() string {
+	return "cargo-local"
+}
+
+func (s *CargoLocalScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the cargo-local scan, stopping early if ctx is cancelled
+func (s *CargoLocalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
+	if cfg.IsScannerDisabled("cargo-local") {
+		return nil, nil
+	}
+
+	projects, err := findProjects(ctx, cfg, "Cargo.lock")
+	if err != nil {
+		return nil, err
+	}
+
+	sourceDirs := cargoSourceDirs(cfg)
+
+	var components []scanners.Component
+	for _, project := range projects {
+		projectPath := project.Path
+		packages, err := scanCargoLock(projectPath, sourceDirs)
+		if err != nil {
+			if cfg.Debug {
+				fmt.Printf("Failed to parse Cargo.lock in %s: %v\n", projectPath, err)
+			}
+			continue
+		}
+		components = append(components, packages...)
+	}
+
+	return components, nil
+}
+
+// cargoLockPackage is a [[package]] entry in Cargo.lock
+type cargoLockPackage struct {
+	Name         string
+	Version      string
+	Source       string
+	Checksum     string
+	Dependencies []string
+}
+
+// parseCargoLock parses the [[package]] tables of a Cargo.lock. Cargo writes the
+// file itself in a fixed layout, so only the subset of TOML it uses is handled:
+// string values and (possibly multi-line) arrays of strings.
+func parseCargoLock(content string) []cargoLockPackage {
+	var packages []cargoLockPackage
+	var current *cargoLockPackage
+	var arrayKey string
+	var arrayValues []string
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Continuation of a multi-line array
+		if arrayKey != "" {
+			if strings.HasPrefix(line, "]") {
+				if current != nil && arrayKey == "dependencies" {
+					current.Dependencies = arrayValues
+				}
+				arrayKey = ""
+				continue
+			}
+			arrayValues = append(arrayValues, parseTOMLStrings(line)...)
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			current = nil
+			if line == "[[package]]" {
+				packages = append(packages, cargoLockPackage{})
+				current = &packages[len(packages)-1]
+			}
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if strings.HasPrefix(value, "[") {
+			values := parseTOMLStrings(value)
+			if strings.HasSuffix(value, "]") {
+				if key == "dependencies" {
+					current.Dependencies = values
+				}
+			} else {
+				arrayKey = key
+				arrayValues = values
+			}
+			continue
+		}
+
+		value = strings.Trim(value, `"`)
+		switch key {
+		case "name":
+			current.Name = value
+		case "version":
+			current.Version = value
+		case "source":
+			current.Source = value
+		case "checksum":
+			current.Checksum = value
+		}
+	}
+
+	return packages
+}
+
+// parseTOMLStrings extracts the double-quoted strings from a line
+func parseTOMLStrings(line string) []string {
+	var values []string
+	for {
+		start := strings.Index(line, `"`)
+		if start == -1 {
+			return values
+		}
+		end := strings.Index(line[start+1:], `"`)
+		if end == -1 {
+			return values
+		}
+		values = append(values, line[start+1:start+1+end])
+		line = line[start+end+2:]
+	}
+}
+
+// scanCargoLock builds the dependency tree of a Cargo project. Packages without a
+// source are the workspace's own crates; their dependencies form the top level and
+// the crates themselves are not reported. Licenses, authors and URLs are read
+// from the crate sources cargo has extracted into sourceDirs.
+func scanCargoLock(projectPath string, sourceDirs []string) ([]scanners.Component, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "Cargo.lock"))
+	if err != nil {
+		return nil, err
+	}
+	packages := parseCargoLock(string(data))
+
+	key := func(pkg cargoLockPackage) string {
+		return pkg.Name + " " + pkg.Version + " " + pkg.Source
+	}
+
+	byName := make(map[string][]cargoLockPackage)
+	for _, pkg := range packages {
+		byName[pkg.Name] = append(byName[pkg.Name], pkg)
+	}
+
+	// Dependency entries are "name", "name version" or "name version (source)",
+	// using the shortest form that is unambiguous
+	resolve := func(dep string) (string, bool) {
+		fields := strings.Fields(dep)
+		if len(fields) == 0 {
+			return "", false
+		}
+		for _, candidate := range byName[fields[0]] {
+			if len(fields) >= 2 && candidate.Version != fields[1] {
+				continue
+			}
+			if len(fields) >= 3 && "("+candidate.Source+")" != strings.Join(fields[2:], " ") {
+				continue
+			}
+			return key(candidate), true
+		}
+		return "", false
+	}
+
+	nodes := make(map[string]scanners.Component)
+	edges := make(map[string][]string)
+	var members []cargoLockPackage
+	for _, pkg := range packages {
+		if pkg.Source == "" {
+			members = append(members, pkg)
+		}
+
+		comp := scanners.Component{
+			Type:           "library",
+			Name:           pkg.Name,
+			Version:        pkg.Version,
+			PackageManager: "cargo",
+			Location:       projectPath,
+			Properties:     localProjectProperties(projectPath, "cargo-local"),
+		}
+		if pkg.Source != "" {
+			comp.Properties["resolved"] = pkg.Source
+			applyCrateManifest(&comp, sourceDirs)
+		} else {
+			comp.Properties["workspace_member"] = "true"
+		}
+		if pkg.Checksum != "" {
+			comp.Properties["checksum"] = pkg.Checksum
+		}
+
+		k := key(pkg)
+		nodes[k] = comp
+		for _, dep := range pkg.Dependencies {
+			if depKey, ok := resolve(dep); ok {
+				edges[k] = append(edges[k], depKey)
+			}
+		}
+		sort.Strings(edges[k])
+	}
+
+	var roots []string
+	seen := make(map[string]bool)
+	for _, member := range members {
+		for _, depKey := range edges[key(member)] {
+			if !seen[depKey] && nodes[depKey].Properties["workspace_member"] == "" {
+				seen[depKey] = true
+				roots = append(roots, depKey)
+			}
+		}
+	}
+	sort.Strings(roots)
+
+	// Anything the workspace crates do not lead to is attached at the top level
+	var all []string
+	for _, k := range sortedKeys(nodes) {
+		if nodes[k].Properties["workspace_member"] == "" {
+			all = append(all, k)
+		}
+	}
+	roots = appendUnreachedRoots(roots, edges, all)
+
+	return scanners.ExpandDependencyGraph(nodes, edges, roots), nil
+}
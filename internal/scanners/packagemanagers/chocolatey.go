@@ -3,8 +3,11 @@ package packagemanagers
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"encoding/xml"
 	"fmt"
-	"os/exec"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -20,30 +23,39 @@ func (s *ChocolateyScanner) Name() string {
 }
 
 func (s *ChocolateyScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the chocolatey scan, stopping early if ctx is cancelled
+func (s *ChocolateyScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("chocolatey") {
 		return nil, nil
 	}
 
 	if runtime.GOOS != "windows" {
-		return nil, nil
+		return nil, scanners.Skipped("chocolatey is only supported on windows")
 	}
 
 	if !isCommandAvailable("choco") {
 		if cfg.Debug {
 			fmt.Println("choco not found, skipping")
 		}
-		return nil, nil
+		return nil, scanners.NotInstalled("choco")
 	}
 
 	var components []scanners.Component
 
-	cmd := exec.Command("choco", "list", "--local-only")
-	output, err := cmd.Output()
+	output, err := RunCommand(ctx, "", "choco", "list", "--local-only")
 	if err != nil {
-		if cfg.Debug {
-			fmt.Printf("chocolatey scan error: %v\n", err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-		return nil, nil
+		return nil, fmt.Errorf("chocolatey scan failed: %w", err)
+	}
+
+	chocoRoot := os.Getenv("ChocolateyInstall")
+	if chocoRoot == "" {
+		chocoRoot = `C:\ProgramData\chocolatey`
 	}
 
 	scanner := bufio.NewScanner(bytes.NewReader(output))
@@ -66,6 +78,7 @@ func (s *ChocolateyScanner) Scan(cfg *config.Config) ([]scanners.Component, erro
 				Name:           parts[0],
 				Version:        parts[1],
 				PackageManager: "chocolatey",
+				License:        readNuspecLicense(filepath.Join(chocoRoot, "lib", parts[0], parts[0]+".nuspec")),
 				Properties:     make(map[string]string),
 			}
 			components = append(components, comp)
@@ -75,3 +88,28 @@ func (s *ChocolateyScanner) Scan(cfg *config.Config) ([]scanners.Component, erro
 	return components, nil
 }
 
+// readNuspecLicense returns the SPDX expression a package's .nuspec declares in
+// <license type="expression">. Most Chocolatey packages only carry a
+// licenseUrl, which says nothing machine-readable about the license.
+func readNuspecLicense(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	var nuspec struct {
+		Metadata struct {
+			License struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"license"`
+		} `xml:"metadata"`
+	}
+	if err := xml.Unmarshal(data, &nuspec); err != nil {
+		return ""
+	}
+	if nuspec.Metadata.License.Type != "expression" {
+		return ""
+	}
+	return strings.TrimSpace(nuspec.Metadata.License.Value)
+}
@@ -0,0 +1,64 @@
+package packagemanagers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// maxCommandOutput caps how much stdout is buffered from a single package manager command.
+// `npm ls --all` on a large monorepo can produce hundreds of megabytes of JSON.
+const maxCommandOutput = 64 << 20 // 64 MiB
+
+// commandWaitDelay is how long to wait for I/O to drain after a command is killed
+const commandWaitDelay = 5 * time.Second
+
+// RunCommand runs an external command and returns its stdout.
+// The command runs in its own process group so that cancelling ctx kills the
+// command and every child it spawned (npm and pip both fork helpers).
+// Like exec.Cmd.Output, the output is returned even if the command exits non-zero.
+func RunCommand(ctx context.Context, dir string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		return killProcessGroup(cmd)
+	}
+	cmd.WaitDelay = commandWaitDelay
+
+	stdout := &cappedBuffer{max: maxCommandOutput}
+	cmd.Stdout = stdout
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if stdout.truncated {
+		return nil, fmt.Errorf("%s output exceeded %d bytes", name, maxCommandOutput)
+	}
+
+	return stdout.Bytes(), err
+}
+
+// cappedBuffer is a bytes.Buffer that silently discards writes past max bytes
+type cappedBuffer struct {
+	bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	remaining := b.max - b.Len()
+	if remaining <= 0 {
+		b.truncated = true
+		return len(p), nil
+	}
+	if len(p) > remaining {
+		b.truncated = true
+		b.Buffer.Write(p[:remaining])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package packagemanagers
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the command in a new process group
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills the command and all of its children
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	// A negative PID signals the whole process group
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
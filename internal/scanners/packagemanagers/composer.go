@@ -1,12 +1,13 @@
 package packagemanagers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strings"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/license"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
 )
 
@@ -18,6 +19,11 @@ func (s *ComposerScanner) Name() string {
 }
 
 func (s *ComposerScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the composer scan, stopping early if ctx is cancelled
+func (s *ComposerScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("composer") {
 		return nil, nil
 	}
@@ -26,28 +32,26 @@ func (s *ComposerScanner) Scan(cfg *config.Config) ([]scanners.Component, error)
 		if cfg.Debug {
 			fmt.Println("composer not found, skipping")
 		}
-		return nil, nil
+		return nil, scanners.NotInstalled("composer")
 	}
 
 	var components []scanners.Component
 
-	cmd := exec.Command("composer", "global", "show", "--format=json")
-	output, err := cmd.Output()
+	output, err := RunCommand(ctx, "", "composer", "global", "show", "--format=json")
 	if err != nil {
-		if cfg.Debug {
-			fmt.Printf("composer scan error: %v\n", err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-		return nil, nil
+		return nil, fmt.Errorf("composer scan failed: %w", err)
 	}
 
 	var result composerShowResult
 	if err := json.Unmarshal(output, &result); err != nil {
-		if cfg.Debug {
-			fmt.Printf("composer parse error: %v\n", err)
-		}
-		return nil, nil
+		return nil, fmt.Errorf("failed to parse composer output: %w", err)
 	}
 
+	licenses := composerGlobalLicenses(ctx, cfg)
+
 	for _, pkg := range result.Installed {
 		comp := scanners.Component{
 			Type:           "library",
@@ -55,6 +59,7 @@ func (s *ComposerScanner) Scan(cfg *config.Config) ([]scanners.Component, error)
 			Version:        pkg.Version,
 			PackageManager: "composer",
 			Description:    pkg.Description,
+			License:        licenses[strings.ToLower(pkg.Name)],
 			Properties:     make(map[string]string),
 		}
 
@@ -68,6 +73,38 @@ func (s *ComposerScanner) Scan(cfg *config.Config) ([]scanners.Component, error)
 	return components, nil
 }
 
+// composerGlobalLicenses returns the licenses of the global packages keyed by
+// lowercased package name. "composer show" does not include them, so they come
+// from "composer global licenses".
+func composerGlobalLicenses(ctx context.Context, cfg *config.Config) map[string]string {
+	licenses := make(map[string]string)
+
+	output, err := RunCommand(ctx, "", "composer", "global", "licenses", "--format=json")
+	if err != nil {
+		if cfg.Debug {
+			fmt.Printf("composer licenses failed: %v\n", err)
+		}
+		return licenses
+	}
+
+	var result struct {
+		Dependencies map[string]struct {
+			License []string `json:"license"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		if cfg.Debug {
+			fmt.Printf("failed to parse composer licenses output: %v\n", err)
+		}
+		return licenses
+	}
+
+	for name, dep := range result.Dependencies {
+		licenses[strings.ToLower(name)] = license.JoinAny(dep.License)
+	}
+	return licenses
+}
+
 type composerShowResult struct {
 	Installed []composerPackage `json:"installed"`
 }
@@ -78,4 +115,3 @@ type composerPackage struct {
 	Description string   `json:"description"`
 	Keywords    []string `json:"keywords"`
 }
-
@@ -0,0 +1,186 @@
+package packagemanagers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/license"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// ComposerLocalScanner scans PHP projects by parsing composer.lock
+type ComposerLocalScanner struct{}
+
+func (s *ComposerLocalScanner) Name() string {
+	return "composer-local"
+}
+
+func (s *ComposerLocalScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the composer-local scan, stopping early if ctx is cancelled
+func (s *ComposerLocalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
+	if cfg.IsScannerDisabled("composer-local") {
+		return nil, nil
+	}
+
+	projects, err := findProjects(ctx, cfg, "composer.lock")
+	if err != nil {
+		return nil, err
+	}
+
+	var components []scanners.Component
+	for _, project := range projects {
+		projectPath := project.Path
+		packages, err := scanComposerLock(projectPath)
+		if err != nil {
+			if cfg.Debug {
+				fmt.Printf("Failed to parse composer.lock in %s: %v\n", projectPath, err)
+			}
+			continue
+		}
+		components = append(components, packages...)
+	}
+
+	return components, nil
+}
+
+// composerLock is the structure of composer.lock
+type composerLock struct {
+	Packages    []composerLockPackage `json:"packages"`
+	PackagesDev []composerLockPackage `json:"packages-dev"`
+}
+
+type composerLockPackage struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	Type        string            `json:"type"`
+	Description string            `json:"description"`
+	License     []string          `json:"license"`
+	Require     map[string]string `json:"require"`
+	Source      *struct {
+		Type      string `json:"type"`
+		URL       string `json:"url"`
+		Reference string `json:"reference"`
+	} `json:"source"`
+	Dist *struct {
+		URL    string `json:"url"`
+		Shasum string `json:"shasum"`
+	} `json:"dist"`
+	Homepage string `json:"homepage"`
+	Authors  []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	Support struct {
+		Issues string `json:"issues"`
+		Source string `json:"source"`
+		Docs   string `json:"docs"`
+	} `json:"support"`
+}
+
+// composerManifest holds the dependency sections of composer.json
+type composerManifest struct {
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+}
+
+// scanComposerLock builds the dependency tree of a Composer project. The packages
+// required by composer.json form the top level.
+func scanComposerLock(projectPath string) ([]scanners.Component, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "composer.lock"))
+	if err != nil {
+		return nil, err
+	}
+
+	var lock composerLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]scanners.Component)
+	edges := make(map[string][]string)
+
+	addPackages := func(packages []composerLockPackage, dev bool) {
+		for _, pkg := range packages {
+			// Composer package names are case-insensitive
+			key := strings.ToLower(pkg.Name)
+
+			comp := scanners.Component{
+				Type:           "library",
+				Name:           pkg.Name,
+				Version:        pkg.Version,
+				Description:    pkg.Description,
+				PackageManager: "composer",
+				Location:       projectPath,
+				License:        license.JoinAny(pkg.License),
+				Properties:     localProjectProperties(projectPath, "composer-local"),
+			}
+			if dev {
+				comp.Properties["dev_dependency"] = "true"
+			}
+			if pkg.Type != "" && pkg.Type != "library" {
+				comp.Properties["package_type"] = pkg.Type
+			}
+			if pkg.Source != nil {
+				if pkg.Source.URL != "" {
+					comp.Properties["resolved"] = pkg.Source.URL
+				}
+				if pkg.Source.Reference != "" {
+					comp.Properties["source_reference"] = pkg.Source.Reference
+				}
+			}
+			if pkg.Dist != nil && pkg.Dist.Shasum != "" {
+				comp.Properties["shasum"] = pkg.Dist.Shasum
+			}
+
+			var authors []string
+			for _, author := range pkg.Authors {
+				if author.Name != "" {
+					authors = append(authors, author.Name)
+				}
+			}
+			comp.Author = strings.Join(authors, ", ")
+			comp.AddExternalRef(scanners.RefWebsite, pkg.Homepage)
+			comp.AddExternalRef(scanners.RefVCS, pkg.Support.Source)
+			comp.AddExternalRef(scanners.RefIssueTracker, pkg.Support.Issues)
+			comp.AddExternalRef(scanners.RefDocumentation, pkg.Support.Docs)
+			if pkg.Dist != nil {
+				comp.AddExternalRef(scanners.RefDistribution, pkg.Dist.URL)
+			}
+
+			nodes[key] = comp
+			for dep := range pkg.Require {
+				edges[key] = append(edges[key], strings.ToLower(dep))
+			}
+			sort.Strings(edges[key])
+		}
+	}
+	addPackages(lock.Packages, false)
+	addPackages(lock.PackagesDev, true)
+
+	var roots []string
+	if manifestData, err := os.ReadFile(filepath.Join(projectPath, "composer.json")); err == nil {
+		var manifest composerManifest
+		if err := json.Unmarshal(manifestData, &manifest); err == nil {
+			// Platform requirements (php, ext-*, lib-*) are not packages and drop out here
+			for _, name := range sortedKeys(manifest.Require, manifest.RequireDev) {
+				if _, ok := nodes[strings.ToLower(name)]; ok {
+					roots = append(roots, strings.ToLower(name))
+				}
+			}
+		}
+	}
+
+	// Attach anything composer.json does not lead to (or every package when it is
+	// missing) at the top level so no locked package is dropped
+	roots = appendUnreachedRoots(roots, edges, sortedKeys(nodes))
+
+	return scanners.ExpandDependencyGraph(nodes, edges, roots), nil
+}
@@ -0,0 +1,231 @@
+package packagemanagers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/system"
+)
+
+// CondaScanner scans conda environments by reading their conda-meta records
+type CondaScanner struct{}
+
+func (s *CondaScanner) Name() string {
+	return "conda"
+}
+
+func (s *CondaScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the conda scan, stopping early if ctx is cancelled
+func (s *CondaScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
+	if cfg.IsScannerDisabled("conda") {
+		return nil, nil
+	}
+
+	envs := findCondaEnvironments(cfg)
+	if len(envs) == 0 {
+		return nil, scanners.NotInstalled("conda")
+	}
+
+	var components []scanners.Component
+	for _, env := range envs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if cfg.Debug {
+			fmt.Printf("Found conda environment at: %s\n", env)
+		}
+
+		envComponents, err := scanCondaEnvironment(env)
+		if err != nil {
+			if cfg.Debug {
+				fmt.Printf("Failed to read conda environment %s: %v\n", env, err)
+			}
+			continue
+		}
+		components = append(components, envComponents...)
+	}
+
+	return components, nil
+}
+
+// condaRecord is a package record from <prefix>/conda-meta/<name>-<version>-<build>.json
+type condaRecord struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Build       string   `json:"build"`
+	BuildNumber int      `json:"build_number"`
+	Channel     string   `json:"channel"`
+	Subdir      string   `json:"subdir"`
+	License     string   `json:"license"`
+	URL         string   `json:"url"`
+	Depends     []string `json:"depends"`
+}
+
+// findCondaEnvironments returns the prefixes of conda installations and their environments
+func findCondaEnvironments(cfg *config.Config) []string {
+	var bases []string
+	var envFiles []string
+
+	installNames := []string{"anaconda3", "miniconda3", "miniforge3", "mambaforge", "micromamba"}
+
+	for _, home := range system.HomeDirs(cfg) {
+		for _, name := range installNames {
+			bases = append(bases, filepath.Join(home, name))
+		}
+		bases = append(bases, filepath.Join(home, ".conda"))
+		// conda records every environment it creates here
+		envFiles = append(envFiles, filepath.Join(home, ".conda", "environments.txt"))
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		programData := os.Getenv("ProgramData")
+		if programData == "" {
+			programData = `C:\ProgramData`
+		}
+		for _, name := range installNames {
+			bases = append(bases, filepath.Join(programData, name))
+		}
+	default:
+		for _, root := range []string{"/opt", "/usr/local"} {
+			for _, name := range installNames {
+				bases = append(bases, filepath.Join(root, name))
+			}
+		}
+		bases = append(bases,
+			"/opt/conda",
+			"/opt/homebrew/Caskroom/miniconda/base",
+			"/opt/homebrew/Caskroom/miniforge/base",
+		)
+	}
+
+	var candidates []string
+	for _, base := range bases {
+		candidates = append(candidates, base)
+		if entries, err := os.ReadDir(filepath.Join(base, "envs")); err == nil {
+			for _, entry := range entries {
+				if entry.IsDir() {
+					candidates = append(candidates, filepath.Join(base, "envs", entry.Name()))
+				}
+			}
+		}
+	}
+	for _, envFile := range envFiles {
+		candidates = append(candidates, readCondaEnvironmentsFile(envFile)...)
+	}
+
+	seen := make(map[string]bool)
+	var envs []string
+	for _, candidate := range candidates {
+		resolved := candidate
+		if real, err := filepath.EvalSymlinks(candidate); err == nil {
+			resolved = real
+		}
+		if seen[resolved] {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(candidate, "conda-meta")); err == nil && info.IsDir() {
+			seen[resolved] = true
+			envs = append(envs, candidate)
+		}
+	}
+
+	return envs
+}
+
+// readCondaEnvironmentsFile reads the environment prefixes listed in ~/.conda/environments.txt
+func readCondaEnvironmentsFile(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var envs []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			envs = append(envs, line)
+		}
+	}
+	return envs
+}
+
+// scanCondaEnvironment reads every package record in an environment's conda-meta directory
+func scanCondaEnvironment(prefix string) ([]scanners.Component, error) {
+	files, err := filepath.Glob(filepath.Join(prefix, "conda-meta", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[string]condaRecord)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		var record condaRecord
+		if err := json.Unmarshal(data, &record); err != nil || record.Name == "" {
+			continue
+		}
+		records[record.Name] = record
+	}
+
+	nodes := make(map[string]scanners.Component)
+	edges := make(map[string][]string)
+	for name, record := range records {
+		comp := scanners.Component{
+			Type:           "library",
+			Name:           record.Name,
+			Version:        record.Version,
+			PackageManager: "conda",
+			Location:       prefix,
+			Properties:     make(map[string]string),
+		}
+
+		comp.Properties["conda_env"] = prefix
+		if record.Build != "" {
+			comp.Properties["build"] = record.Build
+		}
+		if record.Channel != "" {
+			comp.Properties["channel"] = record.Channel
+		}
+		if record.Subdir != "" {
+			comp.Properties["subdir"] = record.Subdir
+		}
+		comp.License = record.License
+		if record.URL != "" {
+			comp.Properties["install_source"] = record.URL
+			comp.AddExternalRef(scanners.RefDistribution, record.URL)
+		}
+		if len(record.Depends) > 0 {
+			comp.Properties["requires"] = strings.Join(record.Depends, ", ")
+		}
+
+		// depends entries are "<name> <version spec> [build]"
+		for _, dep := range record.Depends {
+			depName, _, _ := strings.Cut(strings.TrimSpace(dep), " ")
+			edges[name] = append(edges[name], depName)
+		}
+		sort.Strings(edges[name])
+
+		nodes[name] = comp
+	}
+
+	return scanners.ExpandDependencyGraph(nodes, edges, sortedKeys(records)), nil
+}
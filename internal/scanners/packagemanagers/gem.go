@@ -3,11 +3,15 @@ package packagemanagers
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/license"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
 )
 
@@ -19,6 +23,11 @@ func (s *GemScanner) Name() string {
 }
 
 func (s *GemScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the gem scan, stopping early if ctx is cancelled
+func (s *GemScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("gem") {
 		return nil, nil
 	}
@@ -27,20 +36,21 @@ func (s *GemScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 		if cfg.Debug {
 			fmt.Println("gem not found, skipping")
 		}
-		return nil, nil
+		return nil, scanners.NotInstalled("gem")
 	}
 
 	var components []scanners.Component
 
-	cmd := exec.Command("gem", "list", "--local")
-	output, err := cmd.Output()
+	output, err := RunCommand(ctx, "", "gem", "list", "--local")
 	if err != nil {
-		if cfg.Debug {
-			fmt.Printf("gem scan error: %v\n", err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-		return nil, nil
+		return nil, fmt.Errorf("gem scan failed: %w", err)
 	}
 
+	specDirs := gemSpecDirs(ctx, "")
+
 	scanner := bufio.NewScanner(bytes.NewReader(output))
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -59,13 +69,16 @@ func (s *GemScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 		versions := strings.Split(versionsStr, ",")
 
 		for _, ver := range versions {
+			// Default gems are listed as "default: 2.0.0"
+			ver = strings.TrimPrefix(strings.TrimSpace(ver), "default: ")
 			comp := scanners.Component{
 				Type:           "library",
 				Name:           name,
-				Version:        strings.TrimSpace(ver),
+				Version:        ver,
 				PackageManager: "gem",
 				Properties:     make(map[string]string),
 			}
+			applyGemspec(&comp, specDirs)
 			components = append(components, comp)
 		}
 	}
@@ -73,3 +86,92 @@ func (s *GemScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 	return components, nil
 }
 
+// gemspecLine matches an attribute assignment in an installed gemspec, which
+// RubyGems writes in a fixed form such as `s.licenses = ["MIT".freeze]`
+var gemspecLine = regexp.MustCompile(`^\s*\w+\.(\w+)\s*=\s*(.+)$`)
+
+// gemspecString matches a quoted string in a gemspec assignment
+var gemspecString = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+
+// gemMetadataURLs maps the URL keys of a gemspec's metadata hash to external
+// reference types
+var gemMetadataURLs = map[string]string{
+	"homepage_uri":      scanners.RefWebsite,
+	"source_code_uri":   scanners.RefVCS,
+	"bug_tracker_uri":   scanners.RefIssueTracker,
+	"documentation_uri": scanners.RefDocumentation,
+}
+
+// gemSpecDirs returns the specifications directories of every gem path known to
+// the gem command run in dir
+func gemSpecDirs(ctx context.Context, dir string) []string {
+	output, err := RunCommand(ctx, dir, "gem", "environment", "gempath")
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	for _, path := range filepath.SplitList(strings.TrimSpace(string(output))) {
+		if path != "" {
+			dirs = append(dirs, filepath.Join(path, "specifications"))
+		}
+	}
+	return dirs
+}
+
+// applyGemspec sets a gem's license, authors and URLs from its installed
+// gemspec, if one is found in specDirs
+func applyGemspec(comp *scanners.Component, specDirs []string) {
+	for _, dir := range specDirs {
+		paths := []string{
+			filepath.Join(dir, comp.Name+"-"+comp.Version+".gemspec"),
+			filepath.Join(dir, "default", comp.Name+"-"+comp.Version+".gemspec"),
+		}
+		// Platform gems carry the platform after the version ("nokogiri-1.15.4-x86_64-linux")
+		platform, _ := filepath.Glob(filepath.Join(dir, comp.Name+"-"+comp.Version+"-*.gemspec"))
+		paths = append(paths, platform...)
+
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			parseGemspec(string(data), comp)
+			return
+		}
+	}
+}
+
+// parseGemspec reads the license, authors, homepage and metadata URLs of a
+// gemspec. A gem listing several licenses may be used under any of them.
+func parseGemspec(gemspec string, comp *scanners.Component) {
+	for _, line := range strings.Split(gemspec, "\n") {
+		match := gemspecLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		var values []string
+		for _, quoted := range gemspecString.FindAllStringSubmatch(match[2], -1) {
+			values = append(values, quoted[1]+quoted[2])
+		}
+
+		switch match[1] {
+		case "license", "licenses":
+			comp.License = license.JoinAny(values)
+		case "authors":
+			comp.Author = strings.Join(values, ", ")
+		case "homepage":
+			if len(values) > 0 {
+				comp.AddExternalRef(scanners.RefWebsite, values[0])
+			}
+		case "metadata":
+			// { "key" => "value", ... }: the quoted strings alternate key, value
+			for i := 0; i+1 < len(values); i += 2 {
+				if refType, ok := gemMetadataURLs[values[i]]; ok {
+					comp.AddExternalRef(refType, values[i+1])
+				}
+			}
+		}
+	}
+}
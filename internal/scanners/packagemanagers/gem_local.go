@@ -2,9 +2,9 @@ package packagemanagers
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -20,6 +20,11 @@ func (s *GemLocalScanner) Name() string {
 }
 
 func (s *GemLocalScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the gem-local scan, stopping early if ctx is cancelled
+func (s *GemLocalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("gem-local") {
 		return nil, nil
 	}
@@ -29,61 +34,33 @@ func (s *GemLocalScanner) Scan(cfg *config.Config) ([]scanners.Component, error)
 		if cfg.Debug {
 			fmt.Println("bundle not found, skipping local gem scan")
 		}
-		return nil, nil
+		return nil, scanners.NotInstalled("bundle")
 	}
 
 	var components []scanners.Component
 
-	// Get list of potential project directories
-	projectDirs := getProjectDirectories(cfg)
+	projects, err := findProjects(ctx, cfg, "Gemfile")
+	if err != nil {
+		return nil, err
+	}
 
-	for _, baseDir := range projectDirs {
-		if cfg.Verbose {
-			fmt.Printf("Scanning for Ruby/Bundler projects in: %s\n", baseDir)
+	for _, project := range projects {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
 
-		// Find all Gemfile directories
-		err := filepath.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil // Skip directories we can't access
-			}
-
-			// Skip if this path is excluded
-			if cfg.IsPathExcluded(path) {
-				if info.IsDir() {
-					return filepath.SkipDir
-				}
-				return nil
-			}
-
-			// Look for Gemfile
-			if !info.IsDir() && info.Name() == "Gemfile" {
-				projectPath := filepath.Dir(path)
-				if cfg.Debug {
-					fmt.Printf("Found Ruby project at: %s\n", projectPath)
-				}
-
-				packages := scanBundlerProject(projectPath, cfg)
-				components = append(components, packages...)
-			}
-
-			// Skip vendor directories
-			if info.IsDir() && info.Name() == "vendor" {
-				return filepath.SkipDir
-			}
-
-			return nil
-		})
-
-		if err != nil && cfg.Debug {
-			fmt.Printf("Error walking directory %s: %v\n", baseDir, err)
+		if cfg.Debug {
+			fmt.Printf("Found Ruby project at: %s\n", project.Path)
 		}
+
+		packages := scanBundlerProject(ctx, project.Path, cfg)
+		components = append(components, packages...)
 	}
 
 	return components, nil
 }
 
-func scanBundlerProject(projectPath string, cfg *config.Config) []scanners.Component {
+func scanBundlerProject(ctx context.Context, projectPath string, cfg *config.Config) []scanners.Component {
 	var components []scanners.Component
 
 	// Check if Gemfile.lock exists
@@ -100,10 +77,7 @@ func scanBundlerProject(projectPath string, cfg *config.Config) []scanners.Compo
 	gemMap := make(map[string]*scanners.Component)
 
 	// Run bundle list
-	cmd := exec.Command("bundle", "list")
-	cmd.Dir = projectPath
-
-	output, err := cmd.Output()
+	output, err := RunCommand(ctx, projectPath, "bundle", "list")
 	if err != nil {
 		if cfg.Debug {
 			fmt.Printf("bundle list failed for %s: %v\n", projectPath, err)
@@ -111,6 +85,12 @@ func scanBundlerProject(projectPath string, cfg *config.Config) []scanners.Compo
 		return nil
 	}
 
+	// Gems are installed either into the gem paths or, with "bundle config set
+	// path", under the project's vendor/bundle
+	specDirs := gemSpecDirs(ctx, projectPath)
+	vendored, _ := filepath.Glob(filepath.Join(projectPath, "vendor", "bundle", "ruby", "*", "specifications"))
+	specDirs = append(specDirs, vendored...)
+
 	// Parse bundle list output
 	scanner := bufio.NewScanner(strings.NewReader(string(output)))
 	for scanner.Scan() {
@@ -142,6 +122,7 @@ func scanBundlerProject(projectPath string, cfg *config.Config) []scanners.Compo
 			comp.Properties["install_type"] = "local"
 			comp.Properties["project_path"] = projectPath
 			comp.Properties["source"] = "gem-local"
+			applyGemspec(&comp, specDirs)
 
 			gemMap[name] = &comp
 		}
@@ -164,28 +145,28 @@ func scanBundlerProject(projectPath string, cfg *config.Config) []scanners.Compo
 // parseGemfileLockDependencies parses Gemfile.lock to extract dependency relationships
 func parseGemfileLockDependencies(lockfileContent string, gemMap map[string]*scanners.Component) {
 	scanner := bufio.NewScanner(strings.NewReader(lockfileContent))
-	
+
 	var currentGem string
 	inSpecsSection := false
-	
+
 	for scanner.Scan() {
 		line := scanner.Text()
-		
+
 		// Look for the specs section
 		if strings.Contains(line, "specs:") {
 			inSpecsSection = true
 			continue
 		}
-		
+
 		if !inSpecsSection {
 			continue
 		}
-		
+
 		// End of specs section
 		if line != "" && !strings.HasPrefix(line, "    ") && !strings.HasPrefix(line, "  ") {
 			break
 		}
-		
+
 		// Gem declaration: "    gem_name (version)"
 		if strings.HasPrefix(line, "    ") && !strings.HasPrefix(line, "      ") {
 			parts := strings.Fields(strings.TrimSpace(line))
@@ -194,14 +175,14 @@ func parseGemfileLockDependencies(lockfileContent string, gemMap map[string]*sca
 			}
 			continue
 		}
-		
+
 		// Dependency: "      dep_gem (>= version)"
 		if strings.HasPrefix(line, "      ") && currentGem != "" {
 			depLine := strings.TrimSpace(line)
 			parts := strings.Fields(depLine)
 			if len(parts) >= 1 {
 				depName := parts[0]
-				
+
 				// Add this dependency to the current gem
 				if gemComp, exists := gemMap[currentGem]; exists {
 					if depComp, depExists := gemMap[depName]; depExists {
@@ -212,4 +193,3 @@ func parseGemfileLockDependencies(lockfileContent string, gemMap map[string]*sca
 		}
 	}
 }
-
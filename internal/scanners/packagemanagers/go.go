@@ -1,17 +1,19 @@
 package packagemanagers
 
 import (
-	"bufio"
-	"bytes"
+	"context"
+	"debug/buildinfo"
 	"fmt"
-	"os/exec"
-	"strings"
+	"os"
+	"path/filepath"
+	"runtime/debug"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/system"
 )
 
-// GoScanner scans for Go installed tools
+// GoScanner scans for installed Go binaries using the build info embedded in each binary
 type GoScanner struct{}
 
 func (s *GoScanner) Name() string {
@@ -19,51 +21,166 @@ func (s *GoScanner) Name() string {
 }
 
 func (s *GoScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the go scan, stopping early if ctx is cancelled
+func (s *GoScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("go") {
 		return nil, nil
 	}
 
-	if !isCommandAvailable("go") {
+	binDirs := findGoBinDirs(cfg)
+	if len(binDirs) == 0 {
 		if cfg.Debug {
-			fmt.Println("go not found, skipping")
+			fmt.Println("No Go bin directories found, skipping")
 		}
-		return nil, nil
+		return nil, scanners.NotInstalled("go")
 	}
 
 	var components []scanners.Component
+	seen := make(map[string]bool)
 
-	// List installed Go tools (go list will show installed binaries)
-	cmd := exec.Command("go", "list", "-m", "all")
-	output, err := cmd.Output()
-	if err != nil {
-		if cfg.Debug {
-			fmt.Printf("go scan error: %v\n", err)
+	for _, binDir := range binDirs {
+		entries, err := os.ReadDir(binDir)
+		if err != nil {
+			if cfg.Debug {
+				fmt.Printf("Failed to read %s: %v\n", binDir, err)
+			}
+			continue
+		}
+
+		for _, entry := range entries {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if entry.IsDir() {
+				continue
+			}
+
+			binaryPath := filepath.Join(binDir, entry.Name())
+			resolved := binaryPath
+			if real, err := filepath.EvalSymlinks(binaryPath); err == nil {
+				resolved = real
+			}
+			if seen[resolved] {
+				continue
+			}
+			seen[resolved] = true
+
+			// Files without build info (scripts, non-Go binaries) are skipped
+			info, err := buildinfo.ReadFile(binaryPath)
+			if err != nil {
+				if cfg.Debug {
+					fmt.Printf("No Go build info in %s: %v\n", binaryPath, err)
+				}
+				continue
+			}
+
+			components = append(components, goBinaryComponent(binaryPath, info))
 		}
-		return nil, nil
 	}
 
-	scanner := bufio.NewScanner(bytes.NewReader(output))
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.Fields(line)
-		if len(parts) < 2 {
-			continue
+	return components, nil
+}
+
+// findGoBinDirs returns the existing $GOBIN, $GOPATH/bin and per-user ~/go/bin directories
+func findGoBinDirs(cfg *config.Config) []string {
+	var candidates []string
+
+	if gobin := os.Getenv("GOBIN"); gobin != "" {
+		candidates = append(candidates, gobin)
+	}
+	for _, gopath := range filepath.SplitList(os.Getenv("GOPATH")) {
+		if gopath != "" {
+			candidates = append(candidates, filepath.Join(gopath, "bin"))
 		}
+	}
+	for _, home := range system.HomeDirs(cfg) {
+		candidates = append(candidates, filepath.Join(home, "go", "bin"))
+	}
 
-		name := parts[0]
-		version := parts[1]
+	return globDirs(candidates)
+}
+
+// goBinaryComponent builds a component for the main module of a Go binary with
+// every module linked into it as a dependency. Build info only records the flat
+// list of linked modules, so dependencies are attached directly to the main module.
+func goBinaryComponent(binaryPath string, info *buildinfo.BuildInfo) scanners.Component {
+	name := info.Main.Path
+	if name == "" {
+		// Binaries built outside module mode only record the main package path
+		name = info.Path
+	}
+
+	comp := scanners.Component{
+		Type:           "application",
+		Name:           name,
+		Version:        info.Main.Version,
+		PackageManager: "go",
+		Location:       binaryPath,
+		Properties:     make(map[string]string),
+	}
 
-		comp := scanners.Component{
-			Type:           "library",
-			Name:           name,
-			Version:        version,
-			PackageManager: "go",
-			Properties:     make(map[string]string),
+	comp.Properties["binary"] = filepath.Base(binaryPath)
+	comp.Properties["go_version"] = info.GoVersion
+	if info.Path != "" && info.Path != name {
+		comp.Properties["package_path"] = info.Path
+	}
+	if info.Main.Sum != "" {
+		comp.Properties["module_sum"] = info.Main.Sum
+	}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs":
+			comp.Properties["vcs"] = setting.Value
+		case "vcs.revision":
+			comp.Properties["vcs_revision"] = setting.Value
+		case "vcs.time":
+			comp.Properties["vcs_time"] = setting.Value
+		case "vcs.modified":
+			comp.Properties["vcs_modified"] = setting.Value
+		case "GOOS":
+			comp.Properties["goos"] = setting.Value
+		case "GOARCH":
+			comp.Properties["goarch"] = setting.Value
 		}
+	}
 
-		components = append(components, comp)
+	for _, dep := range info.Deps {
+		comp.Dependencies = append(comp.Dependencies, goModuleComponent(dep, binaryPath))
 	}
 
-	return components, nil
+	return comp
 }
 
+// goModuleComponent builds a component for a module linked into a Go binary
+func goModuleComponent(mod *debug.Module, binaryPath string) scanners.Component {
+	comp := scanners.Component{
+		Type:           "library",
+		Name:           mod.Path,
+		Version:        mod.Version,
+		PackageManager: "go",
+		Location:       binaryPath,
+		Properties:     make(map[string]string),
+	}
+
+	if mod.Sum != "" {
+		comp.Properties["module_sum"] = mod.Sum
+	}
+
+	// A replace directive means the code actually linked came from elsewhere
+	if mod.Replace != nil {
+		replacement := mod.Replace.Path
+		if mod.Replace.Version != "" {
+			replacement += "@" + mod.Replace.Version
+		}
+		comp.Properties["replaced_by"] = replacement
+		if mod.Replace.Sum != "" {
+			comp.Properties["module_sum"] = mod.Replace.Sum
+		}
+	}
+
+	return comp
+}
@@ -0,0 +1,331 @@
+package packagemanagers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/system"
+)
+
+// GoLocalScanner scans Go module projects by parsing go.mod and go.sum
+type GoLocalScanner struct{}
+
+func (s *GoLocalScanner) Name() string {
+	return "go-local"
+}
+
+func (s *GoLocalScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the go-local scan, stopping early if ctx is cancelled
+func (s *GoLocalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
+	if cfg.IsScannerDisabled("go-local") {
+		return nil, nil
+	}
+
+	projects, err := findProjects(ctx, cfg, "go.mod")
+	if err != nil {
+		return nil, err
+	}
+
+	homes := system.HomeDirs(cfg)
+
+	var components []scanners.Component
+	for _, project := range projects {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		projectPath := project.Path
+		packages, err := scanGoModule(projectPath, goModCacheDirs(homes, projectPath))
+		if err != nil {
+			if cfg.Debug {
+				fmt.Printf("Failed to parse go.mod in %s: %v\n", projectPath, err)
+			}
+			continue
+		}
+		components = append(components, packages...)
+	}
+
+	return components, nil
+}
+
+// goModFile holds the parts of a go.mod file relevant to an inventory
+type goModFile struct {
+	Module    string
+	GoVersion string
+	Requires  []goRequire
+	Replaces  map[string]goModuleVersion // keyed by "path" or "path@version"
+}
+
+type goRequire struct {
+	goModuleVersion
+	Indirect bool
+}
+
+type goModuleVersion struct {
+	Path    string
+	Version string
+}
+
+// parseGoMod parses go.mod directives; only module, go, require and replace are kept
+func parseGoMod(content string) *goModFile {
+	mod := &goModFile{Replaces: make(map[string]goModuleVersion)}
+
+	block := ""
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		comment := ""
+		if idx := strings.Index(line, "//"); idx != -1 {
+			comment = strings.TrimSpace(line[idx+2:])
+			line = strings.TrimSpace(line[:idx])
+		}
+		if line == "" {
+			continue
+		}
+
+		if block != "" {
+			if line == ")" {
+				block = ""
+				continue
+			}
+			mod.addDirective(block, strings.Fields(line), comment)
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == "(" {
+			block = fields[0]
+			continue
+		}
+		mod.addDirective(fields[0], fields[1:], comment)
+	}
+
+	return mod
+}
+
+func (m *goModFile) addDirective(verb string, args []string, comment string) {
+	for i, arg := range args {
+		args[i] = strings.Trim(arg, `"`+"`")
+	}
+
+	switch verb {
+	case "module":
+		if len(args) > 0 {
+			m.Module = args[0]
+		}
+	case "go":
+		if len(args) > 0 {
+			m.GoVersion = args[0]
+		}
+	case "require":
+		if len(args) >= 2 {
+			m.Requires = append(m.Requires, goRequire{
+				goModuleVersion: goModuleVersion{Path: args[0], Version: args[1]},
+				Indirect:        strings.HasPrefix(comment, "indirect"),
+			})
+		}
+	case "replace":
+		// old [version] => new [version]
+		arrow := -1
+		for i, arg := range args {
+			if arg == "=>" {
+				arrow = i
+			}
+		}
+		if arrow < 1 || arrow == len(args)-1 {
+			return
+		}
+		key := args[0]
+		if arrow == 2 {
+			key += "@" + args[1]
+		}
+		target := goModuleVersion{Path: args[arrow+1]}
+		if len(args) > arrow+2 {
+			target.Version = args[arrow+2]
+		}
+		m.Replaces[key] = target
+	}
+}
+
+// replacement returns the replace directive that applies to a module, if any
+func (m *goModFile) replacement(mod goModuleVersion) (goModuleVersion, bool) {
+	if target, ok := m.Replaces[mod.Path+"@"+mod.Version]; ok {
+		return target, true
+	}
+	target, ok := m.Replaces[mod.Path]
+	return target, ok
+}
+
+// parseGoSum returns the module hash recorded for each "path@version" in go.sum
+func parseGoSum(content string) map[string]string {
+	sums := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		sums[fields[0]+"@"+fields[1]] = fields[2]
+	}
+	return sums
+}
+
+// scanGoModule builds the dependency tree of a Go module project. go.mod lists every
+// module in the build list but not who requires whom, so edges are read from the
+// dependencies' own go.mod files in the local module cache when available. Modules
+// that cannot be placed in the tree are attached at the top level.
+func scanGoModule(projectPath string, modCaches []string) ([]scanners.Component, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	mod := parseGoMod(string(data))
+
+	sums := make(map[string]string)
+	if sumData, err := os.ReadFile(filepath.Join(projectPath, "go.sum")); err == nil {
+		sums = parseGoSum(string(sumData))
+	}
+
+	nodes := make(map[string]scanners.Component)
+	selected := make(map[string]string) // module path -> selected version
+	for _, req := range mod.Requires {
+		selected[req.Path] = req.Version
+
+		comp := scanners.Component{
+			Type:           "library",
+			Name:           req.Path,
+			Version:        req.Version,
+			PackageManager: "go",
+			Location:       projectPath,
+			Properties:     localProjectProperties(projectPath, "go-local"),
+		}
+		if req.Indirect {
+			comp.Properties["indirect"] = "true"
+		}
+		if mod.Module != "" {
+			comp.Properties["module"] = mod.Module
+		}
+
+		sumKey := req.Path + "@" + req.Version
+		if target, ok := mod.replacement(req.goModuleVersion); ok {
+			replacement := target.Path
+			if target.Version != "" {
+				replacement += "@" + target.Version
+				sumKey = target.Path + "@" + target.Version
+			}
+			comp.Properties["replaced_by"] = replacement
+		}
+		if sum, ok := sums[sumKey]; ok {
+			comp.Properties["module_sum"] = sum
+		}
+
+		nodes[req.Path] = comp
+	}
+
+	edges := make(map[string][]string)
+	for _, req := range mod.Requires {
+		if len(modCaches) == 0 {
+			break
+		}
+		depMod, err := readCachedGoMod(modCaches, req.goModuleVersion)
+		if err != nil {
+			continue
+		}
+		for _, depReq := range depMod.Requires {
+			if _, ok := selected[depReq.Path]; ok {
+				edges[req.Path] = append(edges[req.Path], depReq.Path)
+			}
+		}
+		sort.Strings(edges[req.Path])
+	}
+
+	// Direct requirements form the top level, followed by anything not reachable from them
+	var roots []string
+	for _, req := range mod.Requires {
+		if !req.Indirect {
+			roots = append(roots, req.Path)
+		}
+	}
+	var all []string
+	for _, req := range mod.Requires {
+		all = append(all, req.Path)
+	}
+	roots = appendUnreachedRoots(roots, edges, all)
+
+	return scanners.ExpandDependencyGraph(nodes, edges, roots), nil
+}
+
+// goModCacheDirs returns the module download caches ($GOMODCACHE/cache/download)
+// that exist, in the order they are searched: the default cache of the home the
+// project is in, the cache configured for the user running the scan, then the
+// default caches of the other homes scanned
+func goModCacheDirs(homes []string, projectPath string) []string {
+	var candidates []string
+	for _, home := range homes {
+		if strings.HasPrefix(projectPath, home+string(filepath.Separator)) {
+			candidates = append(candidates, filepath.Join(home, "go", "pkg", "mod"))
+		}
+	}
+	if modCache := os.Getenv("GOMODCACHE"); modCache != "" {
+		candidates = append(candidates, modCache)
+	} else if gopath := os.Getenv("GOPATH"); gopath != "" {
+		candidates = append(candidates, filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod"))
+	}
+	for _, home := range homes {
+		candidates = append(candidates, filepath.Join(home, "go", "pkg", "mod"))
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, modCache := range candidates {
+		dir := filepath.Join(modCache, "cache", "download")
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// readCachedGoMod reads a dependency's go.mod from the first module download
+// cache that has it
+func readCachedGoMod(cacheDirs []string, mod goModuleVersion) (*goModFile, error) {
+	err := os.ErrNotExist
+	for _, cacheDir := range cacheDirs {
+		path := filepath.Join(cacheDir, escapeModulePath(mod.Path), "@v", escapeModulePath(mod.Version)+".mod")
+		var data []byte
+		if data, err = os.ReadFile(path); err == nil {
+			return parseGoMod(string(data)), nil
+		}
+	}
+	return nil, err
+}
+
+// escapeModulePath applies the module cache's case encoding, where each upper-case
+// letter is written as '!' followed by its lower-case form
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,198 @@
+package packagemanagers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eapolsniper/endpointbom/internal/hashing"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// lockPackage is a single resolved package from a JavaScript lockfile
+type lockPackage struct {
+	Name         string
+	Version      string
+	Resolved     string   // Tarball URL or resolution string
+	Integrity    string   // Subresource integrity hash (sha512-..., sha1-...)
+	License      string   // Declared license, when the lockfile records it
+	Dev          bool     // Only needed for development
+	Optional     bool     // Optional dependency
+	Dependencies []string // Keys of the packages this package depends on
+}
+
+// lockGraph is the resolved dependency graph of a project, independent of lockfile format
+type lockGraph struct {
+	Packages map[string]*lockPackage // Keyed by a format-specific unique key
+	Roots    []string                // Keys of the project's direct dependencies
+}
+
+// npmLockfiles lists supported lockfiles in order of preference
+var npmLockfiles = []string{
+	"npm-shrinkwrap.json",
+	"package-lock.json",
+	"pnpm-lock.yaml",
+	"yarn.lock",
+}
+
+// findNPMLockfile returns the path of the preferred lockfile in a project, or "" if none exists
+func findNPMLockfile(projectPath string) string {
+	for _, name := range npmLockfiles {
+		path := filepath.Join(projectPath, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// parseNPMLockfile parses any supported JavaScript lockfile into a dependency graph
+func parseNPMLockfile(lockfilePath string) (*lockGraph, error) {
+	data, err := os.ReadFile(lockfilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	projectPath := filepath.Dir(lockfilePath)
+
+	switch filepath.Base(lockfilePath) {
+	case "package-lock.json", "npm-shrinkwrap.json":
+		return parsePackageLock(data, projectPath)
+	case "yarn.lock":
+		return parseYarnLock(data, projectPath)
+	case "pnpm-lock.yaml":
+		return parsePnpmLock(data)
+	default:
+		return nil, fmt.Errorf("unsupported lockfile: %s", lockfilePath)
+	}
+}
+
+// buildLockComponents converts a lockfile graph into a component tree.
+// Like `npm list --all`, each package is expanded only the first time it is
+// seen; later occurrences are listed without children (deduped). This keeps
+// the tree linear in size while the SBOM still gets every dependency edge.
+func buildLockComponents(graph *lockGraph, projectPath string, lockfileName string) []scanners.Component {
+	expanded := make(map[string]bool)
+
+	var build func(key string, depth int) (scanners.Component, bool)
+	build = func(key string, depth int) (scanners.Component, bool) {
+		pkg, exists := graph.Packages[key]
+		if !exists || pkg.Name == "" {
+			return scanners.Component{}, false
+		}
+
+		comp := scanners.Component{
+			Type:           "library",
+			Name:           pkg.Name,
+			Version:        pkg.Version,
+			PackageManager: "npm",
+			Location:       projectPath,
+			Properties:     make(map[string]string),
+		}
+
+		comp.Properties["dependency_depth"] = fmt.Sprintf("%d", depth)
+		comp.Properties["lockfile"] = lockfileName
+		// package-lock.json keys are install paths such as node_modules/a/node_modules/b
+		if strings.HasPrefix(key, "node_modules/") {
+			comp.Properties["install_path"] = filepath.Join(projectPath, filepath.FromSlash(key))
+		}
+
+		// npm lockfiles record licenses; the rest, the author and the package's
+		// URLs come from the installed package, which yarn and pnpm hoist to
+		// node_modules/<name>
+		comp.License = pkg.License
+		installPath := comp.Properties["install_path"]
+		if installPath == "" {
+			installPath = filepath.Join(projectPath, "node_modules", filepath.FromSlash(pkg.Name))
+		}
+		applyPackageJSON(&comp, installPath)
+		if pkg.Resolved != "" {
+			comp.Properties["resolved"] = pkg.Resolved
+			comp.AddExternalRef(scanners.RefDistribution, pkg.Resolved)
+		}
+		if pkg.Integrity != "" {
+			comp.Properties["integrity"] = pkg.Integrity
+			// The lockfile's integrity is the registry tarball digest
+			if hashes := hashing.FromSRI(pkg.Integrity); len(hashes) > 0 {
+				comp.Hashes = hashes
+			}
+		}
+		comp.Identity = &scanners.Identity{
+			Field:      "purl",
+			Technique:  "manifest-analysis",
+			Confidence: 1,
+			Value:      filepath.Join(projectPath, lockfileName),
+		}
+		if pkg.Dev {
+			comp.Properties["dev_dependency"] = "true"
+		}
+		if pkg.Optional {
+			comp.Properties["optional_dependency"] = "true"
+		}
+
+		if expanded[key] {
+			return comp, true
+		}
+		expanded[key] = true
+
+		for _, depKey := range pkg.Dependencies {
+			if depComp, ok := build(depKey, depth+1); ok {
+				comp.Dependencies = append(comp.Dependencies, depComp)
+			}
+		}
+
+		return comp, true
+	}
+
+	var components []scanners.Component
+	seenRoots := make(map[string]bool)
+	for _, key := range graph.Roots {
+		if seenRoots[key] {
+			continue
+		}
+		seenRoots[key] = true
+
+		if comp, ok := build(key, 0); ok {
+			components = append(components, comp)
+		}
+	}
+
+	return components
+}
+
+// packageJSONManifest holds the dependency sections of a package.json
+type packageJSONManifest struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// readPackageJSON reads the package.json in a project directory
+func readPackageJSON(projectPath string) (*packageJSONManifest, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest packageJSONManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
+
+// allDependencies returns every direct dependency name and range declared in package.json
+func (m *packageJSONManifest) allDependencies() map[string]string {
+	all := make(map[string]string)
+	for _, deps := range []map[string]string{m.Dependencies, m.DevDependencies, m.OptionalDependencies} {
+		for name, spec := range deps {
+			all[name] = spec
+		}
+	}
+	return all
+}
@@ -0,0 +1,248 @@
+package packagemanagers
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/eapolsniper/endpointbom/internal/license"
+)
+
+// packageLockFile is the subset of package-lock.json / npm-shrinkwrap.json we read.
+// v1 lockfiles only have "dependencies", v3 only "packages", v2 has both.
+type packageLockFile struct {
+	LockfileVersion int                           `json:"lockfileVersion"`
+	Packages        map[string]packageLockEntry   `json:"packages"`
+	Dependencies    map[string]packageLockV1Entry `json:"dependencies"`
+}
+
+// packageLockEntry is an entry in the v2/v3 "packages" section
+type packageLockEntry struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Resolved             string            `json:"resolved"`
+	Integrity            string            `json:"integrity"`
+	License              json.RawMessage   `json:"license"`
+	Link                 bool              `json:"link"`
+	Dev                  bool              `json:"dev"`
+	Optional             bool              `json:"optional"`
+	DevOptional          bool              `json:"devOptional"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+}
+
+// packageLockV1Entry is an entry in the v1 "dependencies" tree
+type packageLockV1Entry struct {
+	Version      string                        `json:"version"`
+	Resolved     string                        `json:"resolved"`
+	Integrity    string                        `json:"integrity"`
+	Dev          bool                          `json:"dev"`
+	Optional     bool                          `json:"optional"`
+	Requires     map[string]string             `json:"requires"`
+	Dependencies map[string]packageLockV1Entry `json:"dependencies"`
+}
+
+// parsePackageLock parses package-lock.json (v1, v2 and v3)
+func parsePackageLock(data []byte, projectPath string) (*lockGraph, error) {
+	var lock packageLockFile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse package-lock.json: %w", err)
+	}
+
+	if len(lock.Packages) > 0 {
+		return parsePackageLockV2(&lock), nil
+	}
+	if len(lock.Dependencies) > 0 {
+		return parsePackageLockV1(&lock, projectPath), nil
+	}
+
+	return &lockGraph{Packages: map[string]*lockPackage{}}, nil
+}
+
+// parsePackageLockV2 builds a graph from the flat "packages" map keyed by install path
+// (e.g. "node_modules/a/node_modules/b"). Dependencies are resolved the same way
+// Node does: look in the package's own node_modules, then walk up to the root.
+func parsePackageLockV2(lock *packageLockFile) *lockGraph {
+	graph := &lockGraph{Packages: make(map[string]*lockPackage)}
+
+	// Follow workspace links to the directory that holds the real package
+	target := func(key string) string {
+		if entry, ok := lock.Packages[key]; ok && entry.Link && entry.Resolved != "" {
+			return entry.Resolved
+		}
+		return key
+	}
+
+	for key, entry := range lock.Packages {
+		if key == "" || entry.Link {
+			continue
+		}
+
+		name := entry.Name
+		if idx := strings.LastIndex(key, "node_modules/"); idx != -1 {
+			name = key[idx+len("node_modules/"):]
+		}
+		if name == "" {
+			name = path.Base(key)
+		}
+
+		pkg := &lockPackage{
+			Name:      name,
+			Version:   entry.Version,
+			Resolved:  entry.Resolved,
+			Integrity: entry.Integrity,
+			License:   license.FromPackageJSON(entry.License, nil),
+			Dev:       entry.Dev || entry.DevOptional,
+			Optional:  entry.Optional,
+		}
+
+		for _, depName := range sortedKeys(entry.Dependencies, entry.OptionalDependencies, entry.PeerDependencies) {
+			if depKey := resolveNodeModule(lock.Packages, key, depName); depKey != "" {
+				pkg.Dependencies = append(pkg.Dependencies, target(depKey))
+			}
+		}
+
+		graph.Packages[key] = pkg
+	}
+
+	// Direct dependencies of the root project and of every workspace member
+	importers := []string{""}
+	for key, entry := range lock.Packages {
+		if key != "" && !entry.Link && !strings.Contains(key, "node_modules/") {
+			importers = append(importers, key)
+		}
+	}
+	sort.Strings(importers[1:])
+
+	for _, importer := range importers {
+		entry := lock.Packages[importer]
+		for _, depName := range sortedKeys(entry.Dependencies, entry.DevDependencies, entry.OptionalDependencies) {
+			if depKey := resolveNodeModule(lock.Packages, importer, depName); depKey != "" {
+				graph.Roots = append(graph.Roots, target(depKey))
+			}
+		}
+	}
+
+	return graph
+}
+
+// resolveNodeModule finds the install path a package at fromKey would load depName from
+func resolveNodeModule(packages map[string]packageLockEntry, fromKey, depName string) string {
+	dir := fromKey
+	for {
+		var candidate string
+		if dir == "" {
+			candidate = "node_modules/" + depName
+		} else {
+			candidate = dir + "/node_modules/" + depName
+		}
+		if _, ok := packages[candidate]; ok {
+			return candidate
+		}
+
+		if dir == "" {
+			return ""
+		}
+
+		// Move up one node_modules level; workspace dirs fall back to the root
+		idx := strings.LastIndex(dir, "/node_modules/")
+		if idx == -1 {
+			dir = ""
+		} else {
+			dir = dir[:idx]
+		}
+	}
+}
+
+// parsePackageLockV1 builds a graph from the nested v1 "dependencies" tree.
+// Keys are synthesized install paths so that nested copies stay distinct.
+func parsePackageLockV1(lock *packageLockFile, projectPath string) *lockGraph {
+	graph := &lockGraph{Packages: make(map[string]*lockPackage)}
+
+	type scope struct {
+		key    string
+		deps   map[string]packageLockV1Entry
+		parent *scope
+	}
+
+	// resolve walks up the nesting scopes like Node's module resolution
+	resolve := func(s *scope, name string) string {
+		for ; s != nil; s = s.parent {
+			if _, ok := s.deps[name]; ok {
+				if s.key == "" {
+					return "node_modules/" + name
+				}
+				return s.key + "/node_modules/" + name
+			}
+		}
+		return ""
+	}
+
+	var walk func(s *scope)
+	walk = func(s *scope) {
+		for _, name := range sortedKeys(s.deps) {
+			entry := s.deps[name]
+			key := "node_modules/" + name
+			if s.key != "" {
+				key = s.key + "/node_modules/" + name
+			}
+
+			child := &scope{key: key, deps: entry.Dependencies, parent: s}
+
+			pkg := &lockPackage{
+				Name:      name,
+				Version:   entry.Version,
+				Resolved:  entry.Resolved,
+				Integrity: entry.Integrity,
+				Dev:       entry.Dev,
+				Optional:  entry.Optional,
+			}
+			for _, depName := range sortedKeys(entry.Requires) {
+				if depKey := resolve(child, depName); depKey != "" {
+					pkg.Dependencies = append(pkg.Dependencies, depKey)
+				}
+			}
+			graph.Packages[key] = pkg
+
+			walk(child)
+		}
+	}
+
+	root := &scope{key: "", deps: lock.Dependencies}
+	walk(root)
+
+	// v1 lockfiles don't record the root's direct dependencies; take them from package.json
+	if manifest, err := readPackageJSON(projectPath); err == nil {
+		for _, name := range sortedKeys(manifest.allDependencies()) {
+			if key := resolve(root, name); key != "" {
+				graph.Roots = append(graph.Roots, key)
+			}
+		}
+	} else {
+		for _, name := range sortedKeys(lock.Dependencies) {
+			graph.Roots = append(graph.Roots, "node_modules/"+name)
+		}
+	}
+
+	return graph
+}
+
+// sortedKeys returns the union of the keys of the given maps in sorted order
+func sortedKeys[V any](maps ...map[string]V) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
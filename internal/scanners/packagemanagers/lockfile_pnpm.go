@@ -0,0 +1,221 @@
+package packagemanagers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// pnpmLockFile is the subset of pnpm-lock.yaml we read. It covers lockfile
+// versions 5.x (flat root deps, /name/version keys), 6.x (/name@version keys,
+// importers for workspaces) and 9.x (name@version keys, separate snapshots).
+type pnpmLockFile struct {
+	LockfileVersion      interface{}                 `yaml:"lockfileVersion"`
+	Importers            map[string]pnpmImporter     `yaml:"importers"`
+	Dependencies         map[string]pnpmSpec         `yaml:"dependencies"`
+	DevDependencies      map[string]pnpmSpec         `yaml:"devDependencies"`
+	OptionalDependencies map[string]pnpmSpec         `yaml:"optionalDependencies"`
+	Packages             map[string]pnpmLockPackage  `yaml:"packages"`
+	Snapshots            map[string]pnpmLockSnapshot `yaml:"snapshots"`
+}
+
+type pnpmImporter struct {
+	Dependencies         map[string]pnpmSpec `yaml:"dependencies"`
+	DevDependencies      map[string]pnpmSpec `yaml:"devDependencies"`
+	OptionalDependencies map[string]pnpmSpec `yaml:"optionalDependencies"`
+}
+
+// pnpmSpec is a root dependency: a bare version (v5) or {specifier, version} (v6+)
+type pnpmSpec struct {
+	Version string
+}
+
+func (s *pnpmSpec) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		s.Version = node.Value
+		return nil
+	}
+	var spec struct {
+		Version string `yaml:"version"`
+	}
+	if err := node.Decode(&spec); err != nil {
+		return err
+	}
+	s.Version = spec.Version
+	return nil
+}
+
+type pnpmLockPackage struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Resolution struct {
+		Integrity string `yaml:"integrity"`
+		Tarball   string `yaml:"tarball"`
+	} `yaml:"resolution"`
+	Dev                  bool              `yaml:"dev"`
+	Optional             bool              `yaml:"optional"`
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+type pnpmLockSnapshot struct {
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+// parsePnpmLock parses pnpm-lock.yaml
+func parsePnpmLock(data []byte) (*lockGraph, error) {
+	var lock pnpmLockFile
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse pnpm-lock.yaml: %w", err)
+	}
+
+	major := pnpmLockMajorVersion(lock.LockfileVersion)
+	graph := &lockGraph{Packages: make(map[string]*lockPackage)}
+
+	// v9 splits package metadata (packages) from the resolved graph (snapshots)
+	if major >= 9 {
+		for key, snapshot := range lock.Snapshots {
+			name, version := splitPnpmKey(key, major)
+			meta := lock.Packages[pnpmStripPeers(key)]
+			graph.Packages[key] = &lockPackage{
+				Name:         name,
+				Version:      version,
+				Resolved:     meta.Resolution.Tarball,
+				Integrity:    meta.Resolution.Integrity,
+				Optional:     meta.Optional,
+				Dependencies: pnpmDependencyKeys(major, snapshot.Dependencies, snapshot.OptionalDependencies),
+			}
+		}
+	} else {
+		for key, pkg := range lock.Packages {
+			name, version := splitPnpmKey(key, major)
+			if pkg.Name != "" {
+				name = pkg.Name
+			}
+			if pkg.Version != "" {
+				version = pkg.Version
+			}
+			graph.Packages[key] = &lockPackage{
+				Name:         name,
+				Version:      version,
+				Resolved:     pkg.Resolution.Tarball,
+				Integrity:    pkg.Resolution.Integrity,
+				Dev:          pkg.Dev,
+				Optional:     pkg.Optional,
+				Dependencies: pnpmDependencyKeys(major, pkg.Dependencies, pkg.OptionalDependencies),
+			}
+		}
+	}
+
+	// Root dependencies live in importers (workspaces, v6+) or at the top level (v5)
+	importers := lock.Importers
+	if len(importers) == 0 {
+		importers = map[string]pnpmImporter{
+			".": {
+				Dependencies:         lock.Dependencies,
+				DevDependencies:      lock.DevDependencies,
+				OptionalDependencies: lock.OptionalDependencies,
+			},
+		}
+	}
+
+	importerNames := make([]string, 0, len(importers))
+	for name := range importers {
+		importerNames = append(importerNames, name)
+	}
+	sort.Strings(importerNames)
+
+	for _, importerName := range importerNames {
+		importer := importers[importerName]
+		for _, depName := range sortedKeys(importer.Dependencies, importer.DevDependencies, importer.OptionalDependencies) {
+			spec, ok := importer.Dependencies[depName]
+			if !ok {
+				if spec, ok = importer.DevDependencies[depName]; !ok {
+					spec = importer.OptionalDependencies[depName]
+				}
+			}
+			if key := pnpmDependencyKey(major, depName, spec.Version); key != "" {
+				graph.Roots = append(graph.Roots, key)
+			}
+		}
+	}
+
+	return graph, nil
+}
+
+// pnpmLockMajorVersion returns the major lockfile version (5, 6, 9, ...)
+func pnpmLockMajorVersion(v interface{}) int {
+	var major int
+	fmt.Sscanf(fmt.Sprintf("%v", v), "%d", &major)
+	return major
+}
+
+// pnpmDependencyKeys converts a dependency map into package keys, in sorted order
+func pnpmDependencyKeys(major int, deps ...map[string]string) []string {
+	var keys []string
+	for _, name := range sortedKeys(deps...) {
+		version := ""
+		for _, m := range deps {
+			if v, ok := m[name]; ok {
+				version = v
+				break
+			}
+		}
+		if key := pnpmDependencyKey(major, name, version); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// pnpmDependencyKey builds the packages/snapshots key for a dependency reference
+func pnpmDependencyKey(major int, name, version string) string {
+	switch {
+	case version == "", strings.HasPrefix(version, "link:"), strings.HasPrefix(version, "file:"):
+		// Local links and workspace packages aren't in the packages section
+		return ""
+	case major >= 9:
+		// Aliased deps reference another package key (e.g. "string-width@4.2.3")
+		if before, _, _ := strings.Cut(version, "("); strings.LastIndex(before, "@") > 0 {
+			return version
+		}
+		return name + "@" + version
+	case strings.HasPrefix(version, "/"):
+		return version
+	case major >= 6:
+		return "/" + name + "@" + version
+	default:
+		return "/" + name + "/" + version
+	}
+}
+
+// splitPnpmKey extracts the package name and version from a packages key:
+// "/@scope/name/1.0.0_peer" (v5), "/@scope/name@1.0.0(peer)" (v6), "@scope/name@1.0.0(peer)" (v9)
+func splitPnpmKey(key string, major int) (string, string) {
+	key = strings.TrimPrefix(pnpmStripPeers(key), "/")
+
+	if major >= 6 {
+		if idx := strings.LastIndex(key, "@"); idx > 0 {
+			return key[:idx], key[idx+1:]
+		}
+		return key, ""
+	}
+
+	// v5 appends peer dependencies to the version with an underscore
+	if idx := strings.LastIndex(key, "/"); idx > 0 {
+		version, _, _ := strings.Cut(key[idx+1:], "_")
+		return key[:idx], version
+	}
+	return key, ""
+}
+
+// pnpmStripPeers removes the "(peer@version)" suffix used by v6+ keys and versions
+func pnpmStripPeers(key string) string {
+	if idx := strings.Index(key, "("); idx != -1 {
+		return key[:idx]
+	}
+	return key
+}
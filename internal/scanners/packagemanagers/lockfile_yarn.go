@@ -0,0 +1,237 @@
+package packagemanagers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yarnLockEntry is a resolved package from yarn.lock (classic or berry)
+type yarnLockEntry struct {
+	Version      string            `yaml:"version"`
+	Resolved     string            `yaml:"resolved"`     // classic only
+	Integrity    string            `yaml:"integrity"`    // classic only
+	Resolution   string            `yaml:"resolution"`   // berry only
+	Checksum     string            `yaml:"checksum"`     // berry only
+	LanguageName string            `yaml:"languageName"` // berry only
+	Dependencies map[string]string `yaml:"dependencies"`
+	Optional     map[string]string `yaml:"optionalDependencies"`
+}
+
+// parseYarnLock parses a yarn.lock file. Yarn classic (v1) uses its own
+// indentation-based format; Yarn berry (v2+) lockfiles are valid YAML and
+// start with a __metadata block.
+func parseYarnLock(data []byte, projectPath string) (*lockGraph, error) {
+	var entries map[string]yarnLockEntry
+	var berry bool
+
+	if bytes.Contains(data, []byte("__metadata:")) {
+		berry = true
+		if err := yaml.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse yarn.lock: %w", err)
+		}
+		delete(entries, "__metadata")
+	} else {
+		var err error
+		entries, err = parseYarnClassicLock(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	graph := &lockGraph{Packages: make(map[string]*lockPackage)}
+
+	// Each entry key lists every descriptor (name@range) that resolves to it
+	descriptors := make(map[string]string)
+	for key := range entries {
+		for _, descriptor := range strings.Split(key, ",") {
+			descriptor = strings.Trim(strings.TrimSpace(descriptor), `"`)
+			descriptors[descriptor] = key
+		}
+	}
+
+	// lookup maps a dependency name and range to the entry key that satisfies it
+	lookup := func(name, spec string) string {
+		candidates := []string{name + "@" + spec}
+		if berry && !strings.Contains(spec, ":") {
+			candidates = append(candidates, name+"@npm:"+spec)
+		}
+		for _, candidate := range candidates {
+			if key, ok := descriptors[candidate]; ok {
+				return key
+			}
+		}
+		return ""
+	}
+
+	var workspaceKeys []string
+	for key, entry := range entries {
+		name := yarnDescriptorName(key)
+
+		// Berry records workspaces (including the root project) as entries
+		if berry && strings.Contains(key, "@workspace:") {
+			workspaceKeys = append(workspaceKeys, key)
+		}
+
+		pkg := &lockPackage{
+			Name:      name,
+			Version:   entry.Version,
+			Resolved:  entry.Resolved,
+			Integrity: entry.Integrity,
+		}
+		if berry {
+			pkg.Resolved = entry.Resolution
+			pkg.Integrity = entry.Checksum
+		}
+
+		for _, depName := range sortedKeys(entry.Dependencies, entry.Optional) {
+			spec := entry.Dependencies[depName]
+			if spec == "" {
+				spec = entry.Optional[depName]
+			}
+			if depKey := lookup(depName, spec); depKey != "" {
+				pkg.Dependencies = append(pkg.Dependencies, depKey)
+			}
+		}
+
+		graph.Packages[key] = pkg
+	}
+
+	if len(workspaceKeys) > 0 {
+		sort.Strings(workspaceKeys)
+		for _, key := range workspaceKeys {
+			graph.Roots = append(graph.Roots, graph.Packages[key].Dependencies...)
+			delete(graph.Packages, key)
+		}
+		return graph, nil
+	}
+
+	// Classic lockfiles don't record the root project; take direct deps from package.json
+	manifest, err := readPackageJSON(projectPath)
+	if err != nil {
+		return graph, nil
+	}
+	deps := manifest.allDependencies()
+	for _, name := range sortedKeys(deps) {
+		if key := lookup(name, deps[name]); key != "" {
+			graph.Roots = append(graph.Roots, key)
+		}
+	}
+
+	return graph, nil
+}
+
+// parseYarnClassicLock parses the Yarn v1 lockfile format:
+//
+//	"@babel/core@^7.0.0", "@babel/core@^7.1.0":
+//	  version "7.12.3"
+//	  resolved "https://registry.yarnpkg.com/..."
+//	  integrity sha512-...
+//	  dependencies:
+//	    "@babel/types" "^7.12.1"
+func parseYarnClassicLock(data []byte) (map[string]yarnLockEntry, error) {
+	entries := make(map[string]yarnLockEntry)
+
+	var currentKey string
+	var current yarnLockEntry
+	var section string
+
+	flush := func() {
+		if currentKey != "" {
+			entries[currentKey] = current
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		switch {
+		case indent == 0:
+			// New entry: one or more descriptors followed by a colon
+			flush()
+			currentKey = strings.TrimSuffix(trimmed, ":")
+			current = yarnLockEntry{}
+			section = ""
+
+		case indent == 2 && strings.HasSuffix(trimmed, ":"):
+			section = strings.TrimSuffix(trimmed, ":")
+
+		case indent == 2:
+			section = ""
+			key, value := splitYarnField(trimmed)
+			switch key {
+			case "version":
+				current.Version = value
+			case "resolved":
+				current.Resolved = value
+			case "integrity":
+				current.Integrity = value
+			}
+
+		case indent >= 4 && section != "":
+			name, value := splitYarnField(trimmed)
+			switch section {
+			case "dependencies":
+				if current.Dependencies == nil {
+					current.Dependencies = make(map[string]string)
+				}
+				current.Dependencies[name] = value
+			case "optionalDependencies":
+				if current.Optional == nil {
+					current.Optional = make(map[string]string)
+				}
+				current.Optional[name] = value
+			}
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read yarn.lock: %w", err)
+	}
+
+	return entries, nil
+}
+
+// splitYarnField splits a `key "value"` line from a classic yarn.lock
+func splitYarnField(line string) (string, string) {
+	var key, value string
+	if strings.HasPrefix(line, `"`) {
+		end := strings.Index(line[1:], `"`)
+		if end == -1 {
+			return strings.Trim(line, `"`), ""
+		}
+		key = line[1 : end+1]
+		value = strings.TrimSpace(line[end+2:])
+	} else {
+		parts := strings.SplitN(line, " ", 2)
+		key = parts[0]
+		if len(parts) == 2 {
+			value = strings.TrimSpace(parts[1])
+		}
+	}
+	return key, strings.Trim(value, `"`)
+}
+
+// yarnDescriptorName extracts the package name from a lockfile key such as
+// `"@babel/core@^7.0.0", "@babel/core@^7.1.0"` or `lodash@npm:^4.17.21`
+func yarnDescriptorName(key string) string {
+	first := strings.Trim(strings.TrimSpace(strings.Split(key, ",")[0]), `"`)
+	// Skip the leading @ of scoped packages when looking for the version separator
+	if idx := strings.Index(first[min(1, len(first)):], "@"); idx != -1 {
+		return first[:idx+1]
+	}
+	return first
+}
@@ -1,12 +1,13 @@
 package packagemanagers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"path/filepath"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/license"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
 )
 
@@ -18,6 +19,11 @@ func (s *NPMScanner) Name() string {
 }
 
 func (s *NPMScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the npm scan, stopping early if ctx is cancelled
+func (s *NPMScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("npm") {
 		return nil, nil
 	}
@@ -27,30 +33,30 @@ func (s *NPMScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 		if cfg.Debug {
 			fmt.Println("npm not found, skipping")
 		}
-		return nil, nil
+		return nil, scanners.NotInstalled("npm")
 	}
 
 	var components []scanners.Component
 
-	// Get global packages with all dependencies
-	cmd := exec.Command("npm", "ls", "-g", "--all", "--json", "--depth=999")
-	output, err := cmd.Output()
+	// Get global packages with all dependencies; --long adds each package's
+	// package.json fields, including its license
+	output, err := RunCommand(ctx, "", "npm", "ls", "-g", "--all", "--json", "--long", "--depth=999")
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if cfg.Debug {
 			fmt.Printf("npm scan error: %v\n", err)
 		}
 		// npm ls returns non-zero even on success sometimes
 		if len(output) == 0 {
-			return nil, nil
+			return nil, fmt.Errorf("npm scan failed: %w", err)
 		}
 	}
 
 	var result npmListResult
 	if err := json.Unmarshal(output, &result); err != nil {
-		if cfg.Debug {
-			fmt.Printf("npm parse error: %v\n", err)
-		}
-		return nil, nil
+		return nil, fmt.Errorf("failed to parse npm output: %w", err)
 	}
 
 	// Parse dependencies recursively
@@ -68,34 +74,59 @@ type npmListResult struct {
 type npmPackage struct {
 	Version      string                `json:"version"`
 	Resolved     string                `json:"resolved"`
+	License      json.RawMessage       `json:"license"`
+	Licenses     json.RawMessage       `json:"licenses"`
 	Dependencies map[string]npmPackage `json:"dependencies"`
+
+	scanners.PackageJSONMetadata // Included with --long
 }
 
+// parseNPMDependencies converts the `npm ls --all` tree into components. npm
+// only prints a package's dependencies at one of the places it appears (the rest
+// are deduped), so the tree is flattened into a graph keyed by name@version and
+// expanded again with every package's dependencies attached. Names are visited
+// in sorted order so the output is the same from run to run.
 func parseNPMDependencies(deps map[string]npmPackage, location string) []scanners.Component {
-	var components []scanners.Component
-
-	for name, pkg := range deps {
-		comp := scanners.Component{
-			Type:           "library",
-			Name:           name,
-			Version:        pkg.Version,
-			PackageManager: "npm",
-			Location:       location,
-			Properties:     make(map[string]string),
-		}
-
-		if pkg.Resolved != "" {
-			comp.Properties["resolved"] = pkg.Resolved
+	nodes := make(map[string]scanners.Component)
+	edges := make(map[string][]string)
+
+	var walk func(deps map[string]npmPackage, location string) []string
+	walk = func(deps map[string]npmPackage, location string) []string {
+		var keys []string
+
+		for _, name := range sortedKeys(deps) {
+			pkg := deps[name]
+			key := name + "@" + pkg.Version
+			keys = append(keys, key)
+
+			if _, exists := nodes[key]; !exists {
+				comp := scanners.Component{
+					Type:           "library",
+					Name:           name,
+					Version:        pkg.Version,
+					PackageManager: "npm",
+					Location:       location,
+					License:        license.FromPackageJSON(pkg.License, pkg.Licenses),
+					Properties:     make(map[string]string),
+				}
+				pkg.ApplyTo(&comp)
+
+				if pkg.Resolved != "" {
+					comp.Properties["resolved"] = pkg.Resolved
+					comp.AddExternalRef(scanners.RefDistribution, pkg.Resolved)
+				}
+
+				nodes[key] = comp
+			}
+
+			// Parse transitive dependencies
+			if pkg.Dependencies != nil {
+				edges[key] = append(edges[key], walk(pkg.Dependencies, filepath.Join(location, name))...)
+			}
 		}
 
-		// Parse transitive dependencies
-		if pkg.Dependencies != nil {
-			comp.Dependencies = parseNPMDependencies(pkg.Dependencies, filepath.Join(location, name))
-		}
-
-		components = append(components, comp)
+		return keys
 	}
 
-	return components
+	return scanners.ExpandDependencyGraph(nodes, edges, walk(deps, location))
 }
-
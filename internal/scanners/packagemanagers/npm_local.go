@@ -1,14 +1,13 @@
 package packagemanagers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/license"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
 )
 
@@ -20,81 +19,85 @@ func (s *NPMLocalScanner) Name() string {
 }
 
 func (s *NPMLocalScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
-	if cfg.IsScannerDisabled("npm-local") {
-		return nil, nil
-	}
+	return s.ScanContext(context.Background(), cfg)
+}
 
-	// Check if npm is installed
-	if !isCommandAvailable("npm") {
-		if cfg.Debug {
-			fmt.Println("npm not found, skipping local npm scan")
-		}
+// ScanContext performs the npm-local scan, stopping early if ctx is cancelled
+func (s *NPMLocalScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
+	if cfg.IsScannerDisabled("npm-local") {
 		return nil, nil
 	}
 
 	var components []scanners.Component
 
-	// Get list of potential project directories
-	projectDirs := getProjectDirectories(cfg)
+	projects, err := findProjects(ctx, cfg, "package.json")
+	if err != nil {
+		return nil, err
+	}
 
-	for _, baseDir := range projectDirs {
-		if cfg.Verbose {
-			fmt.Printf("Scanning for npm projects in: %s\n", baseDir)
+	for _, project := range projects {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
 
-		// Find all node_modules directories
-		err := filepath.Walk(baseDir, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil // Skip directories we can't access
+		switch {
+		case project.Has("node_modules"):
+			if cfg.Debug {
+				fmt.Printf("Found npm project at: %s\n", project.Path)
 			}
-
-			// Skip if this path is excluded
-			if cfg.IsPathExcluded(path) {
-				if info.IsDir() {
-					return filepath.SkipDir
-				}
-				return nil
+		case project.Has(".pnp.cjs"):
+			// Yarn Plug'n'Play projects have no node_modules directory
+			if cfg.Debug {
+				fmt.Printf("Found Yarn PnP project at: %s\n", project.Path)
 			}
+		default:
+			// Dependencies were never installed
+			continue
+		}
 
-			// Skip node_modules within node_modules (nested)
-			if info.IsDir() && info.Name() == "node_modules" {
-				// Check if parent has package.json
-				projectPath := filepath.Dir(path)
-				packageJSONPath := filepath.Join(projectPath, "package.json")
-				
-				if _, err := os.Stat(packageJSONPath); err == nil {
-					// Found a valid node project
-					if cfg.Debug {
-						fmt.Printf("Found npm project at: %s\n", projectPath)
-					}
-					
-					packages := scanNPMProject(projectPath, cfg)
-					components = append(components, packages...)
-				}
-
-				// Don't descend into node_modules
-				return filepath.SkipDir
-			}
+		packages := scanNPMProject(ctx, project.Path, cfg)
+		components = append(components, packages...)
+	}
 
-			return nil
-		})
+	return components, nil
+}
 
-		if err != nil && cfg.Debug {
-			fmt.Printf("Error walking directory %s: %v\n", baseDir, err)
+// scanNPMProject inventories a project's installed packages. Lockfiles are parsed
+// directly (no npm needed, no project tooling executed); `npm list` is only used
+// as a fallback for projects without a lockfile.
+func scanNPMProject(ctx context.Context, projectPath string, cfg *config.Config) []scanners.Component {
+	if lockfilePath := findNPMLockfile(projectPath); lockfilePath != "" {
+		graph, err := parseNPMLockfile(lockfilePath)
+		if err == nil {
+			components := buildLockComponents(graph, projectPath, filepath.Base(lockfilePath))
+			for i := range components {
+				for key, value := range localProjectProperties(projectPath, "npm-local") {
+					components[i].Properties[key] = value
+				}
+			}
+			return components
+		}
+		if cfg.Debug {
+			fmt.Printf("Failed to parse %s, falling back to npm list: %v\n", lockfilePath, err)
 		}
 	}
 
-	return components, nil
+	return scanNPMProjectWithCLI(ctx, projectPath, cfg)
 }
 
-func scanNPMProject(projectPath string, cfg *config.Config) []scanners.Component {
+// scanNPMProjectWithCLI runs `npm list` to get the dependency tree of a project without a lockfile
+func scanNPMProjectWithCLI(ctx context.Context, projectPath string, cfg *config.Config) []scanners.Component {
 	var components []scanners.Component
 
-	// Run npm list with full dependency tree
-	cmd := exec.Command("npm", "list", "--json", "--all")
-	cmd.Dir = projectPath
+	if !isCommandAvailable("npm") {
+		if cfg.Debug {
+			fmt.Printf("No lockfile in %s and npm not found, skipping\n", projectPath)
+		}
+		return nil
+	}
 
-	output, err := cmd.Output()
+	// Run npm list with full dependency tree
+	output, err := RunCommand(ctx, projectPath, "npm", "list", "--json", "--all", "--long")
 	if err != nil {
 		// npm list returns non-zero even on success sometimes
 		if len(output) == 0 {
@@ -122,16 +125,16 @@ func scanNPMProject(projectPath string, cfg *config.Config) []scanners.Component
 				Version:        pkg.Version,
 				PackageManager: "npm",
 				Location:       projectPath,
-				Properties:     make(map[string]string),
+				License:        license.FromPackageJSON(pkg.License, pkg.Licenses),
+				Properties:     localProjectProperties(projectPath, "npm-local"),
 			}
 
-			comp.Properties["install_type"] = "local"
-			comp.Properties["project_path"] = projectPath
-			comp.Properties["source"] = "npm-local"
 			comp.Properties["dependency_depth"] = "0" // Direct dependency
+			pkg.ApplyTo(&comp)
 
 			if pkg.Resolved != "" {
 				comp.Properties["resolved"] = pkg.Resolved
+				comp.AddExternalRef(scanners.RefDistribution, pkg.Resolved)
 			}
 
 			// Parse transitive dependencies recursively
@@ -157,13 +160,16 @@ func parseLocalNPMDependencies(deps map[string]npmPackage, projectPath string, d
 			Version:        pkg.Version,
 			PackageManager: "npm",
 			Location:       projectPath,
+			License:        license.FromPackageJSON(pkg.License, pkg.Licenses),
 			Properties:     make(map[string]string),
 		}
 
 		comp.Properties["dependency_depth"] = fmt.Sprintf("%d", depth)
-		
+		pkg.ApplyTo(&comp)
+
 		if pkg.Resolved != "" {
 			comp.Properties["resolved"] = pkg.Resolved
+			comp.AddExternalRef(scanners.RefDistribution, pkg.Resolved)
 		}
 
 		// Recursively parse nested dependencies
@@ -176,98 +182,3 @@ func parseLocalNPMDependencies(deps map[string]npmPackage, projectPath string, d
 
 	return components
 }
-
-// getProjectDirectories returns common directories where projects might be located
-func getProjectDirectories(cfg *config.Config) []string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return []string{}
-	}
-
-	// Common project directory names
-	commonDirs := []string{
-		"projects",
-		"code",
-		"dev",
-		"development",
-		"workspace",
-		"repos",
-		"git",
-		"src",
-		"work",
-	}
-
-	var projectDirs []string
-
-	// Check common project locations
-	for _, dir := range commonDirs {
-		fullPath := filepath.Join(homeDir, dir)
-		if info, err := os.Stat(fullPath); err == nil && info.IsDir() {
-			projectDirs = append(projectDirs, fullPath)
-		}
-	}
-
-	// Also check if home directory itself has projects
-	// but limit depth to avoid scanning everything
-	entries, err := os.ReadDir(homeDir)
-	if err == nil {
-		for _, entry := range entries {
-			if !entry.IsDir() {
-				continue
-			}
-
-			name := entry.Name()
-			
-			// Skip hidden directories, system directories
-			if strings.HasPrefix(name, ".") || 
-			   name == "Library" || 
-			   name == "Documents" || 
-			   name == "Downloads" || 
-			   name == "Desktop" ||
-			   name == "Pictures" ||
-			   name == "Movies" ||
-			   name == "Music" {
-				continue
-			}
-
-			fullPath := filepath.Join(homeDir, name)
-			
-			// Check if this looks like it might contain projects
-			// (has node_modules, package.json, .git, etc.)
-			if looksLikeProjectDir(fullPath) {
-				projectDirs = append(projectDirs, fullPath)
-			}
-		}
-	}
-
-	return projectDirs
-}
-
-func looksLikeProjectDir(path string) bool {
-	// Check for common project indicators
-	indicators := []string{
-		"package.json",
-		"go.mod",
-		"requirements.txt",
-		"Gemfile",
-		"Cargo.toml",
-		"composer.json",
-		".git",
-	}
-
-	entries, err := os.ReadDir(path)
-	if err != nil {
-		return false
-	}
-
-	for _, entry := range entries {
-		for _, indicator := range indicators {
-			if entry.Name() == indicator {
-				return true
-			}
-		}
-	}
-
-	return false
-}
-
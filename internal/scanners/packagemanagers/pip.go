@@ -3,13 +3,17 @@ package packagemanagers
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
+	"github.com/eapolsniper/endpointbom/internal/system"
 )
 
 // PipScanner scans for pip installed packages
@@ -20,12 +24,92 @@ func (s *PipScanner) Name() string {
 }
 
 func (s *PipScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the pip scan, stopping early if ctx is cancelled
+func (s *PipScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("pip") {
 		return nil, nil
 	}
 
 	var components []scanners.Component
 
+	// Read installed distributions directly from site-packages so that no
+	// Python interpreter has to run
+	sitePackagesDirs := GlobalSitePackages(cfg)
+	for _, sitePackages := range sitePackagesDirs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		dists, err := readSitePackages(sitePackages)
+		if err != nil {
+			if cfg.Debug {
+				fmt.Printf("Failed to read %s: %v\n", sitePackages, err)
+			}
+			continue
+		}
+
+		if cfg.Debug {
+			fmt.Printf("Found %d Python packages in %s\n", len(dists), sitePackages)
+		}
+
+		components = append(components, buildPythonComponents(dists, sitePackages, nil)...)
+	}
+
+	if len(sitePackagesDirs) > 0 {
+		return components, nil
+	}
+
+	// No site-packages found in the usual locations; fall back to asking pip
+	return scanPipWithCLI(ctx, cfg)
+}
+
+// GlobalSitePackages returns the system and per-user site-packages directories
+// of Python interpreters installed in common locations
+func GlobalSitePackages(cfg *config.Config) []string {
+	var patterns []string
+
+	switch runtime.GOOS {
+	case "windows":
+		for _, root := range []string{`C:\`, os.Getenv("ProgramFiles"), os.Getenv("ProgramFiles(x86)")} {
+			if root != "" {
+				patterns = append(patterns, filepath.Join(root, "Python*", "Lib", "site-packages"))
+			}
+		}
+		for _, home := range system.HomeDirs(cfg) {
+			patterns = append(patterns,
+				filepath.Join(home, "AppData", "Local", "Programs", "Python", "Python*", "Lib", "site-packages"),
+				filepath.Join(home, "AppData", "Roaming", "Python", "Python*", "site-packages"),
+			)
+		}
+	default:
+		patterns = append(patterns,
+			"/usr/lib/python3*/site-packages",
+			"/usr/lib/python3/dist-packages",
+			"/usr/lib64/python3*/site-packages",
+			"/usr/local/lib/python3*/site-packages",
+			"/usr/local/lib/python3*/dist-packages",
+			"/opt/homebrew/lib/python3*/site-packages",
+			"/Library/Frameworks/Python.framework/Versions/*/lib/python*/site-packages",
+		)
+		for _, home := range system.HomeDirs(cfg) {
+			patterns = append(patterns,
+				filepath.Join(home, ".local", "lib", "python*", "site-packages"),
+				filepath.Join(home, "Library", "Python", "*", "lib", "python", "site-packages"),
+			)
+		}
+	}
+
+	return globDirs(patterns)
+}
+
+// scanPipWithCLI lists packages by running pip, for interpreters installed outside
+// the locations GlobalSitePackages knows about
+func scanPipWithCLI(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
+	var components []scanners.Component
+
 	// Try pip, pip3, and python -m pip
 	commands := [][]string{
 		{"pip", "list", "--format=json"},
@@ -35,15 +119,19 @@ func (s *PipScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 	}
 
 	seen := make(map[string]bool)
+	pipFound := false
 
 	for _, cmdArgs := range commands {
 		if !isCommandAvailable(cmdArgs[0]) {
 			continue
 		}
+		pipFound = true
 
-		cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
-		output, err := cmd.Output()
+		output, err := RunCommand(ctx, "", cmdArgs[0], cmdArgs[1:]...)
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			if cfg.Debug {
 				fmt.Printf("pip scan error with %v: %v\n", cmdArgs, err)
 			}
@@ -73,8 +161,12 @@ func (s *PipScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 				Properties:     make(map[string]string),
 			}
 
-			// Try to get dependencies using pip show
-			deps := getPipDependencies(cmdArgs[0], pkg.Name, cfg)
+			// Try to get dependencies and the license using pip show
+			deps, declared := getPipShowInfo(ctx, cmdArgs[0], pkg.Name, cfg)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			comp.License = declared
 			if len(deps) > 0 {
 				comp.Properties["requires"] = strings.Join(deps, ", ")
 			}
@@ -83,6 +175,10 @@ func (s *PipScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 		}
 	}
 
+	if !pipFound {
+		return nil, scanners.NotInstalled("pip")
+	}
+
 	return components, nil
 }
 
@@ -91,30 +187,41 @@ type pipPackage struct {
 	Version string `json:"version"`
 }
 
-func getPipDependencies(pipCmd, packageName string, cfg *config.Config) []string {
-	cmd := exec.Command(pipCmd, "show", packageName)
-	output, err := cmd.Output()
+// getPipShowInfo returns a package's dependencies and declared license from
+// "pip show". Recent pip versions report the PEP 639 License-Expression, which
+// is preferred over the free-form License field.
+func getPipShowInfo(ctx context.Context, pipCmd, packageName string, cfg *config.Config) (deps []string, declared string) {
+	output, err := RunCommand(ctx, "", pipCmd, "show", packageName)
 	if err != nil {
-		return nil
+		return nil, ""
 	}
 
+	var expression string
 	scanner := bufio.NewScanner(bytes.NewReader(output))
 	for scanner.Scan() {
 		line := scanner.Text()
-		if strings.HasPrefix(line, "Requires:") {
-			depsStr := strings.TrimPrefix(line, "Requires:")
-			depsStr = strings.TrimSpace(depsStr)
+		switch {
+		case strings.HasPrefix(line, "Requires:"):
+			depsStr := strings.TrimSpace(strings.TrimPrefix(line, "Requires:"))
 			if depsStr == "" {
-				return nil
+				continue
 			}
-			deps := strings.Split(depsStr, ",")
+			deps = strings.Split(depsStr, ",")
 			for i, dep := range deps {
 				deps[i] = strings.TrimSpace(dep)
 			}
-			return deps
+		case strings.HasPrefix(line, "License-Expression:"):
+			expression = strings.TrimSpace(strings.TrimPrefix(line, "License-Expression:"))
+		case strings.HasPrefix(line, "License:"):
+			declared = strings.TrimSpace(strings.TrimPrefix(line, "License:"))
 		}
 	}
 
-	return nil
+	if expression != "" {
+		declared = expression
+	}
+	if strings.EqualFold(declared, "UNKNOWN") {
+		declared = ""
+	}
+	return deps, declared
 }
-
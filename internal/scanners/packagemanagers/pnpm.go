@@ -1,9 +1,9 @@
 package packagemanagers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
@@ -17,6 +17,11 @@ func (s *PnpmScanner) Name() string {
 }
 
 func (s *PnpmScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the pnpm scan, stopping early if ctx is cancelled
+func (s *PnpmScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("pnpm") {
 		return nil, nil
 	}
@@ -25,27 +30,23 @@ func (s *PnpmScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 		if cfg.Debug {
 			fmt.Println("pnpm not found, skipping")
 		}
-		return nil, nil
+		return nil, scanners.NotInstalled("pnpm")
 	}
 
 	var components []scanners.Component
 
 	// Get global packages
-	cmd := exec.Command("pnpm", "list", "-g", "--json", "--depth=Infinity")
-	output, err := cmd.Output()
+	output, err := RunCommand(ctx, "", "pnpm", "list", "-g", "--json", "--depth=Infinity")
 	if err != nil {
-		if cfg.Debug {
-			fmt.Printf("pnpm scan error: %v\n", err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-		return nil, nil
+		return nil, fmt.Errorf("pnpm scan failed: %w", err)
 	}
 
 	var result []pnpmListResult
 	if err := json.Unmarshal(output, &result); err != nil {
-		if cfg.Debug {
-			fmt.Printf("pnpm parse error: %v\n", err)
-		}
-		return nil, nil
+		return nil, fmt.Errorf("failed to parse pnpm output: %w", err)
 	}
 
 	for _, pkg := range result {
@@ -62,8 +63,8 @@ type pnpmListResult struct {
 }
 
 type pnpmPackage struct {
-	Version      string                `json:"version"`
-	Path         string                `json:"path"`
+	Version      string                 `json:"version"`
+	Path         string                 `json:"path"`
 	Dependencies map[string]pnpmPackage `json:"dependencies"`
 }
 
@@ -79,6 +80,9 @@ func parsePnpmDependencies(deps map[string]pnpmPackage) []scanners.Component {
 			Location:       pkg.Path,
 			Properties:     make(map[string]string),
 		}
+		if pkg.Path != "" {
+			applyPackageJSON(&comp, pkg.Path)
+		}
 
 		// Parse transitive dependencies
 		if pkg.Dependencies != nil {
@@ -90,4 +94,3 @@ func parsePnpmDependencies(deps map[string]pnpmPackage) []scanners.Component {
 
 	return components
 }
-
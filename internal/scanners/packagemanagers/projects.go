@@ -0,0 +1,28 @@
+package packagemanagers
+
+import (
+	"context"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+	"github.com/eapolsniper/endpointbom/internal/discovery"
+)
+
+// findProjects returns the discovered project directories that contain the marker
+// file (e.g. go.mod or Cargo.lock). Discovery walks the disk once and is shared by
+// all local project scanners.
+func findProjects(ctx context.Context, cfg *config.Config, marker string) ([]discovery.Project, error) {
+	result, err := discovery.Discover(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return result.With(marker), nil
+}
+
+// localProjectProperties returns the properties every local project scanner sets
+func localProjectProperties(projectPath, source string) map[string]string {
+	return map[string]string{
+		"install_type": "local",
+		"project_path": projectPath,
+		"source":       source,
+	}
+}
@@ -0,0 +1,462 @@
+package packagemanagers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/eapolsniper/endpointbom/internal/license"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
+)
+
+// pythonDist is an installed Python distribution read from its *.dist-info or *.egg-info metadata
+type pythonDist struct {
+	Name              string
+	Version           string
+	Summary           string
+	License           string
+	LicenseExpression string
+	Classifiers       []string
+	Author            string
+	AuthorEmail       string
+	HomePage          string
+	ProjectURLs       map[string]string // Project-URL label -> URL
+	Requires          []pythonRequirement
+	Installer         string // Tool that installed the package (pip, uv, conda, ...)
+	DirectURL         *pythonDirectURL
+	RecordFiles       int    // Number of files listed in RECORD
+	MetadataPath      string // Path of the dist-info/egg-info directory
+}
+
+// pythonRequirement is a single Requires-Dist entry
+type pythonRequirement struct {
+	Name      string
+	Specifier string
+	Marker    string
+	Raw       string
+}
+
+// pythonDirectURL is the PEP 610 direct_url.json written for non-index installs
+type pythonDirectURL struct {
+	URL     string `json:"url"`
+	DirInfo *struct {
+		Editable bool `json:"editable"`
+	} `json:"dir_info"`
+	VCSInfo *struct {
+		VCS               string `json:"vcs"`
+		CommitID          string `json:"commit_id"`
+		RequestedRevision string `json:"requested_revision"`
+	} `json:"vcs_info"`
+	ArchiveInfo *struct {
+		Hash string `json:"hash"`
+	} `json:"archive_info"`
+}
+
+// requirementNameRegex matches the distribution name at the start of a requirement
+var requirementNameRegex = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*(.*)$`)
+
+var pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizePythonName applies PEP 503 normalization so "Foo_Bar" and "foo-bar" match
+func normalizePythonName(name string) string {
+	return strings.ToLower(pythonNameSeparators.ReplaceAllString(name, "-"))
+}
+
+// findSitePackages returns the site-packages directories inside a Python prefix
+// (a virtual environment, conda environment or interpreter installation)
+func findSitePackages(prefix string) []string {
+	patterns := []string{
+		filepath.Join(prefix, "lib", "python*", "site-packages"),
+		filepath.Join(prefix, "lib64", "python*", "site-packages"),
+		filepath.Join(prefix, "Lib", "site-packages"), // Windows
+		filepath.Join(prefix, "lib", "site-packages"),
+	}
+
+	return globDirs(patterns)
+}
+
+// globDirs expands glob patterns into existing directories, skipping paths that
+// resolve to a directory already returned (e.g. lib64 symlinked to lib)
+func globDirs(patterns []string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			resolved := match
+			if real, err := filepath.EvalSymlinks(match); err == nil {
+				resolved = real
+			}
+			if seen[resolved] {
+				continue
+			}
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				seen[resolved] = true
+				dirs = append(dirs, match)
+			}
+		}
+	}
+
+	return dirs
+}
+
+// readSitePackages reads every installed distribution in a site-packages directory
+func readSitePackages(sitePackages string) ([]pythonDist, error) {
+	entries, err := os.ReadDir(sitePackages)
+	if err != nil {
+		return nil, err
+	}
+
+	var dists []pythonDist
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(sitePackages, name)
+
+		switch {
+		case strings.HasSuffix(name, ".dist-info") && entry.IsDir():
+			if dist, ok := readDistInfo(path, "METADATA"); ok {
+				dists = append(dists, dist)
+			}
+		case strings.HasSuffix(name, ".egg-info"):
+			// egg-info can be a directory containing PKG-INFO or a PKG-INFO file itself
+			if entry.IsDir() {
+				if dist, ok := readDistInfo(path, "PKG-INFO"); ok {
+					dists = append(dists, dist)
+				}
+			} else if dist, ok := readMetadataFile(path); ok {
+				dists = append(dists, dist)
+			}
+		}
+	}
+
+	return dists, nil
+}
+
+// readDistInfo reads a dist-info/egg-info directory and its auxiliary files
+func readDistInfo(dir string, metadataFile string) (pythonDist, bool) {
+	dist, ok := readMetadataFile(filepath.Join(dir, metadataFile))
+	if !ok {
+		return dist, false
+	}
+	dist.MetadataPath = dir
+
+	if data, err := os.ReadFile(filepath.Join(dir, "INSTALLER")); err == nil {
+		dist.Installer = strings.TrimSpace(string(data))
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "direct_url.json")); err == nil {
+		var directURL pythonDirectURL
+		if err := json.Unmarshal(data, &directURL); err == nil {
+			dist.DirectURL = &directURL
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "RECORD")); err == nil {
+		dist.RecordFiles = bytes.Count(data, []byte("\n"))
+	}
+
+	// egg-info keeps requirements in requires.txt rather than the metadata headers
+	if len(dist.Requires) == 0 {
+		if data, err := os.ReadFile(filepath.Join(dir, "requires.txt")); err == nil {
+			dist.Requires = parseRequiresTxt(string(data))
+		}
+	}
+
+	return dist, true
+}
+
+// readMetadataFile parses the RFC 822 style headers of a METADATA or PKG-INFO file
+func readMetadataFile(path string) (pythonDist, bool) {
+	var dist pythonDist
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return dist, false
+	}
+
+	var lastKey string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Headers end at the first blank line; the rest is the long description
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+
+		// Continuation lines (used by multi-line License fields)
+		if line[0] == ' ' || line[0] == '\t' {
+			if lastKey == "License" {
+				dist.License += "\n" + strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "|"))
+			}
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		lastKey = key
+
+		switch key {
+		case "Name":
+			dist.Name = value
+		case "Version":
+			dist.Version = value
+		case "Summary":
+			dist.Summary = value
+		case "License":
+			dist.License = value
+		case "License-Expression":
+			dist.LicenseExpression = value
+		case "Classifier":
+			dist.Classifiers = append(dist.Classifiers, value)
+		case "Author":
+			dist.Author = value
+		case "Author-email":
+			dist.AuthorEmail = value
+		case "Home-page":
+			dist.HomePage = value
+		case "Project-URL":
+			// "Label, https://..."
+			if label, url, found := strings.Cut(value, ","); found {
+				if dist.ProjectURLs == nil {
+					dist.ProjectURLs = make(map[string]string)
+				}
+				dist.ProjectURLs[strings.TrimSpace(label)] = strings.TrimSpace(url)
+			}
+		case "Requires-Dist":
+			if req, ok := parsePythonRequirement(value); ok {
+				dist.Requires = append(dist.Requires, req)
+			}
+		}
+	}
+
+	return dist, dist.Name != ""
+}
+
+// parsePythonRequirement parses a PEP 508 requirement such as
+// `requests[socks] (>=2.0) ; python_version < "3.8"`
+func parsePythonRequirement(value string) (pythonRequirement, bool) {
+	req := pythonRequirement{Raw: value}
+
+	spec := value
+	if idx := strings.Index(value, ";"); idx != -1 {
+		spec = value[:idx]
+		req.Marker = strings.TrimSpace(value[idx+1:])
+	}
+
+	matches := requirementNameRegex.FindStringSubmatch(spec)
+	if matches == nil {
+		return req, false
+	}
+
+	req.Name = matches[1]
+	req.Specifier = strings.Trim(strings.TrimSpace(matches[3]), "()")
+	return req, true
+}
+
+// parseRequiresTxt parses an egg-info requires.txt, where [section] headers mark extras
+func parseRequiresTxt(content string) []pythonRequirement {
+	var reqs []pythonRequirement
+	marker := ""
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			section := strings.Trim(line, "[]")
+			extra, condition, _ := strings.Cut(section, ":")
+			switch {
+			case extra != "" && condition != "":
+				marker = `extra == "` + extra + `" and ` + condition
+			case extra != "":
+				marker = `extra == "` + extra + `"`
+			default:
+				marker = condition
+			}
+			continue
+		}
+		if req, ok := parsePythonRequirement(line); ok {
+			req.Marker = marker
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs
+}
+
+// license returns the distribution's declared license, preferring the PEP 639
+// License-Expression, then a short License field, then trove classifiers
+func (d pythonDist) license() string {
+	if d.LicenseExpression != "" {
+		return d.LicenseExpression
+	}
+
+	// Some packages paste the full license text into License; only use it when it
+	// looks like a name
+	declared := strings.TrimSpace(d.License)
+	if declared != "" && declared != "UNKNOWN" && !strings.Contains(declared, "\n") && len(declared) <= 100 {
+		return declared
+	}
+
+	var names []string
+	for _, classifier := range d.Classifiers {
+		if !strings.HasPrefix(classifier, "License ::") {
+			continue
+		}
+		parts := strings.Split(classifier, "::")
+		name := strings.TrimSpace(parts[len(parts)-1])
+		if name != "" && name != "OSI Approved" {
+			names = append(names, name)
+		}
+	}
+	return license.Join(names)
+}
+
+// author returns the distribution's author. Only the names are taken from
+// Author-email ("Jane Doe <jane@example.com>, ..."); addresses are not collected.
+func (d pythonDist) author() string {
+	if author := strings.TrimSpace(d.Author); author != "" && !strings.Contains(author, "\n") {
+		return author
+	}
+
+	var names []string
+	for _, entry := range strings.Split(d.AuthorEmail, ",") {
+		name, _, _ := strings.Cut(entry, "<")
+		name = strings.Trim(strings.TrimSpace(name), `"`)
+		if name != "" && !strings.Contains(name, "@") {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// pythonURLTypes maps common Project-URL labels, compared in lowercase without
+// spaces, dashes or underscores, to external reference types
+var pythonURLTypes = map[string]string{
+	"homepage":      scanners.RefWebsite,
+	"home":          scanners.RefWebsite,
+	"website":       scanners.RefWebsite,
+	"source":        scanners.RefVCS,
+	"sourcecode":    scanners.RefVCS,
+	"code":          scanners.RefVCS,
+	"repository":    scanners.RefVCS,
+	"github":        scanners.RefVCS,
+	"gitlab":        scanners.RefVCS,
+	"issues":        scanners.RefIssueTracker,
+	"issuetracker":  scanners.RefIssueTracker,
+	"bugtracker":    scanners.RefIssueTracker,
+	"bugreports":    scanners.RefIssueTracker,
+	"tracker":       scanners.RefIssueTracker,
+	"documentation": scanners.RefDocumentation,
+	"docs":          scanners.RefDocumentation,
+}
+
+// addExternalRefs records the Home-page and the recognized Project-URL entries
+func (d pythonDist) addExternalRefs(comp *scanners.Component) {
+	comp.AddExternalRef(scanners.RefWebsite, d.HomePage)
+
+	labels := make([]string, 0, len(d.ProjectURLs))
+	for label := range d.ProjectURLs {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	normalize := strings.NewReplacer(" ", "", "-", "", "_", "")
+	for _, label := range labels {
+		if refType, ok := pythonURLTypes[normalize.Replace(strings.ToLower(label))]; ok {
+			comp.AddExternalRef(refType, d.ProjectURLs[label])
+		}
+	}
+}
+
+// isOptional reports whether a requirement only applies when an extra is requested
+func (r pythonRequirement) isOptional() bool {
+	return strings.Contains(r.Marker, "extra ==") || strings.Contains(r.Marker, "extra==")
+}
+
+// buildPythonComponents converts distributions from one environment into components.
+// Requires-Dist entries are linked to other distributions in the same environment;
+// requirements gated behind extras are recorded but not linked. extraProps are
+// copied onto every component.
+func buildPythonComponents(dists []pythonDist, location string, extraProps map[string]string) []scanners.Component {
+	nodes := make(map[string]scanners.Component)
+	edges := make(map[string][]string)
+	var order []string
+
+	for _, dist := range dists {
+		comp := scanners.Component{
+			Type:           "library",
+			Name:           dist.Name,
+			Version:        dist.Version,
+			Description:    dist.Summary,
+			PackageManager: "pip",
+			Location:       location,
+			Properties:     make(map[string]string),
+		}
+
+		for key, value := range extraProps {
+			comp.Properties[key] = value
+		}
+		if dist.MetadataPath != "" {
+			comp.Properties["metadata_path"] = dist.MetadataPath
+		}
+		comp.License = dist.license()
+		comp.Author = dist.author()
+		dist.addExternalRefs(&comp)
+		if dist.Installer != "" {
+			comp.Properties["installer"] = dist.Installer
+		}
+		if dist.RecordFiles > 0 {
+			comp.Properties["installed_files"] = fmt.Sprintf("%d", dist.RecordFiles)
+		}
+		if dist.DirectURL != nil {
+			comp.Properties["install_source"] = dist.DirectURL.URL
+			if dist.DirectURL.DirInfo != nil && dist.DirectURL.DirInfo.Editable {
+				comp.Properties["editable"] = "true"
+			}
+			if vcs := dist.DirectURL.VCSInfo; vcs != nil {
+				comp.Properties["vcs"] = vcs.VCS
+				if vcs.CommitID != "" {
+					comp.Properties["vcs_commit"] = vcs.CommitID
+				}
+			}
+		}
+		if len(dist.Requires) > 0 {
+			var raw []string
+			for _, req := range dist.Requires {
+				raw = append(raw, req.Raw)
+			}
+			comp.Properties["requires"] = strings.Join(raw, ", ")
+		}
+
+		key := normalizePythonName(dist.Name)
+		if _, exists := nodes[key]; exists {
+			continue
+		}
+		nodes[key] = comp
+		order = append(order, key)
+
+		for _, req := range dist.Requires {
+			if !req.isOptional() {
+				edges[key] = append(edges[key], normalizePythonName(req.Name))
+			}
+		}
+		sort.Strings(edges[key])
+	}
+
+	sort.Strings(order)
+	return scanners.ExpandDependencyGraph(nodes, edges, order)
+}
@@ -1,8 +1,14 @@
 package packagemanagers
 
 import (
+	"encoding/json"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+
+	"github.com/eapolsniper/endpointbom/internal/license"
+	"github.com/eapolsniper/endpointbom/internal/scanners"
 )
 
 // isCommandAvailable checks if a command is available in PATH
@@ -29,3 +35,60 @@ func splitAtLastChar(s string, sep rune) []string {
 	return []string{s[:idx], s[idx+1:]}
 }
 
+
+// appendUnreachedRoots appends every key in all that cannot be reached from roots,
+// so that nodes missing from the dependency graph still appear at the top level
+func appendUnreachedRoots(roots []string, edges map[string][]string, all []string) []string {
+	reached := make(map[string]bool)
+	var mark func(key string)
+	mark = func(key string) {
+		if reached[key] {
+			return
+		}
+		reached[key] = true
+		for _, dep := range edges[key] {
+			mark(dep)
+		}
+	}
+
+	for _, root := range roots {
+		mark(root)
+	}
+	for _, key := range all {
+		if !reached[key] {
+			roots = append(roots, key)
+			mark(key)
+		}
+	}
+	return roots
+}
+
+// applyPackageJSON sets a component's license, author and external references
+// from the package.json of its installed copy in dir. A package.json for a
+// different version (e.g. a hoisted copy of another release) is ignored, and a
+// license the component already has is kept.
+func applyPackageJSON(comp *scanners.Component, dir string) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return
+	}
+
+	var manifest struct {
+		Version  string          `json:"version"`
+		License  json.RawMessage `json:"license"`
+		Licenses json.RawMessage `json:"licenses"`
+
+		scanners.PackageJSONMetadata
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return
+	}
+	if comp.Version != "" && manifest.Version != comp.Version {
+		return
+	}
+
+	if comp.License == "" {
+		comp.License = license.FromPackageJSON(manifest.License, manifest.Licenses)
+	}
+	manifest.ApplyTo(comp)
+}
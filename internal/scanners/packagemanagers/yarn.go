@@ -1,9 +1,11 @@
 package packagemanagers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/eapolsniper/endpointbom/internal/config"
 	"github.com/eapolsniper/endpointbom/internal/scanners"
@@ -17,6 +19,11 @@ func (s *YarnScanner) Name() string {
 }
 
 func (s *YarnScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
+	return s.ScanContext(context.Background(), cfg)
+}
+
+// ScanContext performs the yarn scan, stopping early if ctx is cancelled
+func (s *YarnScanner) ScanContext(ctx context.Context, cfg *config.Config) ([]scanners.Component, error) {
 	if cfg.IsScannerDisabled("yarn") {
 		return nil, nil
 	}
@@ -25,19 +32,26 @@ func (s *YarnScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 		if cfg.Debug {
 			fmt.Println("yarn not found, skipping")
 		}
-		return nil, nil
+		return nil, scanners.NotInstalled("yarn")
 	}
 
 	var components []scanners.Component
 
 	// Get global packages
-	cmd := exec.Command("yarn", "global", "list", "--json")
-	output, err := cmd.Output()
+	output, err := RunCommand(ctx, "", "yarn", "global", "list", "--json")
 	if err != nil {
-		if cfg.Debug {
-			fmt.Printf("yarn scan error: %v\n", err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("yarn scan failed: %w", err)
+	}
+
+	// Licenses come from the installed packages in yarn's global directory
+	var modulesDir string
+	if dirOutput, err := RunCommand(ctx, "", "yarn", "global", "dir"); err == nil {
+		if dir := strings.TrimSpace(string(dirOutput)); dir != "" {
+			modulesDir = filepath.Join(dir, "node_modules")
 		}
-		return nil, nil
 	}
 
 	// Yarn outputs multiple JSON objects per line
@@ -53,6 +67,9 @@ func (s *YarnScanner) Scan(cfg *config.Config) ([]scanners.Component, error) {
 				comp := parseYarnPackage(tree)
 				if comp.Name != "" {
 					comp.PackageManager = "yarn"
+					if modulesDir != "" {
+						applyYarnPackageJSON(&comp, modulesDir)
+					}
 					components = append(components, comp)
 				}
 			}
@@ -79,7 +96,7 @@ func parseYarnPackage(tree yarnTree) scanners.Component {
 	parts := splitAtLastChar(tree.Name, '@')
 	name := tree.Name
 	version := ""
-	
+
 	if len(parts) == 2 {
 		name = parts[0]
 		version = parts[1]
@@ -104,3 +121,12 @@ func parseYarnPackage(tree yarnTree) scanners.Component {
 	return comp
 }
 
+// applyYarnPackageJSON reads the license, author and URLs of a package and its
+// dependencies from the global node_modules directory, where yarn hoists every
+// package it can
+func applyYarnPackageJSON(comp *scanners.Component, modulesDir string) {
+	applyPackageJSON(comp, filepath.Join(modulesDir, filepath.FromSlash(comp.Name)))
+	for i := range comp.Dependencies {
+		applyYarnPackageJSON(&comp.Dependencies[i], modulesDir)
+	}
+}
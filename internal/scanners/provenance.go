@@ -0,0 +1,76 @@
+package scanners
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Scanner statuses recorded in scan provenance
+const (
+	StatusOK           = "ok"            // Scanner ran to completion
+	StatusSkipped      = "skipped"       // Scanner does not apply to this endpoint (e.g. wrong OS)
+	StatusDisabled     = "disabled"      // Scanner was disabled by config or CLI flag
+	StatusError        = "error"         // Scanner failed or timed out
+	StatusNotInstalled = "not-installed" // The tool the scanner relies on is not installed
+)
+
+var (
+	// ErrNotInstalled is returned by scanners whose package manager or tool is not installed
+	ErrNotInstalled = errors.New("not installed")
+
+	// ErrSkipped is returned by scanners that do not apply to this endpoint
+	ErrSkipped = errors.New("skipped")
+)
+
+// NotInstalled returns an error reporting that a required tool is missing
+func NotInstalled(tool string) error {
+	return fmt.Errorf("%s not found: %w", tool, ErrNotInstalled)
+}
+
+// Skipped returns an error reporting why a scanner did not run
+func Skipped(reason string) error {
+	return fmt.Errorf("%s: %w", reason, ErrSkipped)
+}
+
+// ScannerRecord records what happened to a single scanner during a scan.
+// It lets consumers tell "no packages found" apart from "never scanned".
+type ScannerRecord struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Components int    `json:"components"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// NewScannerRecord builds a provenance record from a runner result
+func NewScannerRecord(run RunResult) ScannerRecord {
+	record := ScannerRecord{
+		Name:       run.Scanner.Name(),
+		Status:     StatusOK,
+		Components: len(run.Components),
+		DurationMS: run.Duration.Milliseconds(),
+	}
+
+	if run.Err != nil {
+		record.Error = run.Err.Error()
+		switch {
+		case errors.Is(run.Err, ErrNotInstalled):
+			record.Status = StatusNotInstalled
+		case errors.Is(run.Err, ErrSkipped):
+			record.Status = StatusSkipped
+		default:
+			record.Status = StatusError
+		}
+		record.Components = 0
+	}
+
+	return record
+}
+
+// DisabledRecord builds a provenance record for a scanner that was not run
+func DisabledRecord(name string) ScannerRecord {
+	return ScannerRecord{
+		Name:   name,
+		Status: StatusDisabled,
+	}
+}
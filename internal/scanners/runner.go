@@ -0,0 +1,110 @@
+package scanners
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+)
+
+// RunResult records the outcome of a single scanner run
+type RunResult struct {
+	Scanner    Scanner       // Scanner that produced this result
+	Components []Component   // Components found (nil on error or timeout)
+	Duration   time.Duration // Wall-clock time spent waiting for the scanner
+	TimedOut   bool          // True if the scanner exceeded its timeout
+	Err        error         // Error returned by the scanner, or the timeout error
+}
+
+// RunScanners runs scanners concurrently using a bounded worker pool.
+// Each scanner gets its own timeout from the config. Results are returned in
+// the same order as the input slice so callers can process them deterministically.
+// Only context-aware scanners can be run, so that a scanner that times out
+// stops instead of running on in the background while the scan is assembled.
+func RunScanners(ctx context.Context, cfg *config.Config, list []ContextScanner) []RunResult {
+	results := make([]RunResult, len(list))
+
+	workers := cfg.MaxParallelScanners
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(list) {
+		workers = len(list)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				results[idx] = runScanner(ctx, cfg, list[idx])
+			}
+		}()
+	}
+
+	for idx := range list {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// runScanner runs a single scanner and waits for it to finish or time out
+func runScanner(ctx context.Context, cfg *config.Config, scanner ContextScanner) RunResult {
+	result := RunResult{Scanner: scanner}
+	start := time.Now()
+
+	timeout := cfg.ScannerTimeout(scanner.Name())
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type scanOutput struct {
+		components []Component
+		err        error
+	}
+
+	// Buffered so the scanner goroutine can always exit, even if we stopped waiting.
+	// The scanner sees the same ctx and stops at its next cancellation check.
+	done := make(chan scanOutput, 1)
+	go func() {
+		// A panic in one scanner, e.g. a parser given a corrupt database,
+		// fails that scanner rather than the whole scan
+		defer func() {
+			if r := recover(); r != nil {
+				if cfg.Debug {
+					fmt.Printf("Scanner %s panicked: %v\n%s", scanner.Name(), r, debug.Stack())
+				}
+				done <- scanOutput{err: fmt.Errorf("scanner panicked: %v", r)}
+			}
+		}()
+		components, err := scanner.ScanContext(ctx, cfg)
+		done <- scanOutput{components: components, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		result.Components = out.components
+		result.Err = out.err
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			result.TimedOut = true
+			result.Err = fmt.Errorf("timed out after %s", timeout)
+		} else {
+			result.Err = ctx.Err()
+		}
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
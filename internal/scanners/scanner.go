@@ -1,18 +1,46 @@
 package scanners
 
-import "github.com/eapolsniper/endpointbom/internal/config"
+import (
+	"context"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
+)
 
 // Component represents a discovered software component
 type Component struct {
-	Type            string            // application, library, etc.
-	Name            string            // Component name
-	Version         string            // Version string
-	Group           string            // Group/namespace (optional)
-	Description     string            // Description (optional)
-	PackageManager  string            // Source package manager (npm, pip, etc.)
-	Location        string            // Installation location
-	Dependencies    []Component       // Transitive dependencies
-	Properties      map[string]string // Additional properties
+	Type           string            // application, library, etc.
+	Name           string            // Component name
+	Version        string            // Version string
+	Group          string            // Group/namespace (optional)
+	Description    string            // Description (optional)
+	PackageManager string            // Source package manager (npm, pip, etc.)
+	PackageURL     string            // Package URL (optional; derived from PackageManager when empty)
+	Location       string            // Installation location
+	License        string            // Declared license from package metadata (optional; normalized to SPDX in the SBOM)
+	Supplier       string            // Organization that supplies the component: vendor, publisher or distro maintainer (optional)
+	Author         string            // Person or organization that wrote the component (optional)
+	Publisher      string            // Publisher of record, e.g. the marketplace publisher of an extension (optional)
+	ExternalRefs   []ExternalRef     // Website, VCS, distribution, issue tracker and marketplace URLs (optional)
+	Dependencies   []Component       // Transitive dependencies
+	Endpoints      []string          // Endpoint URLs of a "service" component, e.g. a remote MCP server (optional)
+	Properties     map[string]string // Additional properties
+	Hashes         map[string]string // Hex digests keyed by CycloneDX algorithm (SHA-256, SHA-512, ...)
+	Identity       *Identity         // How the component was identified (optional)
+	Events         []Event           // Install history found by historical scanners (optional)
+}
+
+// Identity records how a scanner identified a component (CycloneDX evidence.identity)
+type Identity struct {
+	Field      string  // Component field the evidence supports: name (default), version, purl or hash
+	Technique  string  // CycloneDX technique: manifest-analysis, filename, hash-comparison, ...
+	Confidence float32 // 0 (no confidence) to 1 (certain)
+	Value      string  // What was examined, e.g. the manifest path
+}
+
+// ExternalRef is a URL related to a component (CycloneDX externalReferences)
+type ExternalRef struct {
+	Type string // CycloneDX reference type: website, vcs, distribution, issue-tracker, documentation, ...
+	URL  string
 }
 
 // Scanner is the interface that all scanners must implement
@@ -24,11 +52,28 @@ type Scanner interface {
 	Scan(cfg *config.Config) ([]Component, error)
 }
 
+// ContextScanner is implemented by scanners that support cancellation. The
+// runner only accepts ContextScanners and calls ScanContext, so that timeouts
+// and signals stop in-flight work (including child processes) instead of
+// abandoning it.
+type ContextScanner interface {
+	Scanner
+
+	// ScanContext performs the scan, stopping early when ctx is cancelled
+	ScanContext(ctx context.Context, cfg *config.Config) ([]Component, error)
+}
+
 // ScanResult contains the results of all scans
 type ScanResult struct {
 	Applications      []Component
 	PackageManagers   []Component
 	IDEExtensions     []Component
 	BrowserExtensions []Component
-}
 
+	// Scanners records the outcome of every known scanner, including disabled ones
+	Scanners []ScannerRecord
+
+	// Events is the install history of every package found by historical
+	// scanners, oldest first, including packages no longer installed
+	Events []Event
+}
@@ -0,0 +1,54 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// stampLayout is the time stamp in output file names, e.g. 20251214-112345-CST
+const stampLayout = "20060102-150405-MST"
+
+// Session is one run of the scanner. The SBOMs, the scan archive and its
+// metadata all take their names, times and ID from the same session, so the
+// artifacts of a run can always be matched up.
+type Session struct {
+	ID       string    // Random UUID, written into every BOM's metadata
+	Hostname string    // Prefix of every output file name
+	Started  time.Time // When the scan started
+	Finished time.Time // When the scanners finished (zero while running)
+	Files    []string  // Names of the SBOM files written to the output directory
+
+	Redactions int // Secrets removed from the scan results before output
+}
+
+// New starts a session for hostname at the given time
+func New(hostname string, started time.Time) *Session {
+	return &Session{
+		ID:       uuid.NewString(),
+		Hostname: hostname,
+		Started:  started,
+	}
+}
+
+// Finish records the end of the scan
+func (s *Session) Finish() {
+	s.Finished = time.Now()
+}
+
+// Stamp returns the start time as used in file names
+func (s *Session) Stamp() string {
+	return s.Started.Format(stampLayout)
+}
+
+// Filename returns the name of one of the session's output files, such as
+// "host.20251214-112345-CST.package-managers.cdx.json"
+func (s *Session) Filename(kind, extension string) string {
+	return fmt.Sprintf("%s.%s.%s.%s", s.Hostname, s.Stamp(), kind, extension)
+}
+
+// AddFile records a file written to the output directory
+func (s *Session) AddFile(name string) {
+	s.Files = append(s.Files, name)
+}
@@ -11,6 +11,8 @@ import (
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/eapolsniper/endpointbom/internal/config"
 )
 
 // Info contains system information
@@ -173,6 +175,31 @@ func getWindowsLoggedInUsers() ([]string, error) {
 	return result, nil
 }
 
+// HomeDirs returns the home directories to scan: the current user's, plus every
+// other user's when cfg.ScanAllUsers is set
+func HomeDirs(cfg *config.Config) []string {
+	var homes []string
+	seen := make(map[string]bool)
+
+	if home, err := os.UserHomeDir(); err == nil {
+		homes = append(homes, home)
+		seen[home] = true
+	}
+
+	if cfg.ScanAllUsers {
+		if profiles, err := GetAllUserProfiles(); err == nil {
+			for _, profile := range profiles {
+				if !seen[profile] {
+					seen[profile] = true
+					homes = append(homes, profile)
+				}
+			}
+		}
+	}
+
+	return homes
+}
+
 // GetAllUserProfiles returns all user home directories
 func GetAllUserProfiles() ([]string, error) {
 	switch runtime.GOOS {